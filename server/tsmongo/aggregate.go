@@ -0,0 +1,316 @@
+package tsmongo
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+)
+
+// Window is the size of the time windows data is aggregated into.
+type Window int
+
+// Enumerates the supported aggregation windows. Days start at midnight and weeks on Monday
+// (ISO 8601), both in the timezone of the query.
+const (
+	HourWindow Window = iota
+	DayWindow
+	WeekWindow
+)
+
+// AggregateFunc is a function computed over the samples of a window.
+type AggregateFunc string
+
+// Enumerates the supported aggregate functions.
+const (
+	Min   AggregateFunc = "min"
+	Max   AggregateFunc = "max"
+	Mean  AggregateFunc = "mean"
+	Count AggregateFunc = "count"
+)
+
+// AggregateQuery specifies how a series should be aggregated.
+type AggregateQuery struct {
+	Start    time.Time
+	Finish   time.Time
+	Window   Window
+	Location *time.Location  // Timezone windows are aligned to. Defaults to UTC.
+	Funcs    []AggregateFunc // Functions to compute. Defaults to all.
+}
+
+// AggregateBucket holds the aggregated samples of a window. Only the requested functions are
+// filled in.
+type AggregateBucket struct {
+	Start time.Time `json:"start"` // Beginning of the window, in the query timezone.
+	Min   float64   `json:"min,omitempty"`
+	Max   float64   `json:"max,omitempty"`
+	Mean  float64   `json:"mean,omitempty"`
+	Count int       `json:"count,omitempty"`
+}
+
+// Aggregate aggregates the samples of the series within the query range. The path selects the
+// numeric value to aggregate within stored documents (e.g. "temp" for weather), empty for
+// numeric series. The aggregation runs server-side, through the aggregation pipeline (which
+// requires mongo 4.0+), and buckets are returned sorted by descending window start.
+func (s *Session) Aggregate(series Series, path string, q AggregateQuery) ([]AggregateBucket, error) {
+	return s.AggregateContext(context.Background(), series, path, q)
+}
+
+// AggregateContext is like Aggregate, but honors the context deadline and cancellation.
+func (s *Session) AggregateContext(ctx context.Context, series Series, path string, q AggregateQuery) ([]AggregateBucket, error) {
+	tz, funcs, err := q.validate()
+	if err != nil {
+		return nil, err
+	}
+	value := "$s.v"
+	if path != "" {
+		value += "." + path
+	}
+	date := func(op string) bson.M {
+		return bson.M{op: bson.M{"date": "$t", "timezone": tz}}
+	}
+	var id bson.M
+	switch q.Window {
+	case HourWindow:
+		id = bson.M{"y": date("$year"), "m": date("$month"), "d": date("$dayOfMonth"), "h": date("$hour")}
+	case DayWindow:
+		id = bson.M{"y": date("$year"), "m": date("$month"), "d": date("$dayOfMonth")}
+	case WeekWindow:
+		id = bson.M{"y": date("$isoWeekYear"), "w": date("$isoWeek")}
+	}
+	group := bson.M{"_id": id}
+	for _, f := range funcs {
+		switch f {
+		case Min:
+			group["min"] = bson.M{"$min": "$v"}
+		case Max:
+			group["max"] = bson.M{"$max": "$v"}
+		case Mean:
+			group["mean"] = bson.M{"$avg": "$v"}
+		case Count:
+			group["count"] = bson.M{"$sum": 1}
+		}
+	}
+	pipeline := []bson.M{
+		{"$match": bson.M{
+			typeField:           series.Field,
+			roomField:           series.Room,
+			timestampIndexField: bson.M{"$gte": hourUTC(q.Start), "$lte": q.Finish.In(time.UTC)},
+		}},
+		// Buckets without samples have their hourly value considered as a sample.
+		{"$project": bson.M{
+			"h": "$" + timestampIndexField,
+			"m": bson.M{"$objectToArray": bson.M{"$ifNull": []interface{}{"$" + samplesField, bson.M{"0": bson.M{"0": "$" + valueField}}}}},
+		}},
+		{"$unwind": "$m"},
+		{"$project": bson.M{"h": 1, "min": bson.M{"$toInt": "$m.k"}, "s": bson.M{"$objectToArray": "$m.v"}}},
+		{"$unwind": "$s"},
+		{"$project": bson.M{
+			"t": bson.M{"$add": []interface{}{
+				"$h",
+				bson.M{"$multiply": []interface{}{"$min", 60000}},
+				bson.M{"$multiply": []interface{}{bson.M{"$toInt": "$s.k"}, 1000}},
+			}},
+			"v": value,
+		}},
+		{"$match": bson.M{
+			"t": bson.M{"$gte": q.Start.In(time.UTC), "$lte": q.Finish.In(time.UTC)},
+			"v": bson.M{"$type": "number"},
+		}},
+		{"$group": group},
+	}
+	var res []struct {
+		ID struct {
+			Year  int `bson:"y"`
+			Month int `bson:"m"`
+			Day   int `bson:"d"`
+			Hour  int `bson:"h"`
+			Week  int `bson:"w"`
+		} `bson:"_id"`
+		Min   float64 `bson:"min"`
+		Max   float64 `bson:"max"`
+		Mean  float64 `bson:"mean"`
+		Count int     `bson:"count"`
+	}
+	err = s.run(ctx, func(col *mgo.Collection) error {
+		if err := col.Pipe(pipeline).AllowDiskUse().All(&res); err != nil {
+			return fmt.Errorf("Error aggregating tsmongo within range(%v,%v): %q", q.Start, q.Finish, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	loc := q.location()
+	ret := make([]AggregateBucket, len(res))
+	for i, r := range res {
+		var start time.Time
+		if q.Window == WeekWindow {
+			start = isoWeekStart(r.ID.Year, r.ID.Week, loc)
+		} else {
+			start = time.Date(r.ID.Year, time.Month(r.ID.Month), r.ID.Day, r.ID.Hour, 0, 0, 0, loc)
+		}
+		ret[i] = AggregateBucket{Start: start, Min: r.Min, Max: r.Max, Mean: r.Mean, Count: r.Count}
+	}
+	sortBucketsDesc(ret)
+	return ret, nil
+}
+
+// Aggregate aggregates the samples of the series within the query range. Buckets are returned
+// sorted by descending window start.
+func (m *MemStore) Aggregate(series Series, path string, q AggregateQuery) ([]AggregateBucket, error) {
+	return m.AggregateContext(context.Background(), series, path, q)
+}
+
+// AggregateContext is like Aggregate, but returns right away if the context is done.
+func (m *MemStore) AggregateContext(ctx context.Context, series Series, path string, q AggregateQuery) ([]AggregateBucket, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	_, funcs, err := q.validate()
+	if err != nil {
+		return nil, err
+	}
+	trs, err := m.QuerySamples(series, q.Start, q.Finish)
+	if err != nil {
+		return nil, err
+	}
+	loc := q.location()
+	var ret []AggregateBucket
+	sums := make(map[time.Time]float64)
+	index := make(map[time.Time]int)
+	for _, tr := range trs {
+		v, ok := numericValue(tr.Value, path)
+		if !ok {
+			continue
+		}
+		start := q.Window.start(tr.Timestamp.In(loc))
+		i, ok := index[start]
+		if !ok {
+			i = len(ret)
+			index[start] = i
+			ret = append(ret, AggregateBucket{Start: start, Min: v, Max: v})
+		}
+		b := &ret[i]
+		b.Min = math.Min(b.Min, v)
+		b.Max = math.Max(b.Max, v)
+		b.Count++
+		sums[start] += v
+	}
+	for i := range ret {
+		b := ret[i]
+		ret[i] = AggregateBucket{Start: b.Start}
+		for _, f := range funcs {
+			switch f {
+			case Min:
+				ret[i].Min = b.Min
+			case Max:
+				ret[i].Max = b.Max
+			case Mean:
+				ret[i].Mean = sums[b.Start] / float64(b.Count)
+			case Count:
+				ret[i].Count = b.Count
+			}
+		}
+	}
+	sortBucketsDesc(ret)
+	return ret, nil
+}
+
+// Aggregate aggregates the samples of the series within the query range. Buckets are returned
+// sorted by descending window start.
+func (fs *FileStore) Aggregate(series Series, path string, q AggregateQuery) ([]AggregateBucket, error) {
+	return fs.AggregateContext(context.Background(), series, path, q)
+}
+
+// AggregateContext is like Aggregate, but returns right away if the context is done.
+func (fs *FileStore) AggregateContext(ctx context.Context, series Series, path string, q AggregateQuery) ([]AggregateBucket, error) {
+	mem, err := fs.sync(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return mem.AggregateContext(ctx, series, path, q)
+}
+
+// validate checks the query, returning the timezone name and the functions to compute.
+func (q AggregateQuery) validate() (string, []AggregateFunc, error) {
+	if q.Window < HourWindow || q.Window > WeekWindow {
+		return "", nil, fmt.Errorf("invalid aggregation window: %d", q.Window)
+	}
+	tz := q.location().String()
+	if tz == "Local" {
+		return "", nil, fmt.Errorf("invalid aggregation timezone: use time.LoadLocation instead of time.Local")
+	}
+	funcs := q.Funcs
+	if len(funcs) == 0 {
+		funcs = []AggregateFunc{Min, Max, Mean, Count}
+	}
+	for _, f := range funcs {
+		switch f {
+		case Min, Max, Mean, Count:
+		default:
+			return "", nil, fmt.Errorf("invalid aggregate function: %s", f)
+		}
+	}
+	return tz, funcs, nil
+}
+
+func (q AggregateQuery) location() *time.Location {
+	if q.Location == nil {
+		return time.UTC
+	}
+	return q.Location
+}
+
+// start returns the beginning of the window the time (already in the query timezone) belongs to.
+func (w Window) start(t time.Time) time.Time {
+	switch w {
+	case DayWindow:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	case WeekWindow:
+		y, wk := t.ISOWeek()
+		return isoWeekStart(y, wk, t.Location())
+	default:
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location())
+	}
+}
+
+// next returns the beginning of the window following the one beginning at start.
+func (w Window) next(start time.Time) time.Time {
+	switch w {
+	case DayWindow:
+		return w.start(start.AddDate(0, 0, 1))
+	case WeekWindow:
+		return w.start(start.AddDate(0, 0, 7))
+	default:
+		return w.start(start.Add(time.Hour))
+	}
+}
+
+// isoWeekStart returns the Monday which starts the ISO 8601 week. The 4th of January is always
+// in the first week of the year.
+func isoWeekStart(year, week int, loc *time.Location) time.Time {
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, loc)
+	offset := (int(jan4.Weekday()) + 6) % 7 // Days since Monday.
+	return jan4.AddDate(0, 0, (week-1)*7-offset)
+}
+
+// numericValue extracts the numeric value at the path of the stored value.
+func numericValue(v interface{}, path string) (float64, bool) {
+	if path != "" {
+		m, ok := v.(bson.M)
+		if !ok {
+			return 0, false
+		}
+		v = m[path]
+	}
+	return toFloat(v)
+}
+
+func sortBucketsDesc(bs []AggregateBucket) {
+	sort.Slice(bs, func(i, j int) bool { return bs[i].Start.After(bs[j].Start) })
+}
@@ -0,0 +1,86 @@
+package tsmongo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/danielfireman/temp-to-go/server/weather"
+)
+
+func TestAggregate(t *testing.T) {
+	loc, err := time.LoadLocation("America/Maceio")
+	if err != nil {
+		t.Skipf("timezone database not available: %q", err)
+	}
+	s := NewMemStore()
+	bs := NewBedroomService(s, DefaultRoom)
+	// Night of 2018-07-02 (Monday) in Maceio (UTC-3): 23h, 00:30, 01h.
+	night := time.Date(2018, 7, 2, 23, 0, 0, 0, loc)
+	bs.UpdateTemperature(night, 26)
+	bs.UpdateTemperature(night.Add(90*time.Minute), 28)
+	bs.UpdateTemperature(night.Add(90*time.Minute+10*time.Second), 30)
+	bs.UpdateTemperature(night.Add(2*time.Hour), 24)
+	// Sunday before.
+	bs.UpdateTemperature(night.Add(-24*time.Hour), 20)
+
+	day, err := bs.Aggregate(AggregateQuery{
+		Start:    night.Add(-48 * time.Hour),
+		Finish:   night.Add(48 * time.Hour),
+		Window:   DayWindow,
+		Location: loc,
+	})
+	if err != nil {
+		t.Fatalf("Aggregate: %q", err)
+	}
+	want := []AggregateBucket{
+		{Start: time.Date(2018, 7, 3, 0, 0, 0, 0, loc), Min: 24, Max: 30, Mean: (28 + 30 + 24) / 3.0, Count: 3},
+		{Start: time.Date(2018, 7, 2, 0, 0, 0, 0, loc), Min: 26, Max: 26, Mean: 26, Count: 1},
+		{Start: time.Date(2018, 7, 1, 0, 0, 0, 0, loc), Min: 20, Max: 20, Mean: 20, Count: 1},
+	}
+	checkBuckets(t, want, day)
+
+	week, err := bs.Aggregate(AggregateQuery{
+		Start:    night.Add(-48 * time.Hour),
+		Finish:   night.Add(48 * time.Hour),
+		Window:   WeekWindow,
+		Location: loc,
+		Funcs:    []AggregateFunc{Max, Count},
+	})
+	if err != nil {
+		t.Fatalf("Aggregate: %q", err)
+	}
+	want = []AggregateBucket{
+		{Start: time.Date(2018, 7, 2, 0, 0, 0, 0, loc), Max: 30, Count: 4},
+		{Start: time.Date(2018, 6, 25, 0, 0, 0, 0, loc), Max: 20, Count: 1},
+	}
+	checkBuckets(t, want, week)
+
+	ws := NewWeatherService(s)
+	ws.Update(weather.State{Timestamp: night, Temp: 22})
+	ws.Update(weather.State{Timestamp: night.Add(time.Hour), Temp: 21})
+	hour, err := ws.Aggregate(AggregateQuery{Start: night, Finish: night.Add(time.Hour), Window: HourWindow, Funcs: []AggregateFunc{Mean}})
+	if err != nil {
+		t.Fatalf("Aggregate: %q", err)
+	}
+	want = []AggregateBucket{
+		{Start: night.Add(time.Hour).In(time.UTC), Mean: 21},
+		{Start: night.In(time.UTC), Mean: 22},
+	}
+	checkBuckets(t, want, hour)
+
+	if _, err := bs.Aggregate(AggregateQuery{Window: DayWindow, Location: time.Local}); err == nil {
+		t.Errorf("Aggregate with time.Local want error, got nil")
+	}
+}
+
+func checkBuckets(t *testing.T, want, got []AggregateBucket) {
+	t.Helper()
+	if len(want) != len(got) {
+		t.Fatalf("want:%+v got:%+v", want, got)
+	}
+	for i := range want {
+		if !want[i].Start.Equal(got[i].Start) || want[i].Min != got[i].Min || want[i].Max != got[i].Max || want[i].Mean != got[i].Mean || want[i].Count != got[i].Count {
+			t.Errorf("[%d] want:%+v got:%+v", i, want[i], got[i])
+		}
+	}
+}
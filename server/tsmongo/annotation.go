@@ -0,0 +1,107 @@
+package tsmongo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const annotationField = "annotation"
+
+// MaxAnnotationSpan is the longest time range an annotation can be attached to.
+const MaxAnnotationSpan = 31 * 24 * time.Hour
+
+// ErrInvalidAnnotation is returned when an annotation has no text or an invalid time range.
+var ErrInvalidAnnotation = fmt.Errorf("Invalid annotation")
+
+// Annotation is a note about what happened in a room within a time range (e.g. "window open"
+// or "guests sleeping over"), which helps understanding the data.
+type Annotation struct {
+	Start  time.Time `json:"start"`
+	Finish time.Time `json:"finish"`
+	Text   string    `json:"text"`
+	Actor  string    `json:"actor,omitempty"`
+}
+
+// annotationDoc is the stored representation of an annotation, under its start.
+type annotationDoc struct {
+	Finish time.Time `bson:"finish"`
+	Text   string    `bson:"text"`
+	Actor  string    `bson:"actor,omitempty"`
+}
+
+// AnnotationService allows the user to annotate and get the annotations of a bedroom.
+type AnnotationService struct {
+	store  Store
+	series Series
+}
+
+// NewAnnotationService creates a new AnnotationService, which allows to interact with the
+// annotations of the specified room (DefaultRoom if empty).
+func NewAnnotationService(s Store, room string) *AnnotationService {
+	return &AnnotationService{s, roomSeries(annotationField, room)}
+}
+
+// Room returns the room the service is bound to.
+func (as *AnnotationService) Room() string {
+	return as.series.Room
+}
+
+// Annotate stores the annotation, with one second resolution. Annotations starting at the same
+// second replace each other. It returns ErrInvalidAnnotation if the text is empty, the finish is
+// before the start or the annotation spans more than MaxAnnotationSpan.
+func (as *AnnotationService) Annotate(a Annotation) error {
+	return as.AnnotateContext(context.Background(), a)
+}
+
+// AnnotateContext is like Annotate, but honors the context deadline and cancellation.
+func (as *AnnotationService) AnnotateContext(ctx context.Context, a Annotation) error {
+	text := strings.TrimSpace(a.Text)
+	if text == "" || a.Start.IsZero() || a.Finish.Before(a.Start) || a.Finish.Sub(a.Start) > MaxAnnotationSpan {
+		return ErrInvalidAnnotation
+	}
+	return as.store.UpsertSamplesContext(ctx, as.series, TSRecord{a.Start, annotationDoc{a.Finish.UTC().Truncate(time.Second), text, a.Actor}})
+}
+
+// Annotations returns the annotations overlapping the considered period, sorted by ascending
+// start.
+func (as *AnnotationService) Annotations(start time.Time, finish time.Time) ([]Annotation, error) {
+	return as.AnnotationsContext(context.Background(), start, finish)
+}
+
+// AnnotationsContext is like Annotations, but honors the context deadline and cancellation.
+func (as *AnnotationService) AnnotationsContext(ctx context.Context, start time.Time, finish time.Time) ([]Annotation, error) {
+	var ret []Annotation
+	err := as.store.StreamContext(ctx, as.series, start.Add(-MaxAnnotationSpan), finish, FullResolution, Ascending, func(tr TSRecord) error {
+		var doc annotationDoc
+		if err := decodeValue(tr.Value, &doc); err != nil {
+			return &DecodeError{as.series, tr.Timestamp, tr.Value, "annotation document"}
+		}
+		if doc.Finish.Before(start) {
+			return nil
+		}
+		ret = append(ret, Annotation{tr.Timestamp.Local(), doc.Finish.Local(), doc.Text, doc.Actor})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// Remove removes the annotation starting at t, keeping it in the audit trail of the series (see
+// AuditTrail). It returns ErrNotFound if there is no such annotation.
+func (as *AnnotationService) Remove(t time.Time, e Edit) error {
+	return as.RemoveContext(context.Background(), t, e)
+}
+
+// RemoveContext is like Remove, but honors the context deadline and cancellation.
+func (as *AnnotationService) RemoveContext(ctx context.Context, t time.Time, e Edit) error {
+	t = t.Truncate(time.Second)
+	n, err := DeleteRange(ctx, as.store, as.series, t, t, e)
+	if err == nil && n == 0 {
+		return ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,46 @@
+package tsmongo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAnnotations(t *testing.T) {
+	s := NewMemStore()
+	base := time.Date(2018, 7, 1, 22, 0, 0, 0, time.UTC)
+	as := NewAnnotationService(s, "kids-room")
+	window := Annotation{Start: base, Finish: base.Add(2 * time.Hour), Text: "window open", Actor: "dani"}
+	guests := Annotation{Start: base.Add(-48 * time.Hour), Finish: base.Add(12 * time.Hour), Text: "guests sleeping over"}
+	old := Annotation{Start: base.Add(-72 * time.Hour), Finish: base.Add(-71 * time.Hour), Text: "vacuum cleaning"}
+	for _, a := range []Annotation{window, guests, old} {
+		if err := as.Annotate(a); err != nil {
+			t.Fatalf("Annotate(%+v): %q", a, err)
+		}
+	}
+	for _, a := range []Annotation{{Start: base, Finish: base}, {Start: base, Finish: base.Add(-time.Hour), Text: "x"}, {Start: base, Finish: base.Add(MaxAnnotationSpan + time.Hour), Text: "x"}} {
+		if err := as.Annotate(a); err != ErrInvalidAnnotation {
+			t.Errorf("Annotate(%+v) want:%q got:%q", a, ErrInvalidAnnotation, err)
+		}
+	}
+
+	got, err := as.Annotations(base.Add(time.Hour), base.Add(3*time.Hour))
+	if err != nil {
+		t.Fatalf("Annotations: %q", err)
+	}
+	if len(got) != 2 || got[0].Text != guests.Text || got[1].Text != window.Text || got[1].Actor != "dani" || !got[1].Finish.Equal(window.Finish) {
+		t.Errorf("Annotations want guests and window got:%+v", got)
+	}
+	if got, _ := NewAnnotationService(s, DefaultRoom).Annotations(base, base.Add(time.Hour)); len(got) != 0 {
+		t.Errorf("Annotations of other room want none got:%+v", got)
+	}
+
+	if err := as.Remove(window.Start, Edit{Actor: "dani"}); err != nil {
+		t.Fatalf("Remove: %q", err)
+	}
+	if err := as.Remove(window.Start, Edit{}); err != ErrNotFound {
+		t.Errorf("Remove twice want:%q got:%q", ErrNotFound, err)
+	}
+	if got, _ := as.Annotations(base.Add(time.Hour), base.Add(3*time.Hour)); len(got) != 1 || got[0].Text != guests.Text {
+		t.Errorf("Annotations after removal want guests got:%+v", got)
+	}
+}
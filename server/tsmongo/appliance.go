@@ -0,0 +1,375 @@
+package tsmongo
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// acField stores the air conditioner states, as documents.
+const acField = "ac"
+
+// changeSuffix suffixes the fields which store who changed the state of an appliance and why,
+// as samples timestamped as the state samples (e.g. "fan.change").
+const changeSuffix = ".change"
+
+// ApplianceKind identifies the kind of an appliance, which determines its capabilities.
+type ApplianceKind string
+
+// Enumerates the known kinds of appliances.
+const (
+	ApplianceFan ApplianceKind = fanField
+	ApplianceAC  ApplianceKind = acField
+)
+
+// Mode is the operation mode of an appliance.
+type Mode string
+
+// Enumerates the operation modes of air conditioners.
+const (
+	ModeCool Mode = "cool"
+	ModeDry  Mode = "dry"
+	ModeFan  Mode = "fan"
+)
+
+// Capabilities describes what can be set on an appliance.
+type Capabilities struct {
+	OnOff bool `json:"on_off"`
+	// Speeds is the number of discrete speeds of the appliance when on, numbered from 1 (e.g. 2
+	// for low and high). Zero if the speed can not be set.
+	Speeds int `json:"speeds,omitempty"`
+	// MinSetpoint and MaxSetpoint bound the temperature setpoint (Celsius). Both are zero if
+	// there is no setpoint.
+	MinSetpoint float64 `json:"min_setpoint,omitempty"`
+	MaxSetpoint float64 `json:"max_setpoint,omitempty"`
+	Modes       []Mode  `json:"modes,omitempty"`
+}
+
+// HasSetpoint returns whether the appliance has a temperature setpoint.
+func (c Capabilities) HasSetpoint() bool {
+	return c.MaxSetpoint > c.MinSetpoint
+}
+
+// HasMode returns whether the appliance supports the operation mode.
+func (c Capabilities) HasMode(m Mode) bool {
+	for _, mode := range c.Modes {
+		if mode == m {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate returns ErrInvalidApplianceState if the state can not be set on the appliance. An
+// appliance which is on runs at one of its speeds, at a setpoint within its range and in one of
+// its modes, if it has them. An appliance which is off has no speed, but keeps its setpoint and
+// mode.
+func (c Capabilities) Validate(s ApplianceState) error {
+	switch {
+	case !s.On && !c.OnOff:
+		return ErrInvalidApplianceState
+	case !s.On && s.Speed != 0:
+		return ErrInvalidApplianceState
+	case s.On && c.Speeds > 0 && (s.Speed < 1 || s.Speed > c.Speeds):
+		return ErrInvalidApplianceState
+	case c.Speeds == 0 && s.Speed != 0:
+		return ErrInvalidApplianceState
+	case c.HasSetpoint() && s.On && (s.Setpoint < c.MinSetpoint || s.Setpoint > c.MaxSetpoint):
+		return ErrInvalidApplianceState
+	case !c.HasSetpoint() && s.Setpoint != 0:
+		return ErrInvalidApplianceState
+	case len(c.Modes) > 0 && s.On && !c.HasMode(s.Mode):
+		return ErrInvalidApplianceState
+	case s.Mode != "" && !c.HasMode(s.Mode):
+		return ErrInvalidApplianceState
+	}
+	return nil
+}
+
+// Appliances maps the known kinds of appliances to their capabilities.
+var Appliances = map[ApplianceKind]Capabilities{
+	ApplianceFan: {OnOff: true, Speeds: 2},
+	ApplianceAC:  {OnOff: true, Speeds: 3, MinSetpoint: 16, MaxSetpoint: 30, Modes: []Mode{ModeCool, ModeDry, ModeFan}},
+}
+
+// ErrUnknownAppliance is returned when the kind of appliance is not known.
+var ErrUnknownAppliance = fmt.Errorf("Unknown appliance")
+
+// ErrInvalidApplianceState is returned when the state can not be set on the appliance.
+var ErrInvalidApplianceState = fmt.Errorf("Invalid appliance state")
+
+// ApplianceState stores the state of an appliance at a certain moment, and who changed it.
+type ApplianceState struct {
+	Timestamp time.Time `json:"timestamp,omitempty"`
+	On        bool      `json:"on"`
+	Speed     int       `json:"speed,omitempty"`
+	Setpoint  float64   `json:"setpoint,omitempty"`
+	Mode      Mode      `json:"mode,omitempty"`
+	FanChange
+}
+
+// acDoc is the stored representation of the air conditioner state.
+type acDoc struct {
+	On       bool    `bson:"on"`
+	Speed    int     `bson:"speed"`
+	Setpoint float64 `bson:"setpoint,omitempty"`
+	Mode     string  `bson:"mode,omitempty"`
+}
+
+// changeDoc is the stored representation of a change of the state of an appliance.
+type changeDoc struct {
+	Value     interface{} `bson:"value"`
+	FanChange `bson:",inline"`
+}
+
+// ApplianceService allows the user to update and get information about an appliance of a
+// bedroom, e.g. the fan or the air conditioner.
+type ApplianceService struct {
+	store  Store
+	kind   ApplianceKind
+	caps   Capabilities
+	series Series
+}
+
+// NewApplianceService creates a new ApplianceService, which allows to interact with the series
+// of the kind of appliance, for the specified room (DefaultRoom if empty). It returns
+// ErrUnknownAppliance if the kind is not in Appliances.
+func NewApplianceService(s Store, kind ApplianceKind, room string) (*ApplianceService, error) {
+	caps, ok := Appliances[kind]
+	if !ok {
+		return nil, ErrUnknownAppliance
+	}
+	return &ApplianceService{s, kind, caps, roomSeries(string(kind), room)}, nil
+}
+
+// Kind returns the kind of the appliance.
+func (a ApplianceService) Kind() ApplianceKind {
+	return a.kind
+}
+
+// Capabilities returns what can be set on the appliance.
+func (a ApplianceService) Capabilities() Capabilities {
+	return a.caps
+}
+
+// Room returns the room the service is bound to.
+func (a ApplianceService) Room() string {
+	return a.series.Room
+}
+
+// UpdateState changes the state of the appliance at the state timestamp, updating the database.
+// Every change is kept as a sample, with one second resolution, along with who changed it (if
+// known). It returns ErrInvalidApplianceState if the state can not be set on the appliance.
+func (a ApplianceService) UpdateState(s ApplianceState) error {
+	return a.UpdateStateContext(context.Background(), s)
+}
+
+// UpdateStateContext is like UpdateState, but honors the context deadline and cancellation.
+func (a ApplianceService) UpdateStateContext(ctx context.Context, s ApplianceState) error {
+	if err := a.caps.Validate(s); err != nil {
+		return err
+	}
+	v := a.encode(s)
+	if err := a.store.UpsertSamplesContext(ctx, a.series, TSRecord{s.Timestamp, v}); err != nil || s.FanChange == (FanChange{}) {
+		return err
+	}
+	return a.store.UpsertSamplesContext(ctx, a.changeSeries(), TSRecord{s.Timestamp, changeDoc{v, s.FanChange}})
+}
+
+// DeleteRange removes the states within the range (inclusive), along with who changed them,
+// returning how many states were removed. Removed values are kept in the audit trail of the
+// series (see AuditTrail).
+func (a ApplianceService) DeleteRange(start time.Time, finish time.Time, e Edit) (int, error) {
+	return a.DeleteRangeContext(context.Background(), start, finish, e)
+}
+
+// DeleteRangeContext is like DeleteRange, but honors the context deadline and cancellation.
+func (a ApplianceService) DeleteRangeContext(ctx context.Context, start time.Time, finish time.Time, e Edit) (int, error) {
+	n, err := DeleteRange(ctx, a.store, a.series, start, finish, e)
+	if err != nil {
+		return n, err
+	}
+	_, err = DeleteRange(ctx, a.store, a.changeSeries(), start, finish, e)
+	return n, err
+}
+
+// CorrectState changes the state set at the state timestamp, keeping the former one in the audit
+// trail of the series. Who changed it and why (e.g. a mislabeled change) is corrected too, unless
+// empty. It returns ErrNotFound if no state was set at that moment, and ErrInvalidApplianceState
+// if the state can not be set on the appliance.
+func (a ApplianceService) CorrectState(s ApplianceState, e Edit) error {
+	return a.CorrectStateContext(context.Background(), s, e)
+}
+
+// CorrectStateContext is like CorrectState, but honors the context deadline and cancellation.
+func (a ApplianceService) CorrectStateContext(ctx context.Context, s ApplianceState, e Edit) error {
+	if err := a.caps.Validate(s); err != nil {
+		return err
+	}
+	v := a.encode(s)
+	if err := Correct(ctx, a.store, a.series, s.Timestamp, v, e); err != nil || s.FanChange == (FanChange{}) {
+		return err
+	}
+	switch err := Correct(ctx, a.store, a.changeSeries(), s.Timestamp, changeDoc{v, s.FanChange}, e); err {
+	case ErrNotFound: // The change was not recorded.
+		return a.store.UpsertSamplesContext(ctx, a.changeSeries(), TSRecord{s.Timestamp, changeDoc{v, s.FanChange}})
+	default:
+		return err
+	}
+}
+
+// LastState returns the last state of the appliance. Appliances without states are off.
+func (a ApplianceService) LastState() (ApplianceState, error) {
+	return a.LastStateContext(context.Background())
+}
+
+// LastStateContext is like LastState, but honors the context deadline and cancellation.
+func (a ApplianceService) LastStateContext(ctx context.Context) (ApplianceState, error) {
+	ts, err := a.store.LastContext(ctx, a.series)
+	switch err {
+	case nil:
+		s, err := a.decode(ts)
+		if err != nil {
+			return ApplianceState{Timestamp: time.Now()}, err
+		}
+		changes, err := a.changes(ctx, []TSRecord{ts}, Hourly)
+		if err != nil {
+			return ApplianceState{Timestamp: time.Now()}, err
+		}
+		s.FanChange = changes[0]
+		return s, nil
+	case ErrNotFound:
+		return ApplianceState{Timestamp: time.Now()}, nil
+	default:
+		return ApplianceState{Timestamp: time.Now()}, err
+	}
+}
+
+// FetchState returns the appliance state updates in the considered period, either hourly or at
+// full resolution, along with who changed them. Hourly states are the newest state set within
+// the hour, and carry its change.
+func (a ApplianceService) FetchState(start time.Time, finish time.Time, res Resolution) ([]ApplianceState, error) {
+	return a.FetchStateContext(context.Background(), start, finish, res)
+}
+
+// FetchStateContext is like FetchState, but honors the context deadline and cancellation.
+func (a ApplianceService) FetchStateContext(ctx context.Context, start time.Time, finish time.Time, res Resolution) ([]ApplianceState, error) {
+	trs, err := fetch(ctx, a.store, a.series, start, finish, res)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := a.changes(ctx, trs, res)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]ApplianceState, len(trs))
+	for i := range trs {
+		if ret[i], err = a.decode(trs[i]); err != nil {
+			return nil, err
+		}
+		ret[i].FanChange = changes[i]
+	}
+	return ret, nil
+}
+
+// History returns every appliance state change within the considered period, along with who
+// changed it and why, sorted by ascending timestamp.
+func (a ApplianceService) History(start time.Time, finish time.Time) ([]ApplianceState, error) {
+	return a.HistoryContext(context.Background(), start, finish)
+}
+
+// HistoryContext is like History, but honors the context deadline and cancellation.
+func (a ApplianceService) HistoryContext(ctx context.Context, start time.Time, finish time.Time) ([]ApplianceState, error) {
+	ret, err := a.FetchStateContext(ctx, start, finish, FullResolution)
+	if err != nil {
+		return nil, err
+	}
+	for i, j := 0, len(ret)-1; i < j; i, j = i+1, j-1 {
+		ret[i], ret[j] = ret[j], ret[i]
+	}
+	return ret, nil
+}
+
+// encode returns the stored value of the state. Fan states are stored as the speed, which is
+// zero when the fan is off, as they were before other appliances existed.
+func (a ApplianceService) encode(s ApplianceState) interface{} {
+	if a.kind == ApplianceFan {
+		return s.Speed
+	}
+	return acDoc{s.On, s.Speed, s.Setpoint, string(s.Mode)}
+}
+
+func (a ApplianceService) decode(tr TSRecord) (ApplianceState, error) {
+	if a.kind == ApplianceFan {
+		speed, err := decodeInt(a.series, tr)
+		if err != nil {
+			return ApplianceState{}, err
+		}
+		return ApplianceState{Timestamp: tr.Timestamp, On: speed > 0, Speed: speed}, nil
+	}
+	var doc acDoc
+	if err := decodeValue(tr.Value, &doc); err != nil {
+		return ApplianceState{}, &DecodeError{a.series, tr.Timestamp, tr.Value, "ac document"}
+	}
+	return ApplianceState{Timestamp: tr.Timestamp, On: doc.On, Speed: doc.Speed, Setpoint: doc.Setpoint, Mode: Mode(doc.Mode)}, nil
+}
+
+func (a ApplianceService) changeSeries() Series {
+	return Series{Field: a.series.Field + changeSuffix, Room: a.series.Room}
+}
+
+// changes returns the changes of the state records, fetched at the specified resolution. Changes
+// are stored at the timestamp of the state samples, so hourly records are matched through the
+// newest sample of their hour, which they were taken from.
+func (a ApplianceService) changes(ctx context.Context, trs []TSRecord, res Resolution) ([]FanChange, error) {
+	ret := make([]FanChange, len(trs))
+	if len(trs) == 0 {
+		return ret, nil
+	}
+	times := make([]time.Time, len(trs))
+	for i, tr := range trs {
+		times[i] = sampleTime(tr.Timestamp)
+	}
+	start, finish := times[0], times[0]
+	for _, t := range times {
+		if t.Before(start) {
+			start = t
+		}
+		if t.After(finish) {
+			finish = t
+		}
+	}
+	if res == Hourly {
+		start, finish = hourUTC(start), hourUTC(finish).Add(time.Hour-time.Nanosecond)
+		samples, err := a.store.QuerySamplesContext(ctx, a.series, start, finish)
+		if err != nil {
+			return nil, err
+		}
+		newest := make(map[time.Time]time.Time, len(trs))
+		for _, tr := range samples {
+			t := sampleTime(tr.Timestamp)
+			if h := hourUTC(t); t.After(newest[h]) {
+				newest[h] = t
+			}
+		}
+		for i := range times {
+			times[i] = newest[hourUTC(times[i])]
+		}
+	}
+	byTime := make(map[time.Time]FanChange)
+	err := a.store.StreamContext(ctx, a.changeSeries(), start, finish, FullResolution, Ascending, func(tr TSRecord) error {
+		var doc changeDoc
+		if err := decodeValue(tr.Value, &doc); err != nil {
+			return err
+		}
+		byTime[sampleTime(tr.Timestamp)] = doc.FanChange
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i, t := range times {
+		ret[i] = byTime[t]
+	}
+	return ret, nil
+}
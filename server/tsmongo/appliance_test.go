@@ -0,0 +1,90 @@
+package tsmongo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestApplianceValidate(t *testing.T) {
+	fan, ac := Appliances[ApplianceFan], Appliances[ApplianceAC]
+	cases := []struct {
+		name  string
+		caps  Capabilities
+		state ApplianceState
+		valid bool
+	}{
+		{"fan off", fan, ApplianceState{}, true},
+		{"fan high", fan, ApplianceState{On: true, Speed: 2}, true},
+		{"fan on without speed", fan, ApplianceState{On: true}, false},
+		{"fan off with speed", fan, ApplianceState{Speed: 1}, false},
+		{"fan too fast", fan, ApplianceState{On: true, Speed: 3}, false},
+		{"fan setpoint", fan, ApplianceState{On: true, Speed: 1, Setpoint: 22}, false},
+		{"fan mode", fan, ApplianceState{On: true, Speed: 1, Mode: ModeCool}, false},
+		{"ac cool", ac, ApplianceState{On: true, Speed: 3, Setpoint: 22, Mode: ModeCool}, true},
+		{"ac off keeps setpoint", ac, ApplianceState{Setpoint: 22, Mode: ModeDry}, true},
+		{"ac setpoint too low", ac, ApplianceState{On: true, Speed: 1, Setpoint: 10, Mode: ModeCool}, false},
+		{"ac without mode", ac, ApplianceState{On: true, Speed: 1, Setpoint: 22}, false},
+		{"ac unknown mode", ac, ApplianceState{Mode: "heat"}, false},
+	}
+	for _, c := range cases {
+		err := c.caps.Validate(c.state)
+		if c.valid && err != nil || !c.valid && err != ErrInvalidApplianceState {
+			t.Errorf("%s: Validate(%+v) want valid:%v got:%v", c.name, c.state, c.valid, err)
+		}
+	}
+}
+
+func TestApplianceService(t *testing.T) {
+	s := NewMemStore()
+	if _, err := NewApplianceService(s, "heater", DefaultRoom); err != ErrUnknownAppliance {
+		t.Errorf("NewApplianceService(heater) want:%q got:%q", ErrUnknownAppliance, err)
+	}
+	as, err := NewApplianceService(s, ApplianceAC, DefaultRoom)
+	if err != nil {
+		t.Fatalf("NewApplianceService: %q", err)
+	}
+	last, err := as.LastState()
+	if err != nil || last.On {
+		t.Errorf("LastState without states want off got:%+v (%v)", last, err)
+	}
+
+	base := time.Date(2018, 7, 1, 22, 0, 0, 0, time.UTC)
+	byUser := FanChange{Source: FanSourceWeb, Actor: "daniel"}
+	want := []ApplianceState{
+		{Timestamp: base, On: true, Speed: 2, Setpoint: 23, Mode: ModeCool, FanChange: byUser},
+		{Timestamp: base.Add(30 * time.Minute), Setpoint: 23, Mode: ModeCool},
+	}
+	for _, st := range want {
+		if err := as.UpdateState(st); err != nil {
+			t.Fatalf("UpdateState(%+v): %q", st, err)
+		}
+	}
+	if err := as.UpdateState(ApplianceState{Timestamp: base, On: true, Speed: 9}); err != ErrInvalidApplianceState {
+		t.Errorf("UpdateState invalid state want:%q got:%q", ErrInvalidApplianceState, err)
+	}
+	got, err := as.History(base, base.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("History: %q", err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("History want:%+v got:%+v", want, got)
+	}
+	for i := range want {
+		if !got[i].Timestamp.Equal(want[i].Timestamp) {
+			t.Errorf("[%d] want:%v got:%v", i, want[i].Timestamp, got[i].Timestamp)
+		}
+		got[i].Timestamp = want[i].Timestamp
+		if got[i] != want[i] {
+			t.Errorf("[%d] want:%+v got:%+v", i, want[i], got[i])
+		}
+	}
+
+	// The fan is an appliance too, sharing the history of FanService.
+	fs := NewFanService(s, DefaultRoom)
+	fs.UpdateStatusBy(base, FanLowSpeed, byUser)
+	fan, _ := NewApplianceService(s, ApplianceFan, DefaultRoom)
+	last, err = fan.LastState()
+	if err != nil || !last.On || last.Speed != int(FanLowSpeed) || last.FanChange != byUser {
+		t.Errorf("LastState of the fan want low speed by %+v got:%+v (%v)", byUser, last, err)
+	}
+}
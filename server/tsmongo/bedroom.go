@@ -1,6 +1,7 @@
 package tsmongo
 
 import (
+	"context"
 	"time"
 )
 
@@ -12,25 +13,158 @@ type BedroomState struct {
 	Temperature float64   `json:"temp,omitempty"`
 }
 
-// BedroomService allows the user to update and get information about the bedroom state.
+// BedroomService allows the user to update and get information about the state of a bedroom.
 type BedroomService struct {
-	session *Session
+	store  Store
+	series Series
+}
+
+// Room returns the room the service is bound to.
+func (b *BedroomService) Room() string {
+	return b.series.Room
+}
+
+// WithTags returns a copy of the service which stores the passed-in tags along with every
+// update (e.g. the sensor model).
+func (b *BedroomService) WithTags(tags map[string]string) *BedroomService {
+	series := b.series
+	series.Tags = tags
+	return &BedroomService{b.store, series}
 }
 
 // UpdateTemperature changes bedroom temperature at the specified time, updating the database.
+// Every update is kept as a sample, with one second resolution.
 func (b *BedroomService) UpdateTemperature(t time.Time, temp float64) error {
-	return b.session.Upsert(bedroomField, TSRecord{t, temp})
+	return b.UpdateTemperatureContext(context.Background(), t, temp)
+}
+
+// UpdateTemperatureContext is like UpdateTemperature, but honors the context deadline and cancellation.
+func (b *BedroomService) UpdateTemperatureContext(ctx context.Context, t time.Time, temp float64) error {
+	return b.store.UpsertSamplesContext(ctx, b.series, TSRecord{t, temp})
+}
+
+// DeleteRange removes the temperatures taken within the range (inclusive), e.g. while the sensor
+// was broken, returning how many were removed. Removed values are kept in the audit trail of the
+// series (see AuditTrail).
+func (b *BedroomService) DeleteRange(start time.Time, finish time.Time, e Edit) (int, error) {
+	return b.DeleteRangeContext(context.Background(), start, finish, e)
+}
+
+// DeleteRangeContext is like DeleteRange, but honors the context deadline and cancellation.
+func (b *BedroomService) DeleteRangeContext(ctx context.Context, start time.Time, finish time.Time, e Edit) (int, error) {
+	return DeleteRange(ctx, b.store, b.series, start, finish, e)
 }
 
-// FetchState returns the bedroom state updates in the considered period.
-func (b *BedroomService) FetchState(start time.Time, finish time.Time) ([]BedroomState, error) {
-	trs, err := b.session.Query(bedroomField, start, finish)
+// CorrectTemperature changes the temperature taken at t, keeping the former one in the audit
+// trail of the series. It returns ErrNotFound if no temperature was taken at t.
+func (b *BedroomService) CorrectTemperature(t time.Time, temp float64, e Edit) error {
+	return b.CorrectTemperatureContext(context.Background(), t, temp, e)
+}
+
+// CorrectTemperatureContext is like CorrectTemperature, but honors the context deadline and cancellation.
+func (b *BedroomService) CorrectTemperatureContext(ctx context.Context, t time.Time, temp float64, e Edit) error {
+	return Correct(ctx, b.store, b.series, t, temp, e)
+}
+
+// FetchState returns the bedroom state updates in the considered period, either hourly or at
+// full resolution.
+func (b *BedroomService) FetchState(start time.Time, finish time.Time, res Resolution) ([]BedroomState, error) {
+	return b.FetchStateContext(context.Background(), start, finish, res)
+}
+
+// FetchStateContext is like FetchState, but honors the context deadline and cancellation.
+func (b *BedroomService) FetchStateContext(ctx context.Context, start time.Time, finish time.Time, res Resolution) ([]BedroomState, error) {
+	trs, err := fetch(ctx, b.store, b.series, start, finish, res)
 	if err != nil {
 		return nil, err
 	}
 	ret := make([]BedroomState, len(trs))
 	for i := range trs {
-		ret[i] = BedroomState{trs[i].Timestamp, trs[i].Value.(float64)}
+		temp, err := decodeFloat(b.series, trs[i])
+		if err != nil {
+			return nil, err
+		}
+		ret[i] = BedroomState{trs[i].Timestamp, temp}
+	}
+	return ret, nil
+}
+
+// FetchAllRooms returns the state updates of every room within the considered period, keyed by
+// room, regardless of the room the service is bound to.
+func (b *BedroomService) FetchAllRooms(start time.Time, finish time.Time, res Resolution) (map[string][]BedroomState, error) {
+	return b.FetchAllRoomsContext(context.Background(), start, finish, res)
+}
+
+// FetchAllRoomsContext is like FetchAllRooms, but honors the context deadline and cancellation.
+func (b *BedroomService) FetchAllRoomsContext(ctx context.Context, start time.Time, finish time.Time, res Resolution) (map[string][]BedroomState, error) {
+	series, err := b.store.ListSeriesContext(ctx, bedroomField)
+	if err != nil {
+		return nil, err
+	}
+	ret := make(map[string][]BedroomState, len(series))
+	for _, s := range series {
+		states, err := (&BedroomService{b.store, s}).FetchStateContext(ctx, start, finish, res)
+		if err != nil {
+			return nil, err
+		}
+		ret[s.Room] = states
 	}
 	return ret, nil
 }
+
+// StreamState calls fn for each bedroom state update in the considered period, in the
+// specified order. Unlike FetchState, updates are not loaded into memory all at once.
+func (b *BedroomService) StreamState(start time.Time, finish time.Time, res Resolution, order Order, fn func(BedroomState) error) error {
+	return b.StreamStateContext(context.Background(), start, finish, res, order, fn)
+}
+
+// StreamStateContext is like StreamState, but honors the context deadline and cancellation.
+func (b *BedroomService) StreamStateContext(ctx context.Context, start time.Time, finish time.Time, res Resolution, order Order, fn func(BedroomState) error) error {
+	return b.store.StreamContext(ctx, b.series, start, finish, res, order, func(tr TSRecord) error {
+		temp, err := decodeFloat(b.series, tr)
+		if err != nil {
+			return err
+		}
+		return fn(BedroomState{tr.Timestamp, temp})
+	})
+}
+
+// BedroomWatcher delivers bedroom state updates as they arrive.
+type BedroomWatcher struct {
+	// C receives the new bedroom states. It is closed when the watcher stops.
+	C <-chan BedroomState
+	*watcher
+}
+
+// Watch subscribes to bedroom state updates written after the call (see the package Watch for
+// how updates are detected). The watcher stops when closed, when the context is done or at the
+// first error.
+func (b *BedroomService) Watch(ctx context.Context) (*BedroomWatcher, error) {
+	c := make(chan BedroomState)
+	w, err := startWatch(ctx, b.store, b.series, func(ctx context.Context, tr TSRecord) bool {
+		temp, err := decodeFloat(b.series, tr)
+		if err != nil {
+			return true // Records which can not be decoded are skipped.
+		}
+		select {
+		case c <- BedroomState{tr.Timestamp, temp}:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}, func() { close(c) })
+	if err != nil {
+		return nil, err
+	}
+	return &BedroomWatcher{C: c, watcher: w}, nil
+}
+
+// Aggregate aggregates the bedroom temperature (Celsius) samples into time windows.
+func (b *BedroomService) Aggregate(q AggregateQuery) ([]AggregateBucket, error) {
+	return b.AggregateContext(context.Background(), q)
+}
+
+// AggregateContext is like Aggregate, but honors the context deadline and cancellation.
+func (b *BedroomService) AggregateContext(ctx context.Context, q AggregateQuery) ([]AggregateBucket, error) {
+	return b.store.AggregateContext(ctx, b.series, "", q)
+}
@@ -0,0 +1,106 @@
+package tsmongo
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+// DecodeError is returned when a stored value can not be decoded into the type of its field,
+// e.g. a string written to the fan series through the mongo shell.
+type DecodeError struct {
+	Series    Series
+	Timestamp time.Time
+	Value     interface{}
+	Want      string // The expected type, e.g. "int".
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("Error decoding tsmongo %s document of hour %v: value at %v want:%s got:%T(%v)", e.Series, hourUTC(e.Timestamp).Format(time.RFC3339), e.Timestamp.UTC().Format(time.RFC3339), e.Want, e.Value, e.Value)
+}
+
+// Codecs decode the values of a series into their Go type, accepting every numeric BSON
+// encoding (double, 32 and 64-bit integers and decimals). Values written by other clients, e.g.
+// an import or the mongo shell (which writes doubles by default), may not have the type this
+// package writes.
+var (
+	// floatCodec decodes the fields storing real numbers, e.g. the bedroom temperature and sensor
+	// readings.
+	floatCodec = codec{"float", func(v interface{}) (interface{}, bool) { return toFloat(v) }}
+	// intCodec decodes the fields storing integers, e.g. the fan status. Real numbers are
+	// accepted if they have no fractional part.
+	intCodec = codec{"int", func(v interface{}) (interface{}, bool) { return toInt(v) }}
+)
+
+type codec struct {
+	want   string
+	decode func(interface{}) (interface{}, bool)
+}
+
+// decodeFloat decodes the value of the record of the series with floatCodec.
+func decodeFloat(series Series, tr TSRecord) (float64, error) {
+	v, err := floatCodec.decodeRecord(series, tr)
+	if err != nil {
+		return 0, err
+	}
+	return v.(float64), nil
+}
+
+// decodeInt decodes the value of the record of the series with intCodec.
+func decodeInt(series Series, tr TSRecord) (int, error) {
+	v, err := intCodec.decodeRecord(series, tr)
+	if err != nil {
+		return 0, err
+	}
+	return v.(int), nil
+}
+
+func (c codec) decodeRecord(series Series, tr TSRecord) (interface{}, error) {
+	v, ok := c.decode(tr.Value)
+	if !ok {
+		return nil, &DecodeError{series, tr.Timestamp, tr.Value, c.want}
+	}
+	return v, nil
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case bson.Decimal128:
+		f, err := strconv.ParseFloat(n.String(), 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func toInt(v interface{}) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case int32:
+		return int(n), true
+	case int64:
+		if int64(int(n)) != n {
+			return 0, false
+		}
+		return int(n), true
+	}
+	f, ok := toFloat(v)
+	if !ok || f != math.Trunc(f) || math.Abs(f) > math.MaxInt32 {
+		return 0, false
+	}
+	return int(f), true
+}
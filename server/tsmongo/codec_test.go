@@ -0,0 +1,111 @@
+package tsmongo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+func TestDecodeNumericTypes(t *testing.T) {
+	dec, _ := bson.ParseDecimal128("23.5")
+	decInt, _ := bson.ParseDecimal128("2")
+	base := time.Date(2018, 7, 1, 10, 0, 0, 0, time.UTC)
+	temps := []interface{}{float64(21.5), int32(22), int64(23), dec, float32(24)}
+	statuses := []interface{}{int(1), int32(2), int64(0), float64(1), decInt}
+
+	s := NewMemStore()
+	ctx := context.Background()
+	for i := range temps {
+		ts := base.Add(time.Duration(i) * time.Hour)
+		s.UpsertSamplesContext(ctx, bedroomSeries, TSRecord{ts, temps[i]})
+		s.UpsertSamplesContext(ctx, fanSeries, TSRecord{ts, statuses[i]})
+	}
+
+	bs := NewBedroomService(s, DefaultRoom)
+	states, err := bs.FetchState(base, base.Add(5*time.Hour), Hourly)
+	if err != nil {
+		t.Fatalf("FetchState: %q", err)
+	}
+	wantTemps := []float64{24, 23.5, 23, 22, 21.5}
+	for i, st := range states {
+		if st.Temperature != wantTemps[i] {
+			t.Errorf("[%d] FetchState temperature want:%v got:%v", i, wantTemps[i], st.Temperature)
+		}
+	}
+
+	fs := NewFanService(s, DefaultRoom)
+	history, err := fs.History(base, base.Add(5*time.Hour))
+	if err != nil {
+		t.Fatalf("History: %q", err)
+	}
+	wantStatuses := []FanStatus{FanLowSpeed, FanHighSpeed, FanOff, FanLowSpeed, FanHighSpeed}
+	for i, st := range history {
+		if st.Status != wantStatuses[i] {
+			t.Errorf("[%d] History status want:%v got:%v", i, wantStatuses[i], st.Status)
+		}
+	}
+	last, err := fs.LastState()
+	if err != nil || last.Status != FanHighSpeed {
+		t.Errorf("LastState want:%v got:%+v (%v)", FanHighSpeed, last, err)
+	}
+}
+
+func TestDecodeCorruptedData(t *testing.T) {
+	base := time.Date(2018, 7, 1, 10, 0, 0, 0, time.UTC)
+	finish := base.Add(time.Hour)
+	cases := []struct {
+		name   string
+		series Series
+		value  interface{}
+		read   func(s Store) error
+	}{
+		{"bedroom string", bedroomSeries, "hot", func(s Store) error {
+			_, err := NewBedroomService(s, DefaultRoom).FetchState(base, finish, Hourly)
+			return err
+		}},
+		{"bedroom document", bedroomSeries, bson.M{"temp": 20}, func(s Store) error {
+			return NewBedroomService(s, DefaultRoom).StreamState(base, finish, FullResolution, Ascending, func(BedroomState) error { return nil })
+		}},
+		{"bedroom resample", bedroomSeries, true, func(s Store) error {
+			_, err := NewBedroomService(s, DefaultRoom).Resample(base, finish, ResampleOptions{})
+			return err
+		}},
+		{"fan fraction", fanSeries, 1.5, func(s Store) error {
+			_, err := NewFanService(s, DefaultRoom).LastState()
+			return err
+		}},
+		{"fan string", fanSeries, "high", func(s Store) error {
+			_, err := NewFanService(s, DefaultRoom).FetchState(base, finish, FullResolution)
+			return err
+		}},
+		{"fan null", fanSeries, nil, func(s Store) error {
+			_, err := NewFanService(s, DefaultRoom).Resample(base, finish, ResampleOptions{})
+			return err
+		}},
+		{"ac number", Series{Field: acField, Room: DefaultRoom}, 2, func(s Store) error {
+			ac, _ := NewApplianceService(s, ApplianceAC, DefaultRoom)
+			_, err := ac.LastState()
+			return err
+		}},
+		{"sensor string", NewSensorService(nil, DefaultRoom).series(Humidity), "wet", func(s Store) error {
+			_, err := NewSensorService(s, DefaultRoom).Fetch("humidity", base, finish, Hourly)
+			return err
+		}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			s := NewMemStore()
+			s.UpsertSamplesContext(context.Background(), c.series, TSRecord{base, c.value})
+			err := c.read(s)
+			derr, ok := err.(*DecodeError)
+			if !ok {
+				t.Fatalf("want *DecodeError got:%v", err)
+			}
+			if derr.Series.Field != c.series.Field || !derr.Timestamp.Equal(base) {
+				t.Errorf("DecodeError want series:%v timestamp:%v got:%+v", c.series, base, derr)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package tsmongo
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// auditSuffix suffixes the fields which store the audit trail of edited series.
+const auditSuffix = ".audit"
+
+// Names of the edits of stored records.
+const (
+	EditDelete  = "delete"
+	EditCorrect = "correct"
+)
+
+// Edit describes who edited stored records and why.
+type Edit struct {
+	Actor  string `json:"actor,omitempty" bson:"actor,omitempty"`
+	Reason string `json:"reason,omitempty" bson:"reason,omitempty"`
+}
+
+// AuditEntry records an edit of a stored record, along with the value it had before.
+type AuditEntry struct {
+	Series    Series      `json:"series"`
+	Timestamp time.Time   `json:"timestamp"` // Of the edited record.
+	Edited    time.Time   `json:"edited"`
+	Op        string      `json:"op"`
+	Original  interface{} `json:"original"`
+	// Value is the value the record was corrected to (nil for deletions).
+	Value interface{} `json:"value,omitempty"`
+	Edit
+}
+
+// auditTrail is the stored representation of the edits of a record, oldest first, stored under
+// its timestamp in the audit series.
+type auditTrail struct {
+	Edits []auditDoc `bson:"edits"`
+}
+
+// auditDoc is the stored representation of an edit.
+type auditDoc struct {
+	Edited   time.Time   `bson:"edited"`
+	Op       string      `bson:"op"`
+	Original interface{} `bson:"original"`
+	Value    interface{} `bson:"value,omitempty"`
+	Edit     `bson:",inline"`
+}
+
+// AuditSeries returns the series which stores the audit trail of the passed-in series.
+func AuditSeries(series Series) Series {
+	return Series{series.Field + auditSuffix, series.Room, series.Tags}
+}
+
+// DeleteRange removes the records of the series within the range (inclusive), returning how
+// many were removed. The removed values are kept in the audit trail of the series (see
+// AuditTrail).
+func DeleteRange(ctx context.Context, s Store, series Series, start time.Time, finish time.Time, e Edit) (int, error) {
+	trs, err := s.QuerySamplesContext(ctx, series, start, finish)
+	if err != nil || len(trs) == 0 {
+		return 0, err
+	}
+	now := time.Now()
+	entries := make([]auditDoc, len(trs))
+	for i, tr := range trs {
+		entries[i] = auditDoc{now, EditDelete, tr.Value, nil, e}
+	}
+	if err := audit(ctx, s, series, trs, entries); err != nil {
+		return 0, err
+	}
+	// Removing the audited samples only, samples written meanwhile are kept.
+	t := make([]time.Time, len(trs))
+	for i, tr := range trs {
+		t[i] = tr.Timestamp
+	}
+	return s.RemoveSamplesContext(ctx, series, t...)
+}
+
+// Correct changes the value of the record of the series taken at t, keeping its former value in
+// the audit trail of the series (see AuditTrail). It returns ErrNotFound if there is no such
+// record.
+func Correct(ctx context.Context, s Store, series Series, t time.Time, value interface{}, e Edit) error {
+	t = sampleTime(t)
+	trs, err := s.QuerySamplesContext(ctx, series, t, t)
+	if err != nil {
+		return err
+	}
+	if len(trs) == 0 {
+		return ErrNotFound
+	}
+	if err := audit(ctx, s, series, trs[:1], []auditDoc{{time.Now(), EditCorrect, trs[0].Value, value, e}}); err != nil {
+		return err
+	}
+	// Records of buckets without samples (at the beginning of the hour) become samples, which
+	// are read the same way.
+	return s.UpsertSamplesContext(ctx, series, TSRecord{t, value})
+}
+
+// AuditTrail returns the edits of the records of the series taken within the range, sorted by
+// ascending edit time.
+func AuditTrail(ctx context.Context, s Store, series Series, start time.Time, finish time.Time) ([]AuditEntry, error) {
+	a := AuditSeries(series)
+	trs, err := s.QuerySamplesContext(ctx, a, start, finish)
+	if err != nil {
+		return nil, err
+	}
+	var ret []AuditEntry
+	for _, tr := range trs {
+		var doc auditTrail
+		if err := decodeValue(tr.Value, &doc); err != nil {
+			return nil, &DecodeError{a, tr.Timestamp, tr.Value, "audit trail"}
+		}
+		for _, d := range doc.Edits {
+			ret = append(ret, AuditEntry{series, tr.Timestamp.Local(), d.Edited.Local(), d.Op, d.Original, d.Value, d.Edit})
+		}
+	}
+	sort.SliceStable(ret, func(i, j int) bool { return ret[i].Edited.Before(ret[j].Edited) })
+	return ret, nil
+}
+
+// audit appends the entries to the audit trail of the edited records.
+func audit(ctx context.Context, s Store, series Series, edited []TSRecord, entries []auditDoc) error {
+	a := AuditSeries(series)
+	start, finish := edited[0].Timestamp, edited[0].Timestamp
+	for _, tr := range edited {
+		if tr.Timestamp.Before(start) {
+			start = tr.Timestamp
+		}
+		if tr.Timestamp.After(finish) {
+			finish = tr.Timestamp
+		}
+	}
+	trs, err := s.QuerySamplesContext(ctx, a, start, finish)
+	if err != nil {
+		return err
+	}
+	trail := make(map[time.Time][]auditDoc, len(trs))
+	for _, tr := range trs {
+		var doc auditTrail
+		if err := decodeValue(tr.Value, &doc); err != nil {
+			return &DecodeError{a, tr.Timestamp, tr.Value, "audit trail"}
+		}
+		trail[tr.Timestamp.UTC()] = doc.Edits
+	}
+	val := make([]TSRecord, len(edited))
+	for i, tr := range edited {
+		t := tr.Timestamp.UTC()
+		trail[t] = append(trail[t], entries[i])
+		val[i] = TSRecord{t, auditTrail{trail[t]}}
+	}
+	return s.UpsertSamplesContext(ctx, a, val...)
+}
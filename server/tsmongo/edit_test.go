@@ -0,0 +1,138 @@
+package tsmongo
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDeleteRange(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemStore()
+	base := time.Date(2018, 7, 1, 22, 0, 0, 0, time.UTC)
+	bs := NewBedroomService(s, DefaultRoom)
+	for i := 0; i < 6; i++ {
+		bs.UpdateTemperature(base.Add(time.Duration(i)*30*time.Minute), 20+float64(i))
+	}
+	// The hourly value of 01:00 (without samples) is within the range.
+	s.Upsert(bedroomSeries, TSRecord{base.Add(3 * time.Hour), 30.0})
+
+	e := Edit{Actor: "dani", Reason: "sensor under the blanket"}
+	n, err := bs.DeleteRange(base.Add(20*time.Minute), base.Add(3*time.Hour), e)
+	if err != nil || n != 6 {
+		t.Fatalf("DeleteRange want 6 records got:%d (%v)", n, err)
+	}
+	states, err := bs.FetchState(base, base.Add(4*time.Hour), FullResolution)
+	if err != nil || len(states) != 1 || states[0].Temperature != 20 {
+		t.Fatalf("FetchState want the 22:00 temperature got:%+v (%v)", states, err)
+	}
+	// Hourly values of rewritten hours are their last remaining record.
+	if last, _ := s.Last(bedroomSeries); last.Value != 20.0 {
+		t.Errorf("Last want:20 got:%+v", last)
+	}
+
+	trail, err := AuditTrail(ctx, s, bedroomSeries, base, base.Add(4*time.Hour))
+	if err != nil || len(trail) != 6 {
+		t.Fatalf("AuditTrail want 6 entries got:%+v (%v)", trail, err)
+	}
+	originals := make(map[time.Time]interface{})
+	for _, a := range trail {
+		if a.Op != EditDelete || a.Edit != e || a.Value != nil {
+			t.Errorf("AuditTrail want deletion by %+v got:%+v", e, a)
+		}
+		originals[a.Timestamp.UTC()] = a.Original
+	}
+	if originals[base.Add(30*time.Minute)] != 21.0 || originals[base.Add(3*time.Hour)] != 30.0 {
+		t.Errorf("AuditTrail originals want 21 at 22:30 and 30 at 01:00 got:%v", originals)
+	}
+	if n, err := bs.DeleteRange(base.Add(time.Hour), base.Add(2*time.Hour), e); err != nil || n != 0 {
+		t.Errorf("DeleteRange of empty range want 0 got:%d (%v)", n, err)
+	}
+}
+
+// racyStore writes a sample right after the first query, as a concurrent writer would.
+type racyStore struct {
+	*MemStore
+	write TSRecord
+	done  bool
+}
+
+func (r *racyStore) QuerySamplesContext(ctx context.Context, series Series, start time.Time, finish time.Time) ([]TSRecord, error) {
+	trs, err := r.MemStore.QuerySamplesContext(ctx, series, start, finish)
+	if !r.done {
+		r.done = true
+		r.MemStore.UpsertSamplesContext(ctx, series, r.write)
+	}
+	return trs, err
+}
+
+func TestDeleteRangeKeepsNewSamples(t *testing.T) {
+	ctx := context.Background()
+	base := time.Date(2018, 7, 1, 22, 0, 0, 0, time.UTC)
+	s := &racyStore{MemStore: NewMemStore(), write: TSRecord{base.Add(40 * time.Minute), 24.0}}
+	s.UpsertSamples(bedroomSeries, TSRecord{base.Add(10 * time.Minute), 21.0}, TSRecord{base.Add(20 * time.Minute), 22.0})
+	n, err := DeleteRange(ctx, s, bedroomSeries, base, base.Add(time.Hour-time.Second), Edit{Reason: "test"})
+	if err != nil || n != 2 {
+		t.Fatalf("DeleteRange want 2 records got:%d (%v)", n, err)
+	}
+	if trs, _ := s.QuerySamples(bedroomSeries, base, base.Add(time.Hour)); len(trs) != 1 || trs[0].Value != 24.0 {
+		t.Errorf("QuerySamples want the sample written meanwhile got:%+v", trs)
+	}
+}
+
+func TestAuditSeries(t *testing.T) {
+	series := Series{"bedroom", DefaultRoom, map[string]string{"sensor": "dht22"}}
+	if a := AuditSeries(series); a.Field != "bedroom.audit" || a.Room != DefaultRoom || a.Tags["sensor"] != "dht22" {
+		t.Errorf("AuditSeries want the tags of the series got:%+v", a)
+	}
+}
+
+func TestCorrect(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemStore()
+	base := time.Date(2018, 7, 1, 22, 0, 0, 0, time.UTC)
+	bs := NewBedroomService(s, DefaultRoom)
+	bs.UpdateTemperature(base.Add(10*time.Minute), 25)
+	bs.UpdateTemperature(base.Add(20*time.Minute), 52)
+	bs.UpdateTemperature(base.Add(30*time.Minute), 26)
+
+	first, second := Edit{"dani", "typo"}, Edit{"ana", "still wrong"}
+	if err := bs.CorrectTemperature(base.Add(20*time.Minute), 21.5, first); err != nil {
+		t.Fatalf("CorrectTemperature: %q", err)
+	}
+	if err := bs.CorrectTemperature(base.Add(20*time.Minute), 25.5, second); err != nil {
+		t.Fatalf("CorrectTemperature: %q", err)
+	}
+	if err := bs.CorrectTemperature(base.Add(21*time.Minute), 25, first); err != ErrNotFound {
+		t.Errorf("CorrectTemperature of missing record want:%q got:%q", ErrNotFound, err)
+	}
+	states, _ := bs.FetchState(base, base.Add(time.Hour), FullResolution)
+	if len(states) != 3 || states[1].Temperature != 25.5 {
+		t.Errorf("FetchState want corrected temperature got:%+v", states)
+	}
+	if states, _ := bs.FetchState(base, base.Add(time.Hour), Hourly); len(states) != 1 || states[0].Temperature != 26 {
+		t.Errorf("FetchState hourly want:26 got:%+v", states)
+	}
+	trail, err := AuditTrail(ctx, s, bedroomSeries, base, base.Add(time.Hour))
+	if err != nil || len(trail) != 2 {
+		t.Fatalf("AuditTrail want 2 entries got:%+v (%v)", trail, err)
+	}
+	if trail[0].Original != 52.0 || trail[0].Value != 21.5 || trail[0].Edit != first || trail[1].Original != 21.5 || trail[1].Edit != second {
+		t.Errorf("AuditTrail want both corrections got:%+v", trail)
+	}
+
+	// Fixing a mislabeled fan change.
+	fs := NewFanService(s, DefaultRoom)
+	fs.UpdateStatusBy(base, FanHighSpeed, FanChange{FanSourceAutomation, "", "too hot"})
+	change := FanChange{FanSourceWeb, "dani", "going to sleep"}
+	if err := fs.CorrectStatus(base, FanLowSpeed, change, first); err != nil {
+		t.Fatalf("CorrectStatus: %q", err)
+	}
+	last, err := fs.LastState()
+	if err != nil || last.Status != FanLowSpeed || last.FanChange != change {
+		t.Errorf("LastState want low speed changed by %+v got:%+v (%v)", change, last, err)
+	}
+	if err := fs.CorrectStatus(base, FanStatus(7), change, first); err != ErrInvalidFanStatus {
+		t.Errorf("CorrectStatus want:%q got:%q", ErrInvalidFanStatus, err)
+	}
+}
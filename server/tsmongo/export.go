@@ -0,0 +1,428 @@
+package tsmongo
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+// Format is the encoding of exported records.
+type Format int
+
+const (
+	// JSONLines encodes one JSON record per line.
+	JSONLines Format = iota
+	// CSV encodes one record per row, after a header row. Tags and values are JSON encoded.
+	CSV
+)
+
+// ParseFormat parses a format name: "jsonl" or "csv".
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(s) {
+	case "jsonl", "json":
+		return JSONLines, nil
+	case "csv":
+		return CSV, nil
+	}
+	return 0, fmt.Errorf("Invalid export format: %q", s)
+}
+
+// Kinds of exported records: hourly values or samples.
+const (
+	hourKind   = "hour"
+	sampleKind = "sample"
+)
+
+// Types of exported values. Documents (e.g. weather states) use MongoDB extended JSON, with
+// integers encoded as $numberLong, so their numeric types survive the round trip.
+const (
+	intType    = "int"
+	int64Type  = "int64"
+	floatType  = "float"
+	stringType = "string"
+	boolType   = "bool"
+	docType    = "doc"
+)
+
+var csvHeader = []string{"field", "room", "tags", "timestamp", "kind", "type", "value"}
+
+// exportRecord is the exported representation of a record.
+type exportRecord struct {
+	Field     string            `json:"field"`
+	Room      string            `json:"room,omitempty"`
+	Tags      map[string]string `json:"tags,omitempty"`
+	Timestamp time.Time         `json:"timestamp"`
+	Kind      string            `json:"kind"`
+	Type      string            `json:"type"`
+	Value     json.RawMessage   `json:"value"`
+}
+
+func (r exportRecord) series() Series {
+	return Series{r.Field, r.Room, r.Tags}
+}
+
+// ExportRange selects the records of a series within a time range (both inclusive).
+type ExportRange struct {
+	Series Series
+	Start  time.Time
+	Finish time.Time
+}
+
+// Export writes the records within the ranges to w, returning how many were written. Hour
+// buckets holding samples are exported as their samples, whose last one becomes the hourly
+// value again when imported; other buckets are exported as their hourly value.
+func Export(ctx context.Context, s Store, w io.Writer, f Format, ranges ...ExportRange) (int, error) {
+	enc := newRecordEncoder(w, f)
+	n := 0
+	for _, r := range ranges {
+		// Records are grouped by hour bucket to tell hourly values from samples: a bucket
+		// without samples streams a single record, timestamped at the hour.
+		var group []TSRecord
+		var hour time.Time
+		flush := func() error {
+			kind := sampleKind
+			if len(group) == 1 && group[0].Timestamp.Equal(hour) {
+				kind = hourKind
+			}
+			for _, tr := range group {
+				rec, err := newExportRecord(r.Series, kind, tr)
+				if err != nil {
+					return err
+				}
+				if err := enc.encode(rec); err != nil {
+					return fmt.Errorf("Error exporting tsmongo series %q: %q", r.Series, err)
+				}
+				n++
+			}
+			group = group[:0]
+			return nil
+		}
+		err := s.StreamContext(ctx, r.Series, r.Start, r.Finish, FullResolution, Ascending, func(tr TSRecord) error {
+			if h := hourUTC(tr.Timestamp); !h.Equal(hour) {
+				if err := flush(); err != nil {
+					return err
+				}
+				hour = h
+			}
+			group = append(group, tr)
+			return nil
+		})
+		if err == nil {
+			err = flush()
+		}
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, enc.close()
+}
+
+func newExportRecord(series Series, kind string, tr TSRecord) (exportRecord, error) {
+	typ, value, err := encodeExportValue(tr.Value)
+	if err != nil {
+		return exportRecord{}, fmt.Errorf("Error exporting tsmongo series %q at %s: %q", series, tr.Timestamp, err)
+	}
+	return exportRecord{series.Field, series.Room, series.Tags, tr.Timestamp.UTC(), kind, typ, value}, nil
+}
+
+func encodeExportValue(v interface{}) (string, json.RawMessage, error) {
+	var typ string
+	switch v.(type) {
+	case int, int32:
+		typ = intType
+	case int64:
+		typ = int64Type
+	case float64:
+		typ = floatType
+	case string:
+		typ = stringType
+	case bool:
+		typ = boolType
+	default:
+		var doc bson.M
+		if err := decodeValue(v, &doc); err != nil {
+			return "", nil, err
+		}
+		b, err := bson.MarshalJSON(widenInts(doc))
+		if err != nil {
+			return "", nil, err
+		}
+		return docType, bytes.TrimSpace(b), nil
+	}
+	b, err := json.Marshal(v)
+	return typ, b, err
+}
+
+// widenInts converts the integers within the document to int64, which extended JSON preserves.
+func widenInts(v interface{}) interface{} {
+	switch v := v.(type) {
+	case int:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case bson.M:
+		for k := range v {
+			v[k] = widenInts(v[k])
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = widenInts(v[i])
+		}
+	}
+	return v
+}
+
+func decodeExportValue(typ string, raw json.RawMessage) (interface{}, error) {
+	var err error
+	switch typ {
+	case intType:
+		var v int
+		err = json.Unmarshal(raw, &v)
+		return v, err
+	case int64Type:
+		var v int64
+		err = json.Unmarshal(raw, &v)
+		return v, err
+	case floatType:
+		var v float64
+		err = json.Unmarshal(raw, &v)
+		return v, err
+	case stringType:
+		var v string
+		err = json.Unmarshal(raw, &v)
+		return v, err
+	case boolType:
+		var v bool
+		err = json.Unmarshal(raw, &v)
+		return v, err
+	case docType:
+		var v bson.M
+		err = bson.UnmarshalJSON(raw, &v)
+		return v, err
+	}
+	return nil, fmt.Errorf("invalid value type %q", typ)
+}
+
+// recordEncoder writes exported records in a certain format.
+type recordEncoder struct {
+	w      *bufio.Writer
+	csv    *csv.Writer
+	header bool
+}
+
+func newRecordEncoder(w io.Writer, f Format) *recordEncoder {
+	enc := &recordEncoder{w: bufio.NewWriter(w)}
+	if f == CSV {
+		enc.csv = csv.NewWriter(enc.w)
+	}
+	return enc
+}
+
+func (enc *recordEncoder) encode(r exportRecord) error {
+	if enc.csv == nil {
+		b, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		enc.w.Write(b)
+		return enc.w.WriteByte('\n')
+	}
+	if !enc.header {
+		if err := enc.csv.Write(csvHeader); err != nil {
+			return err
+		}
+		enc.header = true
+	}
+	var tags []byte
+	if len(r.Tags) > 0 {
+		var err error
+		if tags, err = json.Marshal(r.Tags); err != nil {
+			return err
+		}
+	}
+	return enc.csv.Write([]string{r.Field, r.Room, string(tags), r.Timestamp.Format(time.RFC3339Nano), r.Kind, r.Type, string(r.Value)})
+}
+
+func (enc *recordEncoder) close() error {
+	if enc.csv != nil {
+		enc.csv.Flush()
+		if err := enc.csv.Error(); err != nil {
+			return err
+		}
+	}
+	return enc.w.Flush()
+}
+
+// recordDecoder reads exported records in a certain format.
+type recordDecoder struct {
+	lines *bufio.Scanner
+	csv   *csv.Reader
+	line  int
+}
+
+func newRecordDecoder(r io.Reader, f Format) *recordDecoder {
+	if f == CSV {
+		c := csv.NewReader(r)
+		c.FieldsPerRecord = len(csvHeader)
+		return &recordDecoder{csv: c}
+	}
+	lines := bufio.NewScanner(r)
+	lines.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	return &recordDecoder{lines: lines}
+}
+
+// decode reads the next record, returning io.EOF when there are no more records.
+func (dec *recordDecoder) decode() (exportRecord, error) {
+	var r exportRecord
+	if dec.csv == nil {
+		for {
+			if !dec.lines.Scan() {
+				if err := dec.lines.Err(); err != nil {
+					return r, err
+				}
+				return r, io.EOF
+			}
+			dec.line++
+			if len(bytes.TrimSpace(dec.lines.Bytes())) > 0 {
+				break
+			}
+		}
+		if err := json.Unmarshal(dec.lines.Bytes(), &r); err != nil {
+			return r, fmt.Errorf("line %d: %q", dec.line, err)
+		}
+		return r, dec.validate(r)
+	}
+	for {
+		row, err := dec.csv.Read()
+		if err != nil {
+			return r, err
+		}
+		dec.line++
+		if dec.line == 1 && row[0] == csvHeader[0] {
+			continue
+		}
+		r = exportRecord{Field: row[0], Room: row[1], Kind: row[4], Type: row[5], Value: json.RawMessage(row[6])}
+		if row[2] != "" {
+			if err := json.Unmarshal([]byte(row[2]), &r.Tags); err != nil {
+				return r, fmt.Errorf("line %d: invalid tags: %q", dec.line, err)
+			}
+		}
+		if r.Timestamp, err = time.Parse(time.RFC3339Nano, row[3]); err != nil {
+			return r, fmt.Errorf("line %d: %q", dec.line, err)
+		}
+		return r, dec.validate(r)
+	}
+}
+
+func (dec *recordDecoder) validate(r exportRecord) error {
+	switch {
+	case r.Field == "":
+		return fmt.Errorf("line %d: missing field", dec.line)
+	case r.Timestamp.IsZero():
+		return fmt.Errorf("line %d: missing timestamp", dec.line)
+	case r.Kind != hourKind && r.Kind != sampleKind:
+		return fmt.Errorf("line %d: invalid kind %q", dec.line, r.Kind)
+	}
+	return nil
+}
+
+// ImportOptions configures Import.
+type ImportOptions struct {
+	// DryRun decodes and validates the records, without storing them.
+	DryRun bool
+	// BatchSize is the maximum number of records upserted at once. Defaults to 1000.
+	BatchSize int
+	// Progress, if not nil, is called after each batch is stored (or validated, on dry runs).
+	Progress func(ImportReport)
+}
+
+// ImportReport counts the records imported so far.
+type ImportReport struct {
+	Hours   int // Hourly values.
+	Samples int
+	Series  int // Distinct series.
+}
+
+// Records returns the total number of imported records.
+func (r ImportReport) Records() int {
+	return r.Hours + r.Samples
+}
+
+func (r ImportReport) String() string {
+	return fmt.Sprintf("%d records (%d hourly values, %d samples) of %d series", r.Records(), r.Hours, r.Samples, r.Series)
+}
+
+// Import reads exported records from r and stores them through the bulk upsert path. Records
+// override stored data with the same series and timestamp, so importing the same data again
+// is a no-op. Decoding stops at the first invalid record; batches stored before it are kept.
+func Import(ctx context.Context, s Store, r io.Reader, f Format, opts ImportOptions) (ImportReport, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 1000
+	}
+	var report ImportReport
+	seen := make(map[seriesKey]bool)
+	var batch []TSRecord
+	var batchSeries Series
+	var batchKind string
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if !opts.DryRun {
+			var err error
+			if batchKind == hourKind {
+				err = s.UpsertContext(ctx, batchSeries, batch...)
+			} else {
+				err = s.UpsertSamplesContext(ctx, batchSeries, batch...)
+			}
+			if err != nil {
+				return fmt.Errorf("Error importing tsmongo series %q: %q", batchSeries, err)
+			}
+		}
+		if batchKind == hourKind {
+			report.Hours += len(batch)
+		} else {
+			report.Samples += len(batch)
+		}
+		batch = batch[:0]
+		if opts.Progress != nil {
+			opts.Progress(report)
+		}
+		return nil
+	}
+	dec := newRecordDecoder(r, f)
+	for {
+		rec, err := dec.decode()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return report, fmt.Errorf("Error decoding tsmongo records: %q", err)
+		}
+		v, err := decodeExportValue(rec.Type, rec.Value)
+		if err != nil {
+			return report, fmt.Errorf("Error decoding tsmongo records: line %d: %q", dec.line, err)
+		}
+		series := rec.series()
+		if series.key() != batchSeries.key() || rec.Kind != batchKind || len(batch) >= opts.BatchSize {
+			if err := flush(); err != nil {
+				return report, err
+			}
+			batchSeries, batchKind = series, rec.Kind
+		}
+		if !seen[series.key()] {
+			seen[series.key()] = true
+			report.Series++
+		}
+		batch = append(batch, TSRecord{rec.Timestamp, v})
+	}
+	return report, flush()
+}
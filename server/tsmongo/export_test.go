@@ -0,0 +1,101 @@
+package tsmongo
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/danielfireman/temp-to-go/server/weather"
+)
+
+func TestExportImport(t *testing.T) {
+	ctx := context.Background()
+	base := time.Date(2018, 7, 1, 10, 0, 0, 0, time.UTC)
+	src := NewMemStore()
+	src.UpsertContext(ctx, bedroomSeries, TSRecord{base.Add(-time.Hour), 24.0})
+	bs := NewBedroomService(src, DefaultRoom)
+	bs.UpdateTemperature(base.Add(10*time.Second), 25)
+	bs.UpdateTemperature(base.Add(30*time.Minute), 26)
+	NewFanService(src, "kitchen").UpdateStatus(base, FanHighSpeed)
+	NewWeatherService(src).Update(weather.State{Timestamp: base, Temp: 30, Wind: weather.Wind{Speed: 2}})
+	NewSensorService(src, DefaultRoom).Update(base, SensorReading{Metric: Humidity, Value: 60})
+
+	all, err := src.ListSeries("")
+	if err != nil {
+		t.Fatalf("ListSeries: %q", err)
+	}
+	var ranges []ExportRange
+	for _, s := range all {
+		ranges = append(ranges, ExportRange{s, base.Add(-24 * time.Hour), base.Add(24 * time.Hour)})
+	}
+
+	for name, f := range map[string]Format{"jsonl": JSONLines, "csv": CSV} {
+		t.Run(name, func(t *testing.T) {
+			var exported bytes.Buffer
+			n, err := Export(ctx, src, &exported, f, ranges...)
+			if err != nil {
+				t.Fatalf("Export: %q", err)
+			}
+			if n != 6 {
+				t.Errorf("Export count want:6 got:%d", n)
+			}
+
+			dst := NewMemStore()
+			report, err := Import(ctx, dst, bytes.NewReader(exported.Bytes()), f, ImportOptions{DryRun: true})
+			if err != nil {
+				t.Fatalf("Import(DryRun): %q", err)
+			}
+			if report.Records() != n || report.Samples != 2 || report.Series != 4 {
+				t.Errorf("Import(DryRun) want:%d records, 2 samples, 4 series got:%s", n, report)
+			}
+			if series, _ := dst.ListSeries(""); len(series) != 0 {
+				t.Errorf("Import(DryRun) must not store records, got series:%v", series)
+			}
+
+			// Importing twice must be the same as importing once.
+			progress := 0
+			for i := 0; i < 2; i++ {
+				_, err := Import(ctx, dst, bytes.NewReader(exported.Bytes()), f, ImportOptions{BatchSize: 1, Progress: func(ImportReport) { progress++ }})
+				if err != nil {
+					t.Fatalf("Import: %q", err)
+				}
+			}
+			if progress != 2*n {
+				t.Errorf("Progress calls want:%d got:%d", 2*n, progress)
+			}
+			var reexported bytes.Buffer
+			if _, err := Export(ctx, dst, &reexported, f, ranges...); err != nil {
+				t.Fatalf("Export: %q", err)
+			}
+			if exported.String() != reexported.String() {
+				t.Errorf("Round trip want:\n%s\ngot:\n%s", exported.String(), reexported.String())
+			}
+
+			// Typed services must be able to read imported data.
+			fan, err := NewFanService(dst, "kitchen").FetchState(base, base, Hourly)
+			if err != nil || len(fan) != 1 || fan[0].Status != FanHighSpeed {
+				t.Errorf("FetchState want:[%v] got:%+v (%v)", FanHighSpeed, fan, err)
+			}
+			ws, err := NewWeatherService(dst).Fetch(base, base)
+			if err != nil || len(ws) != 1 || ws[0].Temp != 30 || ws[0].Wind.Speed != 2 {
+				t.Errorf("Fetch want:30C got:%+v (%v)", ws, err)
+			}
+			hourly, err := NewBedroomService(dst, DefaultRoom).FetchState(base, base, Hourly)
+			if err != nil {
+				t.Fatalf("FetchState: %q", err)
+			}
+			checkStates(t, []BedroomState{{base, 26}}, hourly)
+		})
+	}
+}
+
+func TestImportInvalid(t *testing.T) {
+	in := `{"field":"bedroom","room":"default","timestamp":"2018-07-01T10:00:00Z","kind":"hour","type":"float","value":25}
+{"field":"bedroom","room":"default","timestamp":"2018-07-01T11:00:00Z","kind":"minute","type":"float","value":26}
+`
+	report, err := Import(context.Background(), NewMemStore(), bytes.NewReader([]byte(in)), JSONLines, ImportOptions{})
+	if err == nil {
+		t.Errorf("Import of invalid kind want error got:%s", report)
+	}
+}
@@ -1,59 +1,226 @@
 package tsmongo
 
 import (
+	"context"
 	"fmt"
 	"time"
-
-	"github.com/globalsign/mgo"
 )
 
 const fanField = "fan"
 
-// FanService allows the user to update and get information about the bedroom fan.
+// FanService allows the user to update and get information about the fan of a bedroom. The fan
+// is the ApplianceFan appliance, with a status for each of its speeds.
 type FanService struct {
-	session *Session
+	store  Store
+	series Series
+}
+
+// Room returns the room the service is bound to.
+func (f FanService) Room() string {
+	return f.series.Room
+}
+
+// WithTags returns a copy of the service which stores the passed-in tags along with every
+// update (e.g. the fan model).
+func (f FanService) WithTags(tags map[string]string) *FanService {
+	series := f.series
+	series.Tags = tags
+	return &FanService{f.store, series}
 }
 
 // ErrInvalidFanStatus represent invalid fan status.
 var ErrInvalidFanStatus = fmt.Errorf("Invalid status")
 
-// UpdateStatus changes the fan status at specified time, updating the database.
+// UpdateStatus changes the fan status at specified time, updating the database. Every change
+// is kept as a sample, with one second resolution. The source of the change is unknown, see
+// UpdateStatusBy.
 func (f FanService) UpdateStatus(t time.Time, s FanStatus) error {
+	return f.UpdateStatusContext(context.Background(), t, s)
+}
+
+// UpdateStatusContext is like UpdateStatus, but honors the context deadline and cancellation.
+func (f FanService) UpdateStatusContext(ctx context.Context, t time.Time, s FanStatus) error {
+	return f.UpdateStatusByContext(ctx, t, s, FanChange{})
+}
+
+// UpdateStatusBy is like UpdateStatus, but also records who changed the status and why.
+func (f FanService) UpdateStatusBy(t time.Time, s FanStatus, change FanChange) error {
+	return f.UpdateStatusByContext(context.Background(), t, s, change)
+}
+
+// UpdateStatusByContext is like UpdateStatusBy, but honors the context deadline and cancellation.
+func (f FanService) UpdateStatusByContext(ctx context.Context, t time.Time, s FanStatus, change FanChange) error {
 	if s < FanOff || s > FanHighSpeed {
 		return ErrInvalidFanStatus
 	}
+	return f.appliance().UpdateStateContext(ctx, ApplianceState{Timestamp: t, On: s != FanOff, Speed: int(s), FanChange: change})
+}
+
+// DeleteRange removes the fan status changes within the range (inclusive), returning how many
+// were removed. Removed values are kept in the audit trail of the series (see AuditTrail).
+func (f FanService) DeleteRange(start time.Time, finish time.Time, e Edit) (int, error) {
+	return f.DeleteRangeContext(context.Background(), start, finish, e)
+}
+
+// DeleteRangeContext is like DeleteRange, but honors the context deadline and cancellation.
+func (f FanService) DeleteRangeContext(ctx context.Context, start time.Time, finish time.Time, e Edit) (int, error) {
+	return f.appliance().DeleteRangeContext(ctx, start, finish, e)
+}
+
+// CorrectStatus changes the fan status set at t, and who changed it and why (unless empty),
+// keeping the former values in the audit trail of the series. It returns ErrNotFound if the
+// status was not changed at t.
+func (f FanService) CorrectStatus(t time.Time, s FanStatus, change FanChange, e Edit) error {
+	return f.CorrectStatusContext(context.Background(), t, s, change, e)
+}
 
-	return f.session.Upsert(fanField, TSRecord{t, s})
+// CorrectStatusContext is like CorrectStatus, but honors the context deadline and cancellation.
+func (f FanService) CorrectStatusContext(ctx context.Context, t time.Time, s FanStatus, change FanChange, e Edit) error {
+	if s < FanOff || s > FanHighSpeed {
+		return ErrInvalidFanStatus
+	}
+	return f.appliance().CorrectStateContext(ctx, ApplianceState{Timestamp: t, On: s != FanOff, Speed: int(s), FanChange: change}, e)
 }
 
 // LastState returns the last fan status.
 func (f FanService) LastState() (FanState, error) {
-	ts, err := f.session.Last(fanField)
-	switch err {
-	case nil:
-		s := ts.Value.(int)
-		return FanState{ts.Timestamp, FanStatus(s)}, nil
-	case mgo.ErrNotFound:
-		return FanState{time.Now(), FanOff}, nil
-	default:
-		return FanState{time.Now(), FanOff}, err
+	return f.LastStateContext(context.Background())
+}
+
+// LastStateContext is like LastState, but honors the context deadline and cancellation.
+func (f FanService) LastStateContext(ctx context.Context) (FanState, error) {
+	s, err := f.appliance().LastStateContext(ctx)
+	return fanState(s), err
+}
+
+// FetchState returns the fan status updates in the considered period, either hourly or at
+// full resolution, along with who changed them. Hourly states carry the last change within the
+// hour.
+func (f FanService) FetchState(start time.Time, finish time.Time, res Resolution) ([]FanState, error) {
+	return f.FetchStateContext(context.Background(), start, finish, res)
+}
+
+// FetchStateContext is like FetchState, but honors the context deadline and cancellation.
+func (f FanService) FetchStateContext(ctx context.Context, start time.Time, finish time.Time, res Resolution) ([]FanState, error) {
+	states, err := f.appliance().FetchStateContext(ctx, start, finish, res)
+	return fanStates(states), err
+}
+
+// History returns every fan status change within the considered period, along with who changed
+// it and why, sorted by ascending timestamp.
+func (f FanService) History(start time.Time, finish time.Time) ([]FanState, error) {
+	return f.HistoryContext(context.Background(), start, finish)
+}
+
+// HistoryContext is like History, but honors the context deadline and cancellation.
+func (f FanService) HistoryContext(ctx context.Context, start time.Time, finish time.Time) ([]FanState, error) {
+	states, err := f.appliance().HistoryContext(ctx, start, finish)
+	return fanStates(states), err
+}
+
+// appliance returns the appliance service of the fan, which stores the fan states.
+func (f FanService) appliance() *ApplianceService {
+	return &ApplianceService{f.store, ApplianceFan, Appliances[ApplianceFan], f.series}
+}
+
+func fanState(s ApplianceState) FanState {
+	return FanState{s.Timestamp, FanStatus(s.Speed), s.FanChange}
+}
+
+func fanStates(states []ApplianceState) []FanState {
+	if states == nil {
+		return nil
+	}
+	ret := make([]FanState, len(states))
+	for i, s := range states {
+		ret[i] = fanState(s)
 	}
+	return ret
 }
 
-// FetchState returns the fan status updates in the considered period. Important to n
-func (f FanService) FetchState(start time.Time, finish time.Time) ([]FanState, error) {
-	trs, err := f.session.Query(fanField, start, finish)
+// FetchAllRooms returns the fan state updates of every room within the considered period, keyed
+// by room, regardless of the room the service is bound to.
+func (f FanService) FetchAllRooms(start time.Time, finish time.Time, res Resolution) (map[string][]FanState, error) {
+	return f.FetchAllRoomsContext(context.Background(), start, finish, res)
+}
+
+// FetchAllRoomsContext is like FetchAllRooms, but honors the context deadline and cancellation.
+func (f FanService) FetchAllRoomsContext(ctx context.Context, start time.Time, finish time.Time, res Resolution) (map[string][]FanState, error) {
+	series, err := f.store.ListSeriesContext(ctx, fanField)
 	if err != nil {
 		return nil, err
 	}
-	ret := make([]FanState, len(trs))
-	for i := range trs {
-		s := trs[i].Value.(int)
-		ret[i] = FanState{trs[i].Timestamp, FanStatus(s)}
+	ret := make(map[string][]FanState, len(series))
+	for _, s := range series {
+		states, err := FanService{f.store, s}.FetchStateContext(ctx, start, finish, res)
+		if err != nil {
+			return nil, err
+		}
+		ret[s.Room] = states
 	}
 	return ret, nil
 }
 
+// StreamState calls fn for each fan state update in the considered period, in the specified
+// order. Unlike FetchState, updates are not loaded into memory all at once, and who changed them
+// is not fetched: states are streamed with an empty FanChange (see History).
+func (f FanService) StreamState(start time.Time, finish time.Time, res Resolution, order Order, fn func(FanState) error) error {
+	return f.StreamStateContext(context.Background(), start, finish, res, order, fn)
+}
+
+// StreamStateContext is like StreamState, but honors the context deadline and cancellation.
+func (f FanService) StreamStateContext(ctx context.Context, start time.Time, finish time.Time, res Resolution, order Order, fn func(FanState) error) error {
+	return f.store.StreamContext(ctx, f.series, start, finish, res, order, func(tr TSRecord) error {
+		s, err := decodeInt(f.series, tr)
+		if err != nil {
+			return err
+		}
+		return fn(FanState{tr.Timestamp, FanStatus(s), FanChange{}})
+	})
+}
+
+// FanWatcher delivers fan state updates as they arrive.
+type FanWatcher struct {
+	// C receives the new fan states. It is closed when the watcher stops.
+	C <-chan FanState
+	*watcher
+}
+
+// Watch subscribes to fan status updates written after the call (see the package Watch for
+// how updates are detected). The watcher stops when closed, when the context is done or at the
+// first error. Who changed the status is stored after the status itself, so states are
+// delivered with an empty FanChange (see History).
+func (f FanService) Watch(ctx context.Context) (*FanWatcher, error) {
+	c := make(chan FanState)
+	w, err := startWatch(ctx, f.store, f.series, func(ctx context.Context, tr TSRecord) bool {
+		s, err := decodeInt(f.series, tr)
+		if err != nil {
+			return true // Records which can not be decoded are skipped.
+		}
+		select {
+		case c <- FanState{tr.Timestamp, FanStatus(s), FanChange{}}:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}, func() { close(c) })
+	if err != nil {
+		return nil, err
+	}
+	return &FanWatcher{C: c, watcher: w}, nil
+}
+
+// Aggregate aggregates the fan status samples into time windows. For instance, the mean is the
+// average speed within the window.
+func (f FanService) Aggregate(q AggregateQuery) ([]AggregateBucket, error) {
+	return f.AggregateContext(context.Background(), q)
+}
+
+// AggregateContext is like Aggregate, but honors the context deadline and cancellation.
+func (f FanService) AggregateContext(ctx context.Context, q AggregateQuery) ([]AggregateBucket, error) {
+	return f.store.AggregateContext(ctx, f.series, "", q)
+}
+
 // FanStatus represents the speed/power of the bedroom fan.
 type FanStatus int
 
@@ -64,8 +231,24 @@ const (
 	FanHighSpeed FanStatus = 2
 )
 
-// FanState stores the state of the fan at a certain moment.
+// FanState stores the state of the fan at a certain moment, and who changed it.
 type FanState struct {
 	Timestamp time.Time `json:"timestamp,omitempty"`
 	Status    FanStatus `json:"status,omitempty"`
+	FanChange
+}
+
+// Enumerates the known sources of fan status changes.
+const (
+	FanSourceWeb        = "web"        // The fan form of the web UI.
+	FanSourceScript     = "script"     // Scripts using the API directly.
+	FanSourceAutomation = "automation" // Rules changing the fan by themselves.
+)
+
+// FanChange describes who changed the fan status (or the state of any appliance) and why.
+// Changes recorded before sources existed, or through UpdateStatus, have no source.
+type FanChange struct {
+	Source string `json:"source,omitempty"`
+	Actor  string `json:"actor,omitempty"` // The user or device ID.
+	Reason string `json:"reason,omitempty"`
 }
@@ -0,0 +1,75 @@
+package tsmongo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFanChanges(t *testing.T) {
+	fs := NewFanService(NewMemStore(), DefaultRoom)
+	base := time.Date(2018, 7, 1, 10, 0, 0, 0, time.UTC)
+	byUser := FanChange{Source: FanSourceWeb, Actor: "daniel", Reason: "too hot"}
+	byRule := FanChange{Source: FanSourceAutomation, Actor: "night-rule"}
+	fs.UpdateStatus(base, FanLowSpeed)
+	fs.UpdateStatusBy(base.Add(10*time.Minute), FanHighSpeed, byUser)
+	fs.UpdateStatusBy(base.Add(time.Hour+5*time.Second), FanOff, byRule)
+	if err := fs.UpdateStatusBy(base, FanStatus(7), byUser); err != ErrInvalidFanStatus {
+		t.Errorf("UpdateStatusBy invalid status want:%q got:%q", ErrInvalidFanStatus, err)
+	}
+
+	got, err := fs.History(base, base.Add(2*time.Hour))
+	if err != nil {
+		t.Fatalf("History: %q", err)
+	}
+	want := []FanState{
+		{base, FanLowSpeed, FanChange{}},
+		{base.Add(10 * time.Minute), FanHighSpeed, byUser},
+		{base.Add(time.Hour + 5*time.Second), FanOff, byRule},
+	}
+	checkFanStates(t, want, got)
+
+	// History is limited to the window.
+	got, err = fs.History(base.Add(time.Minute), base.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("History: %q", err)
+	}
+	checkFanStates(t, want[1:2], got)
+
+	// Hourly states carry the change of the newest status within the hour.
+	got, err = fs.FetchState(base, base.Add(time.Hour), Hourly)
+	if err != nil {
+		t.Fatalf("FetchState: %q", err)
+	}
+	checkFanStates(t, []FanState{{base.Add(time.Hour), FanOff, byRule}, {base, FanHighSpeed, byUser}}, got)
+
+	last, err := fs.LastState()
+	if err != nil {
+		t.Fatalf("LastState: %q", err)
+	}
+	if last.Status != FanOff || last.FanChange != byRule {
+		t.Errorf("LastState want:%+v got:%+v", byRule, last)
+	}
+
+	// Changes of earlier statuses within the hour are not attached to later ones.
+	fs.UpdateStatus(base.Add(time.Hour+30*time.Minute), FanLowSpeed)
+	if last, err := fs.LastState(); err != nil || last.Status != FanLowSpeed || last.FanChange != (FanChange{}) {
+		t.Errorf("LastState want low speed without change got:%+v (%v)", last, err)
+	}
+	got, err = fs.FetchState(base.Add(time.Hour), base.Add(time.Hour), Hourly)
+	if err != nil {
+		t.Fatalf("FetchState: %q", err)
+	}
+	checkFanStates(t, []FanState{{base.Add(time.Hour), FanLowSpeed, FanChange{}}}, got)
+}
+
+func checkFanStates(t *testing.T, want, got []FanState) {
+	t.Helper()
+	if len(want) != len(got) {
+		t.Fatalf("want:%+v got:%+v", want, got)
+	}
+	for i := range want {
+		if !want[i].Timestamp.Equal(got[i].Timestamp) || want[i].Status != got[i].Status || want[i].FanChange != got[i].FanChange {
+			t.Errorf("[%d] want:%+v got:%+v", i, want[i], got[i])
+		}
+	}
+}
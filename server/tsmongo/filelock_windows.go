@@ -0,0 +1,13 @@
+package tsmongo
+
+import "os"
+
+// File locking is not supported on windows, the data file must not be shared among processes.
+
+func lockFile(f *os.File) error {
+	return nil
+}
+
+func unlockFile(f *os.File) error {
+	return nil
+}
@@ -0,0 +1,434 @@
+package tsmongo
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+const (
+	fileURIScheme = "file://"
+
+	// The data file is compacted when opened if less than 1/compactionRatio of its records are live.
+	compactionRatio = 2
+)
+
+// FileStore is an embedded Store backed by a single data file, which allows the stack to run
+// without a mongo server. The file is an append-only log of BSON documents with the same
+// shape mongo stores (type, room, timestamp_hour, value and sub-hour samples), replayed in order to
+// rebuild the hour buckets. All records are indexed in memory and queries are answered by a
+// MemStore.
+//
+// The file can be shared by many processes (e.g. the web server and the workers): writes
+// are serialized through an advisory file lock and every operation catches up with records
+// appended by other processes before running.
+type FileStore struct {
+	path string
+
+	mu      sync.Mutex // Protects the fields below.
+	file    *os.File
+	offset  int64 // Position up to where the file was loaded into mem.
+	records int   // Number of records loaded from the file.
+	mem     *MemStore
+}
+
+// fileRecord is the unit of storage of the FileStore data file. It either replaces a whole
+// hour bucket, stores a sample (at Timestamp) within its bucket if Sample is set, removes the
+// buckets from Timestamp until Until if Remove is set or removes the sample at Timestamp if
+// both are set.
+type fileRecord struct {
+	Type      string                            `bson:"type"`
+	Room      string                            `bson:"room,omitempty"`
+	Tags      map[string]string                 `bson:"tags,omitempty"`
+	Timestamp time.Time                         `bson:"timestamp_hour"`
+	Value     interface{}                       `bson:"value"`
+	Samples   map[string]map[string]interface{} `bson:"values,omitempty"`
+	Sample    bool                              `bson:"sample,omitempty"`
+	Remove    bool                              `bson:"remove,omitempty"`
+	Until     time.Time                         `bson:"until,omitempty"`
+}
+
+// apply applies the record to the memory index. Callers must hold fs.mem write lock.
+func (r fileRecord) apply(m *MemStore) int {
+	series := Series{r.Type, r.Room, r.Tags}
+	if r.Room == "" {
+		// Records written before rooms existed.
+		series.Room = legacyRoom(r.Type)
+	}
+	switch {
+	case r.Remove && r.Sample:
+		if m.removeSample(series, r.Timestamp) {
+			return 1
+		}
+	case r.Remove:
+		return m.removeRange(series, r.Timestamp, r.Until)
+	case r.Sample:
+		m.setSample(series, r.Timestamp, r.Value)
+	default:
+		m.set(series, bucket{Timestamp: r.Timestamp, Value: r.Value, Samples: r.Samples})
+	}
+	return 0
+}
+
+// OpenFile opens (creating if necessary) the data file at the specified path and loads it.
+func OpenFile(path string) (*FileStore, error) {
+	fs := &FileStore{path: path}
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if err := fs.reopen(); err != nil {
+		return nil, err
+	}
+	if err := fs.compactIfNeeded(); err != nil {
+		fs.file.Close()
+		return nil, err
+	}
+	return fs, nil
+}
+
+// Upsert inserts the given data into the timeseries overriding the data if necessary.
+// All records are written to the data file in a single write.
+func (fs *FileStore) Upsert(series Series, val ...TSRecord) error {
+	return fs.UpsertContext(context.Background(), series, val...)
+}
+
+// UpsertContext is like Upsert, but returns right away if the context is done.
+func (fs *FileStore) UpsertContext(ctx context.Context, series Series, val ...TSRecord) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return fs.write(series, false, val)
+}
+
+// UpsertSamples stores the given data as samples within their hour buckets, also updating the
+// hourly value. All records are written to the data file in a single write.
+func (fs *FileStore) UpsertSamples(series Series, val ...TSRecord) error {
+	return fs.UpsertSamplesContext(context.Background(), series, val...)
+}
+
+// UpsertSamplesContext is like UpsertSamples, but returns right away if the context is done.
+func (fs *FileStore) UpsertSamplesContext(ctx context.Context, series Series, val ...TSRecord) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return fs.write(series, true, val)
+}
+
+func (fs *FileStore) write(series Series, sample bool, val []TSRecord) error {
+	if len(val) == 0 {
+		return nil
+	}
+	values, err := encodeValues(val)
+	if err != nil {
+		return err
+	}
+	recs := make([]fileRecord, len(val))
+	for i := range val {
+		recs[i] = fileRecord{Type: series.Field, Room: series.Room, Tags: series.Tags, Timestamp: hourUTC(val[i].Timestamp), Value: values[i], Sample: sample}
+		if sample {
+			// Samples keep their full timestamp (truncated to the second, as mongo keys do).
+			recs[i].Timestamp = val[i].Timestamp.In(time.UTC).Truncate(time.Second)
+		}
+	}
+	_, err = fs.append(recs...)
+	return err
+}
+
+// append writes the records to the data file in a single write and applies them to the memory
+// index, returning the number of buckets removed.
+func (fs *FileStore) append(recs ...fileRecord) (int, error) {
+	var buf []byte
+	for _, r := range recs {
+		b, err := bson.Marshal(r)
+		if err != nil {
+			return 0, err
+		}
+		buf = append(buf, b...)
+	}
+
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if err := fs.lockAndSync(); err != nil {
+		return 0, err
+	}
+	defer unlockFile(fs.file)
+	if _, err := fs.file.Write(buf); err != nil {
+		return 0, fmt.Errorf("error writing to %s: %q", fs.path, err)
+	}
+	if err := fs.file.Sync(); err != nil {
+		return 0, fmt.Errorf("error syncing %s: %q", fs.path, err)
+	}
+	fs.offset += int64(len(buf))
+	fs.records += len(recs)
+	fs.mem.mu.Lock()
+	defer fs.mem.mu.Unlock()
+	removed := 0
+	for _, r := range recs {
+		removed += r.apply(fs.mem)
+	}
+	return removed, nil
+}
+
+// RemoveRange removes the hour buckets of the series within the specified range.
+func (fs *FileStore) RemoveRange(series Series, start time.Time, finish time.Time) (int, error) {
+	return fs.RemoveRangeContext(context.Background(), series, start, finish)
+}
+
+// RemoveRangeContext is like RemoveRange, but returns right away if the context is done.
+func (fs *FileStore) RemoveRangeContext(ctx context.Context, series Series, start time.Time, finish time.Time) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	return fs.append(fileRecord{Type: series.Field, Room: series.Room, Tags: series.Tags, Timestamp: start.In(time.UTC), Until: finish.In(time.UTC), Remove: true})
+}
+
+// RemoveSamples removes the samples of the series taken at the specified times (with one second
+// resolution), returning how many were removed. The hourly value of their buckets becomes the
+// newest remaining sample, and buckets left empty are removed.
+func (fs *FileStore) RemoveSamples(series Series, t ...time.Time) (int, error) {
+	return fs.RemoveSamplesContext(context.Background(), series, t...)
+}
+
+// RemoveSamplesContext is like RemoveSamples, but returns right away if the context is done.
+func (fs *FileStore) RemoveSamplesContext(ctx context.Context, series Series, t ...time.Time) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	if len(t) == 0 {
+		return 0, nil
+	}
+	recs := make([]fileRecord, len(t))
+	for i := range t {
+		recs[i] = fileRecord{Type: series.Field, Room: series.Room, Tags: series.Tags, Timestamp: sampleTime(t[i]), Sample: true, Remove: true}
+	}
+	return fs.append(recs...)
+}
+
+// Query fetches all records from the timeseries within the specified range.
+func (fs *FileStore) Query(series Series, start time.Time, finish time.Time) ([]TSRecord, error) {
+	return fs.QueryContext(context.Background(), series, start, finish)
+}
+
+// QueryContext is like Query, but returns right away if the context is done.
+func (fs *FileStore) QueryContext(ctx context.Context, series Series, start time.Time, finish time.Time) ([]TSRecord, error) {
+	mem, err := fs.sync(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return mem.QueryContext(ctx, series, start, finish)
+}
+
+// QuerySamples fetches all samples from the timeseries within the specified range.
+func (fs *FileStore) QuerySamples(series Series, start time.Time, finish time.Time) ([]TSRecord, error) {
+	return fs.QuerySamplesContext(context.Background(), series, start, finish)
+}
+
+// QuerySamplesContext is like QuerySamples, but returns right away if the context is done.
+func (fs *FileStore) QuerySamplesContext(ctx context.Context, series Series, start time.Time, finish time.Time) ([]TSRecord, error) {
+	mem, err := fs.sync(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return mem.QuerySamplesContext(ctx, series, start, finish)
+}
+
+// Last returns the last element in the timeseries, if any.
+func (fs *FileStore) Last(series Series) (TSRecord, error) {
+	return fs.LastContext(context.Background(), series)
+}
+
+// LastContext is like Last, but returns right away if the context is done.
+func (fs *FileStore) LastContext(ctx context.Context, series Series) (TSRecord, error) {
+	mem, err := fs.sync(ctx)
+	if err != nil {
+		return TSRecord{}, err
+	}
+	return mem.LastContext(ctx, series)
+}
+
+// Close closes the data file.
+func (fs *FileStore) Close() {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.file.Close()
+}
+
+// sync loads records appended by other processes and returns the up-to-date index.
+func (fs *FileStore) sync(ctx context.Context) (*MemStore, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if err := fs.catchUp(); err != nil {
+		return nil, err
+	}
+	return fs.mem, nil
+}
+
+// lockAndSync acquires the exclusive file lock and catches up with the data file, making sure
+// the locked file is still the one at fs.path (it might have been compacted in the meantime).
+// Callers must hold fs.mu and release the file lock.
+func (fs *FileStore) lockAndSync() error {
+	for {
+		if err := lockFile(fs.file); err != nil {
+			return fmt.Errorf("error locking %s: %q", fs.path, err)
+		}
+		same, err := fs.isCurrent()
+		if err != nil {
+			unlockFile(fs.file)
+			return err
+		}
+		if same {
+			if err := fs.load(); err != nil {
+				unlockFile(fs.file)
+				return err
+			}
+			// Holding the lock, any bytes after the last complete record come from an
+			// interrupted write and must be discarded before appending.
+			if err := fs.file.Truncate(fs.offset); err != nil {
+				unlockFile(fs.file)
+				return fmt.Errorf("error truncating %s: %q", fs.path, err)
+			}
+			return nil
+		}
+		unlockFile(fs.file)
+		if err := fs.reopen(); err != nil {
+			return err
+		}
+	}
+}
+
+// catchUp loads the records appended to the data file since the last load. Callers must hold fs.mu.
+func (fs *FileStore) catchUp() error {
+	same, err := fs.isCurrent()
+	if err != nil {
+		return err
+	}
+	if !same {
+		return fs.reopen()
+	}
+	return fs.load()
+}
+
+// isCurrent returns whether the open file is the one currently at fs.path.
+func (fs *FileStore) isCurrent() (bool, error) {
+	pathInfo, err := os.Stat(fs.path)
+	if err != nil {
+		return false, fmt.Errorf("error checking %s: %q", fs.path, err)
+	}
+	fileInfo, err := fs.file.Stat()
+	if err != nil {
+		return false, fmt.Errorf("error checking %s: %q", fs.path, err)
+	}
+	return os.SameFile(pathInfo, fileInfo), nil
+}
+
+// reopen (re)opens the data file and loads it from scratch. Callers must hold fs.mu.
+func (fs *FileStore) reopen() error {
+	f, err := os.OpenFile(fs.path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("error opening %s: %q", fs.path, err)
+	}
+	if fs.file != nil {
+		fs.file.Close()
+	}
+	fs.file = f
+	fs.offset = 0
+	fs.records = 0
+	fs.mem = NewMemStore()
+	return fs.load()
+}
+
+// load reads the records after fs.offset into memory. A truncated trailing record (e.g. a write
+// interrupted by a crash) is ignored. Callers must hold fs.mu.
+func (fs *FileStore) load() error {
+	info, err := fs.file.Stat()
+	if err != nil {
+		return fmt.Errorf("error checking %s: %q", fs.path, err)
+	}
+	if info.Size() <= fs.offset {
+		return nil
+	}
+	r := bufio.NewReader(io.NewSectionReader(fs.file, fs.offset, info.Size()-fs.offset))
+	fs.mem.mu.Lock()
+	defer fs.mem.mu.Unlock()
+	for {
+		var size [4]byte
+		if _, err := io.ReadFull(r, size[:]); err != nil {
+			return nil // EOF or truncated length.
+		}
+		l := int(binary.LittleEndian.Uint32(size[:]))
+		if l < len(size) {
+			return fmt.Errorf("corrupted data file %s at offset %d", fs.path, fs.offset)
+		}
+		doc := make([]byte, l)
+		copy(doc, size[:])
+		if _, err := io.ReadFull(r, doc[len(size):]); err != nil {
+			return nil // Truncated document.
+		}
+		var rec fileRecord
+		if err := bson.Unmarshal(doc, &rec); err != nil {
+			return fmt.Errorf("corrupted data file %s at offset %d: %q", fs.path, fs.offset, err)
+		}
+		rec.apply(fs.mem)
+		fs.offset += int64(l)
+		fs.records++
+	}
+}
+
+// compactIfNeeded rewrites the data file keeping only live records, if it has too many
+// overridden ones. Callers must hold fs.mu.
+func (fs *FileStore) compactIfNeeded() error {
+	if err := fs.lockAndSync(); err != nil {
+		return err
+	}
+	defer unlockFile(fs.file)
+
+	live := 0
+	for _, hours := range fs.mem.fields {
+		live += len(hours)
+	}
+	if fs.records <= compactionRatio*live {
+		return nil
+	}
+
+	tmpPath := fs.path + ".tmp"
+	tmp, err := os.OpenFile(tmpPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("error compacting %s: %q", fs.path, err)
+	}
+	w := bufio.NewWriter(tmp)
+	for k, hours := range fs.mem.fields {
+		for h, bkt := range hours {
+			b, err := bson.Marshal(fileRecord{Type: k.field, Room: k.room, Tags: fs.mem.tags[k], Timestamp: h, Value: bkt.Value, Samples: bkt.Samples})
+			if err != nil {
+				tmp.Close()
+				return err
+			}
+			if _, err := w.Write(b); err != nil {
+				tmp.Close()
+				return fmt.Errorf("error compacting %s: %q", fs.path, err)
+			}
+		}
+	}
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error compacting %s: %q", fs.path, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error compacting %s: %q", fs.path, err)
+	}
+	tmp.Close()
+	if err := os.Rename(tmpPath, fs.path); err != nil {
+		return fmt.Errorf("error compacting %s: %q", fs.path, err)
+	}
+	return fs.reopen()
+}
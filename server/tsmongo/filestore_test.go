@@ -0,0 +1,144 @@
+package tsmongo
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileStore_SurvivesRestart(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "filestore_testing")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "ts.db")
+	base := time.Date(2018, 7, 1, 10, 0, 0, 0, time.UTC)
+
+	s, err := Open(fileURIScheme + path)
+	if err != nil {
+		t.Fatalf("Open: %q", err)
+	}
+	NewBedroomService(s, DefaultRoom).UpdateTemperature(base, 25)
+	NewBedroomService(s, DefaultRoom).UpdateTemperature(base.Add(time.Minute), 26) // Overrides, same hour.
+	NewFanService(s, DefaultRoom).UpdateStatus(base.Add(time.Hour), FanHighSpeed)
+	s.Close()
+
+	s, err = OpenFile(path)
+	if err != nil {
+		t.Fatalf("OpenFile: %q", err)
+	}
+	defer s.Close()
+	bs, err := NewBedroomService(s, DefaultRoom).FetchState(base, base.Add(time.Hour), Hourly)
+	if err != nil {
+		t.Fatalf("FetchState: %q", err)
+	}
+	if len(bs) != 1 || !bs[0].Timestamp.Equal(base) || bs[0].Temperature != 26 {
+		t.Errorf("FetchState want:[{%v 26}] got:%+v", base, bs)
+	}
+	fs, err := NewFanService(s, DefaultRoom).LastState()
+	if err != nil {
+		t.Fatalf("LastState: %q", err)
+	}
+	if !fs.Timestamp.Equal(base.Add(time.Hour)) || fs.Status != FanHighSpeed {
+		t.Errorf("LastState want:{%v %v} got:%+v", base.Add(time.Hour), FanHighSpeed, fs)
+	}
+}
+
+func TestFileStore_SharedAmongProcesses(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "filestore_testing")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "ts.db")
+	base := time.Date(2018, 7, 1, 10, 0, 0, 0, time.UTC)
+
+	web, err := OpenFile(path)
+	if err != nil {
+		t.Fatalf("OpenFile: %q", err)
+	}
+	defer web.Close()
+	worker, err := OpenFile(path)
+	if err != nil {
+		t.Fatalf("OpenFile: %q", err)
+	}
+	defer worker.Close()
+
+	if err := worker.Upsert(bedroomSeries, TSRecord{base, 27.0}); err != nil {
+		t.Fatalf("Upsert: %q", err)
+	}
+	r, err := web.Last(bedroomSeries)
+	if err != nil {
+		t.Fatalf("Last: %q", err)
+	}
+	if r.Value != 27.0 {
+		t.Errorf("Last want:27 got:%v", r.Value)
+	}
+}
+
+func TestFileStore_CompactionAndTruncation(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "filestore_testing")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "ts.db")
+	base := time.Date(2018, 7, 1, 10, 0, 0, 0, time.UTC)
+
+	s, err := OpenFile(path)
+	if err != nil {
+		t.Fatalf("OpenFile: %q", err)
+	}
+	for i := 0; i < 10; i++ {
+		s.Upsert(bedroomSeries, TSRecord{base, float64(i)})
+	}
+	s.Close()
+	before, _ := os.Stat(path)
+
+	// Simulating a write interrupted in the middle.
+	f, _ := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	f.Write([]byte{0x20, 0, 0, 0, 1})
+	f.Close()
+
+	s, err = OpenFile(path)
+	if err != nil {
+		t.Fatalf("OpenFile: %q", err)
+	}
+	defer s.Close()
+	after, _ := os.Stat(path)
+	if after.Size() >= before.Size() {
+		t.Errorf("data file not compacted, size before:%d after:%d", before.Size(), after.Size())
+	}
+	r, err := s.Last(bedroomSeries)
+	if err != nil {
+		t.Fatalf("Last: %q", err)
+	}
+	if r.Value != 9.0 {
+		t.Errorf("Last want:9 got:%v", r.Value)
+	}
+}
+
+func TestFileStore_DiscardsInterruptedWrite(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "filestore_testing")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "ts.db")
+	base := time.Date(2018, 7, 1, 10, 0, 0, 0, time.UTC)
+
+	s, _ := OpenFile(path)
+	s.Upsert(bedroomSeries, TSRecord{base, 25.0})
+	s.Close()
+
+	f, _ := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	f.Write([]byte{0x20, 0, 0, 0, 1})
+	f.Close()
+
+	s, _ = OpenFile(path)
+	if err := s.Upsert(bedroomSeries, TSRecord{base.Add(time.Hour), 26.0}); err != nil {
+		t.Fatalf("Upsert: %q", err)
+	}
+	s.Close()
+
+	s, _ = OpenFile(path)
+	defer s.Close()
+	trs, err := s.Query(bedroomSeries, base, base.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Query: %q", err)
+	}
+	if len(trs) != 2 {
+		t.Errorf("len(trs) want:2 got:%d", len(trs))
+	}
+}
@@ -0,0 +1,191 @@
+package tsmongo
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/danielfireman/temp-to-go/server/weather"
+)
+
+const (
+	forecastIssueField = "forecast.issue"
+
+	// maxForecastLead is the longest lead time of stored forecasts (OWM forecasts up to 5 days),
+	// which bounds the issues scanned when looking for the vintages of an hour.
+	maxForecastLead = 5 * 24 * time.Hour
+)
+
+var forecastIssueSeries = Series{Field: forecastIssueField}
+
+// ForecastVintage is the forecast of the weather at a certain hour, as issued at a certain time.
+type ForecastVintage struct {
+	Issued time.Time     `json:"issued"`
+	State  weather.State `json:"state"`
+}
+
+// Lead returns how far ahead of the forecasted hour the forecast was issued.
+func (v ForecastVintage) Lead() time.Duration {
+	return v.State.Timestamp.Sub(v.Issued)
+}
+
+// forecastIssue stores all states forecasted at a certain time. Issues are stored as samples
+// (keyed by issuance time) of the forecast issue series, so no vintage is ever overwritten.
+type forecastIssue struct {
+	States []forecastIssueState `bson:"states"`
+}
+
+type forecastIssueState struct {
+	Hour  time.Time    `bson:"hour"`
+	State weatherState `bson:"state"`
+}
+
+// UpdateIssued is like Update, but also keeps the forecast as issued at the specified time
+// (its vintage), so it is not lost when newer forecasts for the same hours arrive.
+func (wf *ForecastService) UpdateIssued(issued time.Time, states ...weather.State) error {
+	return wf.UpdateIssuedContext(context.Background(), issued, states...)
+}
+
+// UpdateIssuedContext is like UpdateIssued, but honors the context deadline and cancellation.
+func (wf *ForecastService) UpdateIssuedContext(ctx context.Context, issued time.Time, states ...weather.State) error {
+	if len(states) == 0 {
+		return nil
+	}
+	issue := forecastIssue{States: make([]forecastIssueState, len(states))}
+	for i, s := range states {
+		issue.States[i] = forecastIssueState{hourUTC(s.Timestamp), toStore(s)}
+	}
+	// The vintage is stored first, so the latest forecast is never missing from it.
+	if err := wf.store.UpsertSamplesContext(ctx, forecastIssueSeries, TSRecord{issued, issue}); err != nil {
+		return err
+	}
+	return wf.updateLatest(ctx, states...)
+}
+
+// Vintages returns all forecasts issued for the hour, sorted by descending issuance time.
+func (wf *ForecastService) Vintages(hour time.Time) ([]ForecastVintage, error) {
+	return wf.VintagesContext(context.Background(), hour)
+}
+
+// VintagesContext is like Vintages, but honors the context deadline and cancellation.
+func (wf *ForecastService) VintagesContext(ctx context.Context, hour time.Time) ([]ForecastVintage, error) {
+	h := hourUTC(hour)
+	byHour, err := wf.vintages(ctx, h, h)
+	if err != nil {
+		return nil, err
+	}
+	return byHour[h], nil
+}
+
+// LatestFor returns the most recently issued forecast for the hour. It returns ErrNotFound if
+// there is none.
+func (wf *ForecastService) LatestFor(hour time.Time) (ForecastVintage, error) {
+	return wf.LatestForContext(context.Background(), hour)
+}
+
+// LatestForContext is like LatestFor, but honors the context deadline and cancellation.
+func (wf *ForecastService) LatestForContext(ctx context.Context, hour time.Time) (ForecastVintage, error) {
+	vs, err := wf.VintagesContext(ctx, hour)
+	if err != nil {
+		return ForecastVintage{}, err
+	}
+	if len(vs) == 0 {
+		return ForecastVintage{}, ErrNotFound
+	}
+	return vs[0], nil
+}
+
+// vintages returns the vintages of the hours within the range (both already truncated to the
+// hour, in UTC), keyed by hour and sorted by descending issuance time.
+func (wf *ForecastService) vintages(ctx context.Context, start, finish time.Time) (map[time.Time][]ForecastVintage, error) {
+	ret := make(map[time.Time][]ForecastVintage)
+	err := wf.store.StreamContext(ctx, forecastIssueSeries, start.Add(-maxForecastLead), finish, FullResolution, Descending, func(tr TSRecord) error {
+		var issue forecastIssue
+		if err := decodeValue(tr.Value, &issue); err != nil {
+			return err
+		}
+		for _, s := range issue.States {
+			h := s.Hour.UTC()
+			if h.Before(start) || h.After(finish) {
+				continue
+			}
+			ret[h] = append(ret[h], ForecastVintage{tr.Timestamp, fromStore(s.State, s.Hour.Local())})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// ForecastError summarizes the temperature error (forecasted minus observed, in Celsius) of
+// the forecasts issued with a certain lead time.
+type ForecastError struct {
+	Lead         time.Duration `json:"lead"` // Lead times within [Lead, Lead+step) are grouped.
+	Count        int           `json:"count"`
+	MeanError    float64       `json:"mean_error"` // Bias: positive if forecasts are too warm.
+	MeanAbsError float64       `json:"mean_abs_error"`
+	RMSE         float64       `json:"rmse"`
+}
+
+// ErrorReport compares the vintages of the hours within the range against the weather observed
+// (as stored by ws), grouping the errors by lead time in steps of the specified size (e.g.
+// 3 hours). Hours without observations are ignored. Groups are sorted by lead time.
+func (wf *ForecastService) ErrorReport(ws *WeatherService, start, finish time.Time, step time.Duration) ([]ForecastError, error) {
+	return wf.ErrorReportContext(context.Background(), ws, start, finish, step)
+}
+
+// ErrorReportContext is like ErrorReport, but honors the context deadline and cancellation.
+func (wf *ForecastService) ErrorReportContext(ctx context.Context, ws *WeatherService, start, finish time.Time, step time.Duration) ([]ForecastError, error) {
+	if step <= 0 {
+		step = time.Hour
+	}
+	observed, err := ws.FetchContext(ctx, start, finish)
+	if err != nil {
+		return nil, err
+	}
+	temps := make(map[time.Time]float64, len(observed))
+	for _, s := range observed {
+		temps[s.Timestamp.UTC()] = s.Temp
+	}
+	byHour, err := wf.vintages(ctx, hourUTC(start), finish.In(time.UTC))
+	if err != nil {
+		return nil, err
+	}
+	groups := make(map[time.Duration]*ForecastError)
+	for h, vs := range byHour {
+		temp, ok := temps[h]
+		if !ok {
+			continue
+		}
+		for _, v := range vs {
+			lead := v.Lead()
+			if lead < 0 { // Issued after the hour began.
+				continue
+			}
+			lead = lead / step * step
+			g, ok := groups[lead]
+			if !ok {
+				g = &ForecastError{Lead: lead}
+				groups[lead] = g
+			}
+			e := v.State.Temp - temp
+			g.Count++
+			g.MeanError += e
+			g.MeanAbsError += math.Abs(e)
+			g.RMSE += e * e
+		}
+	}
+	ret := make([]ForecastError, 0, len(groups))
+	for _, g := range groups {
+		n := float64(g.Count)
+		g.MeanError /= n
+		g.MeanAbsError /= n
+		g.RMSE = math.Sqrt(g.RMSE / n)
+		ret = append(ret, *g)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Lead < ret[j].Lead })
+	return ret, nil
+}
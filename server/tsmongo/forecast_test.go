@@ -0,0 +1,77 @@
+package tsmongo
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/danielfireman/temp-to-go/server/weather"
+)
+
+func TestForecastVintages(t *testing.T) {
+	s := NewMemStore()
+	fs := NewForecastService(s)
+	ws := NewWeatherService(s)
+	base := time.Date(2018, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	if _, err := fs.LatestFor(base); err != ErrNotFound {
+		t.Errorf("LatestFor on empty store want:%q got:%q", ErrNotFound, err)
+	}
+	// Two issues 3 hours apart, both forecasting the base hour.
+	issued1, issued2 := base.Add(-6*time.Hour), base.Add(-3*time.Hour)
+	if err := fs.UpdateIssued(issued1, weather.State{Timestamp: base, Temp: 30}, weather.State{Timestamp: base.Add(3 * time.Hour), Temp: 28}); err != nil {
+		t.Fatalf("UpdateIssued: %q", err)
+	}
+	if err := fs.UpdateIssued(issued2, weather.State{Timestamp: base, Temp: 27}); err != nil {
+		t.Fatalf("UpdateIssued: %q", err)
+	}
+
+	vs, err := fs.Vintages(base)
+	if err != nil {
+		t.Fatalf("Vintages: %q", err)
+	}
+	if len(vs) != 2 || !vs[0].Issued.Equal(issued2) || vs[0].State.Temp != 27 || !vs[1].Issued.Equal(issued1) || vs[1].State.Temp != 30 {
+		t.Fatalf("Vintages want:[27@%s 30@%s] got:%+v", issued2, issued1, vs)
+	}
+	if vs[1].Lead() != 6*time.Hour {
+		t.Errorf("Lead want:%s got:%s", 6*time.Hour, vs[1].Lead())
+	}
+	latest, err := fs.LatestFor(base.Add(10 * time.Minute))
+	if err != nil {
+		t.Fatalf("LatestFor: %q", err)
+	}
+	if !latest.Issued.Equal(issued2) || latest.State.Temp != 27 || !latest.State.Timestamp.Equal(base) {
+		t.Errorf("LatestFor want:27@%s got:%+v", issued2, latest)
+	}
+	// The latest forecast is still available through Fetch.
+	fetched, err := fs.Fetch(base, base)
+	if err != nil {
+		t.Fatalf("Fetch: %q", err)
+	}
+	if len(fetched) != 1 || fetched[0].Temp != 27 {
+		t.Errorf("Fetch want:27 got:%+v", fetched)
+	}
+
+	// Observed 28 at the base hour: the 6h-ahead forecast was 2 degrees too warm, the 3h-ahead
+	// one was 1 degree too cold. The hour without observations is ignored.
+	if err := ws.Update(weather.State{Timestamp: base, Temp: 28}); err != nil {
+		t.Fatalf("Update: %q", err)
+	}
+	report, err := fs.ErrorReport(ws, base.Add(-time.Hour), base.Add(6*time.Hour), 3*time.Hour)
+	if err != nil {
+		t.Fatalf("ErrorReport: %q", err)
+	}
+	want := []ForecastError{
+		{Lead: 3 * time.Hour, Count: 1, MeanError: -1, MeanAbsError: 1, RMSE: 1},
+		{Lead: 6 * time.Hour, Count: 1, MeanError: 2, MeanAbsError: 2, RMSE: 2},
+	}
+	if len(report) != len(want) {
+		t.Fatalf("ErrorReport want:%+v got:%+v", want, report)
+	}
+	for i := range want {
+		g := report[i]
+		if g.Lead != want[i].Lead || g.Count != want[i].Count || math.Abs(g.MeanError-want[i].MeanError) > 1e-9 || math.Abs(g.MeanAbsError-want[i].MeanAbsError) > 1e-9 || math.Abs(g.RMSE-want[i].RMSE) > 1e-9 {
+			t.Errorf("[%d] want:%+v got:%+v", i, want[i], g)
+		}
+	}
+}
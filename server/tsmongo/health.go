@@ -0,0 +1,147 @@
+package tsmongo
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/globalsign/mgo"
+)
+
+// UnreachableError is returned when the database could not be reached (e.g. the network is down
+// or a replica set has no primary), as opposed to errors returned by the database for the
+// operation itself.
+type UnreachableError struct {
+	Err error
+}
+
+func (e *UnreachableError) Error() string {
+	return fmt.Sprintf("Error reaching tsmongo database: %q", e.Err)
+}
+
+// IsUnreachable returns whether the error was returned because the database could not be
+// reached.
+func IsUnreachable(err error) bool {
+	_, ok := err.(*UnreachableError)
+	return ok
+}
+
+// unreachable returns whether the error of an operation was caused by the connection to the
+// database, instead of by the operation. Context errors are neither.
+func unreachable(err error) bool {
+	if err == nil || err == context.Canceled || err == context.DeadlineExceeded || IsUnreachable(err) {
+		return false
+	}
+	return isTransient(err)
+}
+
+// Health is the outcome of the last liveness probe of a session.
+type Health struct {
+	Checked time.Time `json:"checked"`
+	Err     error     `json:"-"`
+}
+
+// Reachable returns whether the database answered the last probe.
+func (h Health) Reachable() bool {
+	return !h.Checked.IsZero() && h.Err == nil
+}
+
+// health holds the outcome of the last liveness probe of a session.
+type health struct {
+	mu   sync.Mutex
+	last Health
+}
+
+// Ping checks whether the database is reachable. Like other operations, it refreshes the
+// session (reconnecting to the current primary) if the database could not be reached.
+func (s *Session) Ping() error {
+	return s.PingContext(context.Background())
+}
+
+// PingContext is like Ping, but honors the context deadline and cancellation.
+func (s *Session) PingContext(ctx context.Context) error {
+	err := s.run(ctx, func(col *mgo.Collection) error {
+		return col.Database.Session.Ping()
+	})
+	s.health.mu.Lock()
+	s.health.last = Health{time.Now(), err}
+	s.health.mu.Unlock()
+	return err
+}
+
+// Health returns the outcome of the last liveness probe (see Ping and KeepAlive).
+func (s *Session) Health() Health {
+	s.health.mu.Lock()
+	defer s.health.mu.Unlock()
+	return s.health.last
+}
+
+// KeepAlive pings the database every interval until the context is done, so broken
+// connections (e.g. after a replica set failover) are refreshed before requests need them.
+// Each ping times out after the interval.
+func (s *Session) KeepAlive(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		pingCtx, cancel := context.WithTimeout(ctx, interval)
+		s.PingContext(pingCtx)
+		cancel()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Refresh closes the sockets held by the session, so the next operation reconnects to the
+// cluster (e.g. to the new primary after a failover).
+func (s *Session) Refresh() {
+	s.session.Refresh()
+}
+
+// SetPoolLimit sets the maximum number of sockets the session (and its copies) keep open per
+// server. Operations wait for a socket once the limit is reached.
+func (s *Session) SetPoolLimit(limit int) {
+	s.session.SetPoolLimit(limit)
+}
+
+// ErrPoolExhausted is returned when no session copy became available before the context was
+// done.
+var ErrPoolExhausted = fmt.Errorf("Session pool exhausted")
+
+// SessionPool hands out copies of a session (see Session.Copy), limiting how many are in use at
+// once. Copies let concurrent requests use their own sockets, instead of sharing the socket of
+// the session.
+type SessionPool struct {
+	session *Session
+	slots   chan struct{}
+}
+
+// NewSessionPool creates a pool of at most limit copies of the session in use at once.
+func NewSessionPool(s *Session, limit int) *SessionPool {
+	return &SessionPool{s, make(chan struct{}, limit)}
+}
+
+// Acquire returns a copy of the session, waiting for one to be released if the limit was
+// reached. It returns ErrPoolExhausted if the context is done first. Copies must be released.
+func (p *SessionPool) Acquire(ctx context.Context) (*Session, error) {
+	select {
+	case p.slots <- struct{}{}:
+		return p.session.Copy(), nil
+	case <-ctx.Done():
+		return nil, ErrPoolExhausted
+	}
+}
+
+// Release closes a copy returned by Acquire.
+func (p *SessionPool) Release(s *Session) {
+	s.Close()
+	<-p.slots
+}
+
+// InUse returns how many copies are in use.
+func (p *SessionPool) InUse() int {
+	return len(p.slots)
+}
@@ -0,0 +1,142 @@
+package tsmongo
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/dbtest"
+)
+
+func TestUnreachable(t *testing.T) {
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{context.DeadlineExceeded, false},
+		{mgo.ErrNotFound, false},
+		{&mgo.QueryError{Code: 2, Message: "bad query"}, false},
+		{io.EOF, true},
+		{errors.New("no reachable servers"), true},
+		{&mgo.QueryError{Code: 10107, Message: "not master"}, true},
+		{&UnreachableError{io.EOF}, false}, // Already reported.
+	}
+	for _, c := range cases {
+		if got := unreachable(c.err); got != c.want {
+			t.Errorf("unreachable(%v) want:%v got:%v", c.err, c.want, got)
+		}
+	}
+	if !isTransient(&UnreachableError{io.EOF}) {
+		t.Errorf("isTransient(UnreachableError) want:true got:false")
+	}
+}
+
+// brokenIter yields the records, failing with err (e.g. a dropped connection) after n of them.
+type brokenIter struct {
+	recs []TSRecord
+	n    int
+	err  error
+}
+
+func (it *brokenIter) Next(result interface{}) bool {
+	if len(it.recs) == 0 || it.n == 0 {
+		return false
+	}
+	*result.(*TSRecord) = it.recs[0]
+	it.recs = it.recs[1:]
+	it.n--
+	return true
+}
+
+func (it *brokenIter) Close() error {
+	if len(it.recs) > 0 {
+		return it.err
+	}
+	return nil
+}
+
+func TestRetryOnceReadsFromScratch(t *testing.T) {
+	base := time.Date(2018, 7, 1, 0, 0, 0, 0, time.UTC)
+	recs := []TSRecord{{base.Add(2 * time.Hour), 3}, {base.Add(time.Hour), 2}, {base, 1}}
+	iters := []*brokenIter{{recs, 2, io.EOF}, {recs, len(recs), nil}}
+	refreshed := 0
+	var ret []TSRecord
+	err := retryOnce(context.Background(), func() error {
+		var err error
+		ret, err = readRecords(iters[0])
+		iters = iters[1:]
+		return err
+	}, func() { refreshed++ })
+	if err != nil || refreshed != 1 {
+		t.Fatalf("retryOnce want success after 1 refresh got:%q (%d refreshes)", err, refreshed)
+	}
+	if len(ret) != len(recs) {
+		t.Errorf("readRecords after a failure mid-iteration want:%v got:%v", recs, ret)
+	}
+
+	// Failing twice reports the database as unreachable.
+	err = retryOnce(context.Background(), func() error {
+		_, err := readRecords(&brokenIter{recs, 1, io.EOF})
+		return err
+	}, func() {})
+	if !IsUnreachable(err) {
+		t.Errorf("retryOnce want unreachable error got:%v", err)
+	}
+}
+
+func TestSessionHealth(t *testing.T) {
+	if _, err := exec.LookPath("mongod"); err != nil {
+		t.Skip("mongod not available")
+	}
+	tempDir, _ := ioutil.TempDir("", "health_testing")
+	defer os.RemoveAll(tempDir)
+	var mongoDB dbtest.DBServer
+	mongoDB.SetPath(tempDir)
+	defer mongoDB.Wipe()
+
+	s := mongoDB.Session()
+	defer s.Close()
+	session := NewSession(s, "test")
+	defer session.Close()
+	if err := session.Ping(); err != nil || !session.Health().Reachable() {
+		t.Fatalf("Ping want reachable got:%q (%+v)", err, session.Health())
+	}
+
+	pool := NewSessionPool(session, 1)
+	c, err := pool.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("Acquire: %q", err)
+	}
+	if err := c.Ping(); err != nil {
+		t.Errorf("Ping of copy: %q", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := pool.Acquire(ctx); err != ErrPoolExhausted {
+		t.Errorf("Acquire over the limit want:%q got:%q", ErrPoolExhausted, err)
+	}
+	pool.Release(c)
+	if n := pool.InUse(); n != 0 {
+		t.Errorf("InUse want:0 got:%d", n)
+	}
+
+	// Queries fail as unreachable once the server is gone.
+	mongoDB.Stop()
+	session.session.SetSyncTimeout(500 * time.Millisecond)
+	if err := session.Ping(); !IsUnreachable(err) {
+		t.Errorf("Ping want unreachable error got:%v", err)
+	}
+	if _, err := session.Last(bedroomSeries); !IsUnreachable(err) {
+		t.Errorf("Last want unreachable error got:%v", err)
+	}
+	if session.Health().Reachable() {
+		t.Errorf("Health want unreachable got:%+v", session.Health())
+	}
+}
@@ -0,0 +1,128 @@
+package tsmongo
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// Column selects the numeric values of a series joined by Join, and how its gaps are filled.
+type Column struct {
+	Name   string
+	Series Series
+	// Path, if not empty, is the dotted path of the value within stored documents (e.g. "temp"
+	// for the weather).
+	Path   string
+	Fill   Fill
+	MaxGap time.Duration // See ResampleOptions.
+}
+
+// JoinQuery specifies the columns joined by Join, and the grid they are aligned on.
+type JoinQuery struct {
+	Start   time.Time
+	Finish  time.Time
+	Step    time.Duration // Defaults to an hour. See ResampleOptions.
+	Columns []Column
+	// Complete, if set, only returns rows where every column has a value.
+	Complete bool
+}
+
+// Cell is the value of a column at a row. Cells of columns without values (neither observed
+// nor filled) at the row are not valid.
+type Cell struct {
+	Value  float64 `json:"value"`
+	Filled bool    `json:"filled,omitempty"`
+	Valid  bool    `json:"valid"`
+}
+
+// Row holds the values of the joined columns at a grid step, in the order of the query columns.
+type Row struct {
+	Timestamp time.Time `json:"timestamp"`
+	Cells     []Cell    `json:"cells"`
+}
+
+// Complete returns whether every column has a value at the row.
+func (r Row) Complete() bool {
+	for _, c := range r.Cells {
+		if !c.Valid {
+			return false
+		}
+	}
+	return true
+}
+
+// Values returns the values of the columns at the row. Invalid cells are zero.
+func (r Row) Values() []float64 {
+	ret := make([]float64, len(r.Cells))
+	for i, c := range r.Cells {
+		ret[i] = c.Value
+	}
+	return ret
+}
+
+// Join resamples the columns on a common grid of steps within the range, returning one row per
+// step where any column (every column, for complete queries) has a value, sorted by ascending
+// timestamp.
+func Join(ctx context.Context, s Store, q JoinQuery) ([]Row, error) {
+	step := q.Step
+	if step <= 0 {
+		step = time.Hour
+	}
+	first := q.Start.Truncate(step)
+	byStep := make(map[int]*Row)
+	var steps []int
+	for i, col := range q.Columns {
+		path := col.Path
+		// Records without a numeric value (at the path) are skipped, as Aggregate does.
+		points, err := resample(ctx, s, col.Series, q.Start, q.Finish, ResampleOptions{step, col.Fill, col.MaxGap}, func(tr TSRecord) ([]float64, error) {
+			if v, ok := numericValue(tr.Value, path); ok {
+				return []float64{v}, nil
+			}
+			return nil, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range points {
+			k := int(p.timestamp.Sub(first) / step)
+			r, ok := byStep[k]
+			if !ok {
+				r = &Row{Timestamp: p.timestamp, Cells: make([]Cell, len(q.Columns))}
+				byStep[k] = r
+				steps = append(steps, k)
+			}
+			r.Cells[i] = Cell{p.values[0], p.filled, true}
+		}
+	}
+	sort.Ints(steps)
+	ret := make([]Row, 0, len(steps))
+	for _, k := range steps {
+		r := byStep[k]
+		if q.Complete && !r.Complete() {
+			continue
+		}
+		ret = append(ret, *r)
+	}
+	return ret, nil
+}
+
+// Column returns the join column of the bedroom temperature.
+func (b *BedroomService) Column(fill Fill, maxGap time.Duration) Column {
+	return Column{Name: b.series.String(), Series: b.series, Fill: fill, MaxGap: maxGap}
+}
+
+// Column returns the join column of the fan status (as a number, e.g. 1 for FanLowSpeed).
+func (f FanService) Column(fill Fill, maxGap time.Duration) Column {
+	return Column{Name: f.series.String(), Series: f.series, Fill: fill, MaxGap: maxGap}
+}
+
+// Column returns the join column of the weather temperature (Celsius).
+func (w *WeatherService) Column(fill Fill, maxGap time.Duration) Column {
+	return Column{Name: weatherSeries.String(), Series: weatherSeries, Path: "temp", Fill: fill, MaxGap: maxGap}
+}
+
+// Column returns the join column of the readings of the metric.
+func (ss *SensorService) Column(metric string, fill Fill, maxGap time.Duration) Column {
+	series := ss.series(Metric{Name: metric})
+	return Column{Name: metric + "@" + ss.room, Series: series, Fill: fill, MaxGap: maxGap}
+}
@@ -0,0 +1,78 @@
+package tsmongo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/danielfireman/temp-to-go/server/weather"
+)
+
+func TestJoin(t *testing.T) {
+	s := NewMemStore()
+	base := time.Date(2018, 7, 1, 10, 0, 0, 0, time.UTC)
+	bs := NewBedroomService(s, DefaultRoom)
+	bs.UpdateTemperature(base.Add(5*time.Minute), 25) // Not aligned to the hour.
+	bs.UpdateTemperature(base.Add(time.Hour), 26)
+	bs.UpdateTemperature(base.Add(3*time.Hour), 27)
+	ws := NewWeatherService(s)
+	ws.Update(weather.State{Timestamp: base, Temp: 30})
+	ws.Update(weather.State{Timestamp: base.Add(2 * time.Hour), Temp: 32})
+	fs := NewFanService(s, DefaultRoom)
+	fs.UpdateStatus(base.Add(time.Hour+30*time.Minute), FanLowSpeed)
+
+	q := JoinQuery{
+		Start:  base,
+		Finish: base.Add(3 * time.Hour),
+		Columns: []Column{
+			bs.Column(FillNone, 0),
+			ws.Column(FillLinear, 0),
+			fs.Column(FillPrevious, 0),
+		},
+	}
+	rows, err := Join(context.Background(), s, q)
+	if err != nil {
+		t.Fatalf("Join: %q", err)
+	}
+	obs := func(v float64) Cell { return Cell{v, false, true} }
+	fill := func(v float64) Cell { return Cell{v, true, true} }
+	want := []Row{
+		{base, []Cell{obs(25), obs(30), {}}},
+		{base.Add(time.Hour), []Cell{obs(26), fill(31), obs(1)}},
+		{base.Add(2 * time.Hour), []Cell{{}, obs(32), fill(1)}},
+		{base.Add(3 * time.Hour), []Cell{obs(27), {}, fill(1)}},
+	}
+	checkRows(t, want, rows)
+
+	q.Complete = true
+	rows, err = Join(context.Background(), s, q)
+	if err != nil {
+		t.Fatalf("Join(Complete): %q", err)
+	}
+	checkRows(t, want[1:2], rows)
+
+	// Documents have no numeric value without the path of the value.
+	q.Columns = []Column{{Name: "weather", Series: weatherSeries}}
+	rows, err = Join(context.Background(), s, q)
+	if err != nil || len(rows) != 0 {
+		t.Errorf("Join of documents without path want:[] got:%+v (%v)", rows, err)
+	}
+}
+
+func checkRows(t *testing.T, want, got []Row) {
+	t.Helper()
+	if len(want) != len(got) {
+		t.Fatalf("want:%+v got:%+v", want, got)
+	}
+	for i := range want {
+		if !want[i].Timestamp.Equal(got[i].Timestamp) || len(want[i].Cells) != len(got[i].Cells) {
+			t.Errorf("[%d] want:%+v got:%+v", i, want[i], got[i])
+			continue
+		}
+		for j := range want[i].Cells {
+			if want[i].Cells[j] != got[i].Cells[j] {
+				t.Errorf("[%d][%d] want:%+v got:%+v", i, j, want[i].Cells[j], got[i].Cells[j])
+			}
+		}
+	}
+}
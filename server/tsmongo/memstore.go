@@ -0,0 +1,314 @@
+package tsmongo
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+// MemStore is an in-memory Store. It is safe for concurrent use and mimics the semantics of
+// the mongo Session: records are bucketed by series and hour (UTC), values go through a BSON
+// round trip (so they come back exactly as they would from mongo) and queries return records
+// sorted by descending timestamp.
+type MemStore struct {
+	mu     sync.RWMutex
+	fields map[seriesKey]map[time.Time]*bucket
+	tags   map[seriesKey]map[string]string
+}
+
+// NewMemStore creates a new empty MemStore.
+func NewMemStore() *MemStore {
+	return &MemStore{
+		fields: make(map[seriesKey]map[time.Time]*bucket),
+		tags:   make(map[seriesKey]map[string]string),
+	}
+}
+
+// Upsert inserts the given data into the store overriding the data if necessary.
+func (m *MemStore) Upsert(series Series, val ...TSRecord) error {
+	return m.UpsertContext(context.Background(), series, val...)
+}
+
+// UpsertContext is like Upsert, but returns right away if the context is done.
+func (m *MemStore) UpsertContext(ctx context.Context, series Series, val ...TSRecord) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	values, err := encodeValues(val)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := range val {
+		m.set(series, bucket{Timestamp: hourUTC(val[i].Timestamp), Value: values[i]})
+	}
+	return nil
+}
+
+// UpsertSamples stores the given data as samples within their hour buckets. The hourly value
+// of the buckets they are the newest samples of is also updated.
+func (m *MemStore) UpsertSamples(series Series, val ...TSRecord) error {
+	return m.UpsertSamplesContext(context.Background(), series, val...)
+}
+
+// UpsertSamplesContext is like UpsertSamples, but returns right away if the context is done.
+func (m *MemStore) UpsertSamplesContext(ctx context.Context, series Series, val ...TSRecord) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	values, err := encodeValues(val)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := range val {
+		m.setSample(series, val[i].Timestamp, values[i])
+	}
+	return nil
+}
+
+// set stores an already encoded bucket, replacing the existing one. Callers must hold the write lock.
+func (m *MemStore) set(series Series, b bucket) {
+	k := series.key()
+	hours, ok := m.fields[k]
+	if !ok {
+		hours = make(map[time.Time]*bucket)
+		m.fields[k] = hours
+	}
+	if len(series.Tags) > 0 {
+		m.tags[k] = series.Tags
+	}
+	b.Timestamp = b.Timestamp.UTC() // Normalizing location, which is part of time.Time equality.
+	if tr, ok := b.newest(); ok && b.Last.IsZero() {
+		b.Last = tr.Timestamp
+	}
+	hours[b.Timestamp] = &b
+}
+
+// setSample stores an already encoded sample. Callers must hold the write lock.
+func (m *MemStore) setSample(series Series, t time.Time, value interface{}) {
+	hour := hourUTC(t)
+	b, ok := m.fields[series.key()][hour]
+	if !ok {
+		m.set(series, bucket{Timestamp: hour})
+		b = m.fields[series.key()][hour]
+	} else if len(series.Tags) > 0 {
+		m.tags[series.key()] = series.Tags
+	}
+	if b.Samples == nil {
+		b.Samples = make(map[string]map[string]interface{})
+	}
+	min, sec := sampleKey(t)
+	if b.Samples[min] == nil {
+		b.Samples[min] = make(map[string]interface{})
+	}
+	b.Samples[min][sec] = value
+	// Only the newest sample of the bucket is its hourly value.
+	if t = sampleTime(t); !t.Before(b.Last) {
+		b.Value = value
+		b.Last = t
+	}
+}
+
+// RemoveRange removes the hour buckets of the series within the specified range.
+func (m *MemStore) RemoveRange(series Series, start time.Time, finish time.Time) (int, error) {
+	return m.RemoveRangeContext(context.Background(), series, start, finish)
+}
+
+// RemoveRangeContext is like RemoveRange, but returns right away if the context is done.
+func (m *MemStore) RemoveRangeContext(ctx context.Context, series Series, start time.Time, finish time.Time) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.removeRange(series, start, finish), nil
+}
+
+// removeRange removes buckets, returning how many were removed. Callers must hold the write lock.
+func (m *MemStore) removeRange(series Series, start time.Time, finish time.Time) int {
+	n := 0
+	for h := range m.fields[series.key()] {
+		if h.Before(start) || h.After(finish) {
+			continue
+		}
+		delete(m.fields[series.key()], h)
+		n++
+	}
+	return n
+}
+
+// RemoveSamples removes the samples of the series taken at the specified times (with one second
+// resolution), returning how many were removed. The hourly value of their buckets becomes the
+// newest remaining sample, and buckets left empty are removed.
+func (m *MemStore) RemoveSamples(series Series, t ...time.Time) (int, error) {
+	return m.RemoveSamplesContext(context.Background(), series, t...)
+}
+
+// RemoveSamplesContext is like RemoveSamples, but returns right away if the context is done.
+func (m *MemStore) RemoveSamplesContext(ctx context.Context, series Series, t ...time.Time) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n := 0
+	for _, t := range t {
+		if m.removeSample(series, t) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// removeSample removes a sample, returning whether it was found. Buckets without samples hold a
+// single record, at the beginning of the hour. Callers must hold the write lock.
+func (m *MemStore) removeSample(series Series, t time.Time) bool {
+	t = sampleTime(t)
+	hour := hourUTC(t)
+	b, ok := m.fields[series.key()][hour]
+	if !ok {
+		return false
+	}
+	if len(b.Samples) == 0 {
+		if !t.Equal(hour) {
+			return false
+		}
+		delete(m.fields[series.key()], hour)
+		return true
+	}
+	min, sec := sampleKey(t)
+	if _, ok := b.Samples[min][sec]; !ok {
+		return false
+	}
+	delete(b.Samples[min], sec)
+	if len(b.Samples[min]) == 0 {
+		delete(b.Samples, min)
+	}
+	prev, ok := b.newest()
+	if !ok {
+		delete(m.fields[series.key()], hour)
+		return true
+	}
+	b.Value, b.Last = prev.Value, prev.Timestamp
+	return true
+}
+
+// Query fetches all records from the store within the specified range.
+func (m *MemStore) Query(series Series, start time.Time, finish time.Time) ([]TSRecord, error) {
+	return m.QueryContext(context.Background(), series, start, finish)
+}
+
+// QueryContext is like Query, but returns right away if the context is done.
+func (m *MemStore) QueryContext(ctx context.Context, series Series, start time.Time, finish time.Time) ([]TSRecord, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var ret []TSRecord
+	for h, b := range m.fields[series.key()] {
+		if h.Before(start) || h.After(finish) {
+			continue
+		}
+		ret = append(ret, TSRecord{h.Local(), b.Value})
+	}
+	sortDesc(ret)
+	return ret, nil
+}
+
+// QuerySamples fetches all samples from the store within the specified range.
+func (m *MemStore) QuerySamples(series Series, start time.Time, finish time.Time) ([]TSRecord, error) {
+	return m.QuerySamplesContext(context.Background(), series, start, finish)
+}
+
+// QuerySamplesContext is like QuerySamples, but returns right away if the context is done.
+func (m *MemStore) QuerySamplesContext(ctx context.Context, series Series, start time.Time, finish time.Time) ([]TSRecord, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var ret []TSRecord
+	startHour := hourUTC(start)
+	for h, b := range m.fields[series.key()] {
+		if h.Before(startHour) || h.After(finish) {
+			continue
+		}
+		for _, r := range b.records(start, finish) {
+			ret = append(ret, TSRecord{r.Timestamp.Local(), r.Value})
+		}
+	}
+	sortDesc(ret)
+	return ret, nil
+}
+
+// Last returns the last element in the timeseries, if any.
+func (m *MemStore) Last(series Series) (TSRecord, error) {
+	return m.LastContext(context.Background(), series)
+}
+
+// LastContext is like Last, but returns right away if the context is done.
+func (m *MemStore) LastContext(ctx context.Context, series Series) (TSRecord, error) {
+	if err := ctx.Err(); err != nil {
+		return TSRecord{}, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var last TSRecord
+	found := false
+	for h, b := range m.fields[series.key()] {
+		if !found || h.After(last.Timestamp) {
+			last = TSRecord{h, b.Value}
+			found = true
+		}
+	}
+	if !found {
+		return TSRecord{}, ErrNotFound
+	}
+	last.Timestamp = last.Timestamp.Local()
+	return last, nil
+}
+
+// Close is a no-op, there are no resources associated with a MemStore.
+func (m *MemStore) Close() {
+}
+
+// encodeValues round trips the values of the records. It is called before locking, so a value
+// that can not be stored does not cause a partial update.
+func encodeValues(val []TSRecord) ([]interface{}, error) {
+	values := make([]interface{}, len(val))
+	for i := range val {
+		v, err := roundTrip(val[i].Value)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+// roundTrip encodes and decodes the value using BSON, producing the same representation
+// mongo would return (e.g. structs become bson.M and int kinds become int).
+func roundTrip(v interface{}) (interface{}, error) {
+	b, err := bson.Marshal(bson.M{valueField: v})
+	if err != nil {
+		return nil, err
+	}
+	var d struct {
+		Value interface{} `bson:"value"`
+	}
+	if err := bson.Unmarshal(b, &d); err != nil {
+		return nil, err
+	}
+	return d.Value, nil
+}
+
+func sortDesc(trs []TSRecord) {
+	sort.Slice(trs, func(i, j int) bool { return trs[i].Timestamp.After(trs[j].Timestamp) })
+}
@@ -0,0 +1,116 @@
+package tsmongo
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/danielfireman/temp-to-go/server/weather"
+)
+
+var (
+	bedroomSeries = Series{Field: bedroomField, Room: DefaultRoom}
+	fanSeries     = Series{Field: fanField, Room: DefaultRoom}
+)
+
+func TestMemStore_UpsertAndQuery(t *testing.T) {
+	s := NewMemStore()
+	base := time.Date(2018, 7, 1, 10, 0, 0, 0, time.UTC)
+
+	// Both updates fall into the same hour, so the second must override the first.
+	s.Upsert(bedroomSeries, TSRecord{base.Add(10 * time.Minute), 25.0})
+	s.Upsert(bedroomSeries, TSRecord{base.Add(20 * time.Minute), 26.0})
+	s.Upsert(bedroomSeries, TSRecord{base.Add(time.Hour), 27.0}, TSRecord{base.Add(2 * time.Hour), 28.0})
+	s.Upsert(fanSeries, TSRecord{base, FanHighSpeed})
+
+	trs, err := s.Query(bedroomSeries, base, base.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Query: %q", err)
+	}
+	want := []TSRecord{{base.Add(time.Hour), 27.0}, {base, 26.0}}
+	if len(trs) != len(want) {
+		t.Fatalf("len(trs) want:%d got:%d", len(want), len(trs))
+	}
+	for i := range want {
+		if !trs[i].Timestamp.Equal(want[i].Timestamp) || trs[i].Value != want[i].Value {
+			t.Errorf("trs[%d] want:%+v got:%+v", i, want[i], trs[i])
+		}
+	}
+
+	last, err := s.Last(bedroomSeries)
+	if err != nil {
+		t.Fatalf("Last: %q", err)
+	}
+	if !last.Timestamp.Equal(base.Add(2*time.Hour)) || last.Value != 28.0 {
+		t.Errorf("Last want:%v got:%+v", base.Add(2*time.Hour), last)
+	}
+	if _, err := s.Last(weatherSeries); err != ErrNotFound {
+		t.Errorf("Last on empty field want:%q got:%q", ErrNotFound, err)
+	}
+}
+
+func TestMemStore_Services(t *testing.T) {
+	s := NewMemStore()
+	now := time.Now()
+
+	fs := NewFanService(s, DefaultRoom)
+	if err := fs.UpdateStatus(now, FanLowSpeed); err != nil {
+		t.Fatalf("UpdateStatus: %q", err)
+	}
+	st, err := fs.LastState()
+	if err != nil {
+		t.Fatalf("LastState: %q", err)
+	}
+	if st.Status != FanLowSpeed {
+		t.Errorf("LastState want:%v got:%v", FanLowSpeed, st.Status)
+	}
+
+	ws := NewWeatherService(s)
+	if err := ws.Update(weather.State{Timestamp: now, Temp: 27.5, Wind: weather.Wind{Speed: 3}}); err != nil {
+		t.Fatalf("Update: %q", err)
+	}
+	states, err := ws.Fetch(now.Add(-time.Hour), now)
+	if err != nil {
+		t.Fatalf("Fetch: %q", err)
+	}
+	if len(states) != 1 || states[0].Temp != 27.5 || states[0].Wind.Speed != 3 {
+		t.Errorf("Fetch want temp:27.5 wind:3 got:%+v", states)
+	}
+}
+
+func TestMemStore_Concurrency(t *testing.T) {
+	s := NewMemStore()
+	base := time.Date(2018, 7, 1, 0, 0, 0, 0, time.UTC)
+	var wg sync.WaitGroup
+	for i := 0; i < 24; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			s.Upsert(bedroomSeries, TSRecord{base.Add(time.Duration(i) * time.Hour), float64(i)})
+			s.Query(bedroomSeries, base, base.Add(24*time.Hour))
+		}(i)
+	}
+	wg.Wait()
+	trs, _ := s.Query(bedroomSeries, base, base.Add(24*time.Hour))
+	if len(trs) != 24 {
+		t.Fatalf("len(trs) want:24 got:%d", len(trs))
+	}
+	for i := 1; i < len(trs); i++ {
+		if !trs[i-1].Timestamp.After(trs[i].Timestamp) {
+			t.Errorf("records are not sorted in descending order: %v %v", trs[i-1].Timestamp, trs[i].Timestamp)
+		}
+	}
+}
+
+func TestMemStore_ContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	bs := NewBedroomService(NewMemStore(), DefaultRoom)
+	if err := bs.UpdateTemperatureContext(ctx, time.Now(), 25); err != context.Canceled {
+		t.Errorf("UpdateTemperatureContext want:%q got:%q", context.Canceled, err)
+	}
+	if _, err := bs.FetchStateContext(ctx, time.Now().Add(-time.Hour), time.Now(), Hourly); err != context.Canceled {
+		t.Errorf("FetchStateContext want:%q got:%q", context.Canceled, err)
+	}
+}
@@ -1,17 +1,34 @@
 package tsmongo
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
-const predictionField = "pred"
+const (
+	predictionField    = "pred"
+	predictionRunField = "pred.run"
+)
+
+// Prediction series are not bound to a room.
+var (
+	predictionSeries    = Series{Field: predictionField}
+	predictionRunSeries = Series{Field: predictionRunField}
+)
 
 // PredictionService allows users to store or fetch predictions.
 type PredictionService struct {
-	session *Session
+	store Store
 }
 
 // Update stores the passed-in predictions. The call overrides any
-// previously stored predictions.
+// previously stored predictions. Use UpdateRun to also store the run which produced them.
 func (p *PredictionService) Update(ps ...Prediction) error {
+	return p.UpdateContext(context.Background(), ps...)
+}
+
+// UpdateContext is like Update, but honors the context deadline and cancellation.
+func (p *PredictionService) UpdateContext(ctx context.Context, ps ...Prediction) error {
 	if len(ps) == 0 {
 		return nil
 	}
@@ -19,14 +36,144 @@ func (p *PredictionService) Update(ps ...Prediction) error {
 	for i := range ps {
 		trs[i] = TSRecord{ps[i].Timestamp, ps[i]}
 	}
-	return p.session.Upsert(predictionField, trs...)
+	return p.store.UpsertContext(ctx, predictionSeries, trs...)
 }
 
 // Prediction represents a prediction of a bedroom temperature at a certain time in
 // any of the following states: fan off, low or high.
 type Prediction struct {
-	Timestamp   time.Time `bson:"-"`
-	TempFanOff  float64   `bson:"fan_off,omitempty"`
-	TempFanLow  float64   `bson:"fan_low,omitempty"`
-	TempFanHigh float64   `bson:"fan_high,omitempty"`
+	Timestamp   time.Time `bson:"-" json:"timestamp"`
+	TempFanOff  float64   `bson:"fan_off,omitempty" json:"fan_off"`
+	TempFanLow  float64   `bson:"fan_low,omitempty" json:"fan_low"`
+	TempFanHigh float64   `bson:"fan_high,omitempty" json:"fan_high"`
+	Run         int       `bson:"run,omitempty" json:"run,omitempty"` // Version of the run which produced it, if known.
+}
+
+// PredictionRun describes a run of the predictor: when it happened and the model it trained.
+type PredictionRun struct {
+	Version        int                `bson:"version" json:"version"`
+	GeneratedAt    time.Time          `bson:"generated_at" json:"generated_at"`
+	TrainStart     time.Time          `bson:"train_start" json:"train_start"`
+	TrainFinish    time.Time          `bson:"train_finish" json:"train_finish"`
+	TrainingPoints int                `bson:"training_points" json:"training_points"`
+	Coefficients   map[string]float64 `bson:"coefficients" json:"coefficients"` // Keyed by variable name.
+	R2             float64            `bson:"r2" json:"r2"`                     // Fit quality.
+}
+
+// UpdateRun stores the run, versioned after the last stored one, and the predictions it
+// produced. The stored run is returned.
+func (p *PredictionService) UpdateRun(run PredictionRun, ps ...Prediction) (PredictionRun, error) {
+	return p.UpdateRunContext(context.Background(), run, ps...)
+}
+
+// UpdateRunContext is like UpdateRun, but honors the context deadline and cancellation.
+func (p *PredictionService) UpdateRunContext(ctx context.Context, run PredictionRun, ps ...Prediction) (PredictionRun, error) {
+	last, err := p.LatestRunContext(ctx)
+	switch err {
+	case nil:
+		run.Version = last.Version + 1
+	case ErrNotFound:
+		run.Version = 1
+	default:
+		return PredictionRun{}, err
+	}
+	if run.GeneratedAt.IsZero() {
+		run.GeneratedAt = time.Now()
+	}
+	// Runs are stored as samples, so many runs within the same hour are kept.
+	if err := p.store.UpsertSamplesContext(ctx, predictionRunSeries, TSRecord{run.GeneratedAt, run}); err != nil {
+		return PredictionRun{}, err
+	}
+	for i := range ps {
+		ps[i].Run = run.Version
+	}
+	return run, p.UpdateContext(ctx, ps...)
+}
+
+// Fetch returns the predictions within the time range, sorted by descending timestamp.
+func (p *PredictionService) Fetch(start time.Time, finish time.Time) ([]Prediction, error) {
+	return p.FetchContext(context.Background(), start, finish)
+}
+
+// FetchContext is like Fetch, but honors the context deadline and cancellation.
+func (p *PredictionService) FetchContext(ctx context.Context, start time.Time, finish time.Time) ([]Prediction, error) {
+	trs, err := p.store.QueryContext(ctx, predictionSeries, start, finish)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]Prediction, len(trs))
+	for i := range trs {
+		if err := decodeValue(trs[i].Value, &ret[i]); err != nil {
+			return nil, err
+		}
+		ret[i].Timestamp = trs[i].Timestamp
+	}
+	return ret, nil
+}
+
+// Latest returns the latest run and the predictions it produced which were not overridden by
+// other runs, sorted by descending timestamp. It returns ErrNotFound if no run was stored.
+func (p *PredictionService) Latest() (PredictionRun, []Prediction, error) {
+	return p.LatestContext(context.Background())
+}
+
+// LatestContext is like Latest, but honors the context deadline and cancellation.
+func (p *PredictionService) LatestContext(ctx context.Context) (PredictionRun, []Prediction, error) {
+	run, err := p.LatestRunContext(ctx)
+	if err != nil {
+		return PredictionRun{}, nil, err
+	}
+	ps, err := p.FetchContext(ctx, hourUTC(run.GeneratedAt), watchHorizon)
+	if err != nil {
+		return PredictionRun{}, nil, err
+	}
+	var ret []Prediction
+	for _, pred := range ps {
+		if pred.Run == run.Version {
+			ret = append(ret, pred)
+		}
+	}
+	return run, ret, nil
+}
+
+// LatestRun returns the latest stored run. It returns ErrNotFound if there is none.
+func (p *PredictionService) LatestRun() (PredictionRun, error) {
+	return p.LatestRunContext(context.Background())
+}
+
+// LatestRunContext is like LatestRun, but honors the context deadline and cancellation.
+func (p *PredictionService) LatestRunContext(ctx context.Context) (PredictionRun, error) {
+	last, err := p.store.LastContext(ctx, predictionRunSeries)
+	if err != nil {
+		return PredictionRun{}, err
+	}
+	runs, err := p.FetchRunsContext(ctx, last.Timestamp, last.Timestamp.Add(time.Hour))
+	if err != nil {
+		return PredictionRun{}, err
+	}
+	if len(runs) == 0 {
+		return PredictionRun{}, ErrNotFound
+	}
+	return runs[0], nil
+}
+
+// FetchRuns returns the runs generated within the time range, sorted by descending generation
+// time.
+func (p *PredictionService) FetchRuns(start time.Time, finish time.Time) ([]PredictionRun, error) {
+	return p.FetchRunsContext(context.Background(), start, finish)
+}
+
+// FetchRunsContext is like FetchRuns, but honors the context deadline and cancellation.
+func (p *PredictionService) FetchRunsContext(ctx context.Context, start time.Time, finish time.Time) ([]PredictionRun, error) {
+	trs, err := p.store.QuerySamplesContext(ctx, predictionRunSeries, start, finish)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]PredictionRun, len(trs))
+	for i := range trs {
+		if err := decodeValue(trs[i].Value, &ret[i]); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
 }
@@ -0,0 +1,58 @@
+package tsmongo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPredictionService(t *testing.T) {
+	ps := NewPredictionService(NewMemStore())
+	base := time.Date(2018, 7, 1, 10, 0, 0, 0, time.UTC)
+
+	if _, _, err := ps.Latest(); err != ErrNotFound {
+		t.Errorf("Latest on empty store want:%q got:%q", ErrNotFound, err)
+	}
+	run1, err := ps.UpdateRun(PredictionRun{GeneratedAt: base, TrainingPoints: 10, R2: 0.5},
+		Prediction{Timestamp: base.Add(time.Hour), TempFanOff: 30},
+		Prediction{Timestamp: base.Add(2 * time.Hour), TempFanOff: 31},
+	)
+	if err != nil {
+		t.Fatalf("UpdateRun: %q", err)
+	}
+	// A later run, within the same hour, overriding one of the predictions.
+	run2, err := ps.UpdateRun(PredictionRun{GeneratedAt: base.Add(30 * time.Minute), TrainingPoints: 12, R2: 0.7, Coefficients: map[string]float64{"offset": 1.5}},
+		Prediction{Timestamp: base.Add(2 * time.Hour), TempFanOff: 29},
+	)
+	if err != nil {
+		t.Fatalf("UpdateRun: %q", err)
+	}
+	if run1.Version != 1 || run2.Version != 2 {
+		t.Errorf("versions want:(1,2) got:(%d,%d)", run1.Version, run2.Version)
+	}
+
+	run, preds, err := ps.Latest()
+	if err != nil {
+		t.Fatalf("Latest: %q", err)
+	}
+	if run.Version != 2 || run.TrainingPoints != 12 || run.R2 != 0.7 || run.Coefficients["offset"] != 1.5 || !run.GeneratedAt.Equal(base.Add(30*time.Minute)) {
+		t.Errorf("Latest run want:%+v got:%+v", run2, run)
+	}
+	if len(preds) != 1 || preds[0].TempFanOff != 29 || !preds[0].Timestamp.Equal(base.Add(2*time.Hour)) {
+		t.Errorf("Latest predictions want:[29] got:%+v", preds)
+	}
+
+	all, err := ps.Fetch(base, base.Add(3*time.Hour))
+	if err != nil {
+		t.Fatalf("Fetch: %q", err)
+	}
+	if len(all) != 2 || all[0].Run != 2 || all[1].Run != 1 || all[1].TempFanOff != 30 {
+		t.Errorf("Fetch want runs [2 1] got:%+v", all)
+	}
+	runs, err := ps.FetchRuns(base, base.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("FetchRuns: %q", err)
+	}
+	if len(runs) != 2 || runs[0].Version != 2 || runs[1].Version != 1 {
+		t.Errorf("FetchRuns want versions [2 1] got:%+v", runs)
+	}
+}
@@ -0,0 +1,300 @@
+package tsmongo
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// quarantineSuffix suffixes the fields which store the readings rejected by a Validator.
+const quarantineSuffix = ".quarantine"
+
+// defaultLookback is how far back the readings compared to an incoming reading are looked for,
+// unless specified.
+const defaultLookback = time.Hour
+
+// Names of the rules readings are rejected by.
+const (
+	RuleRange  = "range"
+	RuleRate   = "rate"
+	RuleMedian = "median"
+)
+
+// ValidationRules specifies which incoming readings of a metric are plausible. Zero values
+// disable their rule.
+type ValidationRules struct {
+	// Min and Max bound the plausible values (inclusive), if set. The range rule is disabled if
+	// neither is.
+	Min *float64
+	Max *float64
+	// MaxRate is the maximum change per minute from the previous reading. Readings taken less
+	// than a minute apart can also change that much.
+	MaxRate float64
+	// Neighbors is the number of previous readings whose median is compared to the reading,
+	// which can not be farther than MaxDeviation from it. Readings without enough previous
+	// readings are not checked.
+	Neighbors    int
+	MaxDeviation float64
+	// Lookback is how far back previous readings are looked for. Defaults to an hour.
+	Lookback time.Duration
+}
+
+// ParseValidationRules parses rules in the "min=0,max=45,rate=0.5,median=5,deviation=3"
+// format, where median is the number of neighbors. The lookback accepts durations (e.g.
+// "lookback=30m").
+func ParseValidationRules(s string) (ValidationRules, error) {
+	var r ValidationRules
+	for _, kv := range strings.Split(s, ",") {
+		parts := strings.SplitN(strings.TrimSpace(kv), "=", 2)
+		if len(parts) != 2 {
+			return ValidationRules{}, fmt.Errorf("invalid validation rules %q: expected key=value", s)
+		}
+		var err error
+		switch parts[0] {
+		case "min":
+			r.Min, err = parseBound(parts[1])
+		case "max":
+			r.Max, err = parseBound(parts[1])
+		case "rate":
+			r.MaxRate, err = strconv.ParseFloat(parts[1], 64)
+		case "median":
+			r.Neighbors, err = strconv.Atoi(parts[1])
+		case "deviation":
+			r.MaxDeviation, err = strconv.ParseFloat(parts[1], 64)
+		case "lookback":
+			r.Lookback, err = time.ParseDuration(parts[1])
+		default:
+			err = fmt.Errorf("unknown key %s", parts[0])
+		}
+		if err != nil {
+			return ValidationRules{}, fmt.Errorf("invalid validation rules %q: %q", s, err)
+		}
+	}
+	if (r.Min != nil && r.Max != nil && *r.Min > *r.Max) || r.MaxRate < 0 || r.Neighbors < 0 || r.MaxDeviation < 0 || r.Lookback < 0 {
+		return ValidationRules{}, fmt.Errorf("invalid validation rules %q: negative or empty limits", s)
+	}
+	if r.Neighbors > 0 && r.MaxDeviation == 0 {
+		return ValidationRules{}, fmt.Errorf("invalid validation rules %q: median needs a deviation", s)
+	}
+	return r, nil
+}
+
+func parseBound(s string) (*float64, error) {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// Check returns the rule rejecting the reading, given the previous readings sorted by
+// descending timestamp, or an empty string if the reading is plausible.
+func (r ValidationRules) Check(reading TSRecord, previous []TSRecord) string {
+	v, ok := toFloat(reading.Value)
+	if !ok {
+		return "" // Not a number, left to the store.
+	}
+	if (r.Min != nil && v < *r.Min) || (r.Max != nil && v > *r.Max) {
+		return RuleRange
+	}
+	if r.MaxRate > 0 && len(previous) > 0 {
+		if prev, ok := toFloat(previous[0].Value); ok {
+			minutes := math.Max(reading.Timestamp.Sub(previous[0].Timestamp).Minutes(), 1)
+			if math.Abs(v-prev) > r.MaxRate*minutes {
+				return RuleRate
+			}
+		}
+	}
+	if r.Neighbors > 0 && len(previous) >= r.Neighbors {
+		values := make([]float64, 0, r.Neighbors)
+		for _, p := range previous[:r.Neighbors] {
+			if f, ok := toFloat(p.Value); ok {
+				values = append(values, f)
+			}
+		}
+		if len(values) > 0 && math.Abs(v-median(values)) > r.MaxDeviation {
+			return RuleMedian
+		}
+	}
+	return ""
+}
+
+func (r ValidationRules) lookback() time.Duration {
+	if r.Lookback > 0 {
+		return r.Lookback
+	}
+	return defaultLookback
+}
+
+func median(values []float64) float64 {
+	sort.Float64s(values)
+	n := len(values)
+	if n%2 == 1 {
+		return values[n/2]
+	}
+	return (values[n/2-1] + values[n/2]) / 2
+}
+
+// QuarantinedReading is a reading rejected by a Validator, waiting to be reviewed.
+type QuarantinedReading struct {
+	Series    Series    `json:"series"` // The series the reading was written to.
+	Timestamp time.Time `json:"timestamp"`
+	Value     float64   `json:"value"`
+	Rule      string    `json:"rule"`
+}
+
+// quarantineDoc is the stored representation of a quarantined reading.
+type quarantineDoc struct {
+	Value interface{} `bson:"value"`
+	Rule  string      `bson:"rule"`
+}
+
+// QuarantineSeries returns the series which stores the readings of the passed-in series
+// rejected by a Validator.
+func QuarantineSeries(series Series) Series {
+	return Series{series.Field + quarantineSuffix, series.Room, series.Tags}
+}
+
+// Validator is a Store which checks the samples written to the series of metrics with rules
+// (e.g. the temperature readings of every room), writing the implausible ones to the
+// quarantine series (see QuarantineSeries) instead. Quarantined readings are not an error:
+// they can be reviewed with Quarantined and stored with Release. Other writes and queries go
+// straight to the underlying store.
+type Validator struct {
+	Store
+	rules    map[string]ValidationRules // By field.
+	onReject func(QuarantinedReading)
+}
+
+// NewValidator creates a Validator enforcing the rules, keyed by metric name (e.g.
+// "temperature" or "humidity"). onReject, if not nil, is called with every quarantined reading.
+func NewValidator(s Store, rules map[string]ValidationRules, onReject func(QuarantinedReading)) *Validator {
+	byField := make(map[string]ValidationRules, len(rules))
+	for name, r := range rules {
+		byField[Metric{Name: name}.field()] = r
+	}
+	return &Validator{s, byField, onReject}
+}
+
+// UpsertSamplesContext stores the plausible samples in the series, and the others in its
+// quarantine series. Samples are compared to the previous readings only if they can be
+// fetched, so only errors writing the samples are returned.
+func (v *Validator) UpsertSamplesContext(ctx context.Context, series Series, val ...TSRecord) error {
+	rules, ok := v.rules[series.Field]
+	if !ok || len(val) == 0 {
+		return v.Store.UpsertSamplesContext(ctx, series, val...)
+	}
+	sorted := append([]TSRecord(nil), val...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Timestamp.Before(sorted[j].Timestamp) })
+	// Previous readings, sorted by descending timestamp. Accepted readings are added as they
+	// are checked. If they can not be fetched (e.g. the database is unreachable and the store
+	// is a Writer, which queues the samples), the samples are checked without them: readings
+	// must not be lost because their history is unavailable.
+	previous, err := v.Store.QuerySamplesContext(ctx, series, sorted[0].Timestamp.Add(-rules.lookback()), sorted[0].Timestamp.Add(-time.Nanosecond))
+	if err != nil {
+		previous = nil
+	}
+	var accepted, rejected []TSRecord
+	for _, tr := range sorted {
+		rule := rules.Check(tr, previous)
+		if rule == "" {
+			accepted = append(accepted, tr)
+			previous = append([]TSRecord{tr}, previous...)
+			continue
+		}
+		rejected = append(rejected, TSRecord{tr.Timestamp, quarantineDoc{tr.Value, rule}})
+		if v.onReject != nil {
+			f, _ := toFloat(tr.Value)
+			v.onReject(QuarantinedReading{series, tr.Timestamp, f, rule})
+		}
+	}
+	if len(rejected) > 0 {
+		if err := v.Store.UpsertSamplesContext(ctx, QuarantineSeries(series), rejected...); err != nil {
+			return err
+		}
+	}
+	if len(accepted) > 0 {
+		return v.Store.UpsertSamplesContext(ctx, series, accepted...)
+	}
+	return nil
+}
+
+// Quarantined returns the readings quarantined within the considered period, of every series,
+// sorted by descending timestamp.
+func Quarantined(ctx context.Context, s Store, start time.Time, finish time.Time) ([]QuarantinedReading, error) {
+	all, err := s.ListSeriesContext(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	var ret []QuarantinedReading
+	for _, q := range all {
+		if !strings.HasSuffix(q.Field, quarantineSuffix) {
+			continue
+		}
+		series := Series{strings.TrimSuffix(q.Field, quarantineSuffix), q.Room, q.Tags}
+		trs, err := s.QuerySamplesContext(ctx, q, start, finish)
+		if err != nil {
+			return nil, err
+		}
+		for _, tr := range trs {
+			var doc quarantineDoc
+			if err := decodeValue(tr.Value, &doc); err != nil {
+				return nil, &DecodeError{q, tr.Timestamp, tr.Value, "quarantine document"}
+			}
+			v, err := floatCodec.decodeRecord(q, TSRecord{tr.Timestamp, doc.Value})
+			if err != nil {
+				return nil, err
+			}
+			ret = append(ret, QuarantinedReading{series, tr.Timestamp.Local(), v.(float64), doc.Rule})
+		}
+	}
+	sort.SliceStable(ret, func(i, j int) bool { return ret[i].Timestamp.After(ret[j].Timestamp) })
+	return ret, nil
+}
+
+// Release stores the quarantined reading of the series taken at t (e.g. a real temperature
+// drop), removing it from the quarantine once stored, so it is kept there if storing fails. It
+// returns ErrNotFound if there is no such reading.
+func Release(ctx context.Context, s Store, series Series, t time.Time) error {
+	tr, err := quarantined(ctx, s, series, t)
+	if err != nil {
+		return err
+	}
+	var doc quarantineDoc
+	if err := decodeValue(tr.Value, &doc); err != nil {
+		return &DecodeError{QuarantineSeries(series), tr.Timestamp, tr.Value, "quarantine document"}
+	}
+	if err := s.UpsertSamplesContext(ctx, series, TSRecord{tr.Timestamp, doc.Value}); err != nil {
+		return err
+	}
+	// Removed meanwhile (e.g. released twice) is fine, the reading is stored either way.
+	_, err = s.RemoveSamplesContext(ctx, QuarantineSeries(series), tr.Timestamp)
+	return err
+}
+
+// Discard removes the quarantined reading of the series taken at t. It returns ErrNotFound if
+// there is no such reading.
+func Discard(ctx context.Context, s Store, series Series, t time.Time) error {
+	n, err := s.RemoveSamplesContext(ctx, QuarantineSeries(series), sampleTime(t))
+	if err == nil && n == 0 {
+		return ErrNotFound
+	}
+	return err
+}
+
+// quarantined returns the quarantined reading of the series taken at t.
+func quarantined(ctx context.Context, s Store, series Series, t time.Time) (TSRecord, error) {
+	t = sampleTime(t)
+	trs, err := s.QuerySamplesContext(ctx, QuarantineSeries(series), t, t)
+	if err != nil {
+		return TSRecord{}, err
+	}
+	if len(trs) == 0 {
+		return TSRecord{}, ErrNotFound
+	}
+	return trs[0], nil
+}
@@ -0,0 +1,203 @@
+package tsmongo
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// bound returns a pointer to the range bound v.
+func bound(v float64) *float64 {
+	return &v
+}
+
+func TestParseValidationRules(t *testing.T) {
+	got, err := ParseValidationRules("min=5,max=45,rate=0.5,median=3,deviation=2,lookback=30m")
+	if err != nil || got.Min == nil || *got.Min != 5 || got.Max == nil || *got.Max != 45 {
+		t.Errorf("ParseValidationRules want min:5 max:45 got:%+v (%v)", got, err)
+	}
+	got.Min, got.Max = nil, nil
+	want := ValidationRules{MaxRate: 0.5, Neighbors: 3, MaxDeviation: 2, Lookback: 30 * time.Minute}
+	if got != want {
+		t.Errorf("ParseValidationRules want:%+v got:%+v", want, got)
+	}
+	for _, s := range []string{"min=5", "max=45", "min=-5,max=0"} {
+		if _, err := ParseValidationRules(s); err != nil {
+			t.Errorf("ParseValidationRules(%q) want:nil got:%q", s, err)
+		}
+	}
+	for _, s := range []string{"", "min=5,max=0", "max=a", "rate=-1", "median=3", "foo=1"} {
+		if _, err := ParseValidationRules(s); err == nil {
+			t.Errorf("ParseValidationRules(%q) want error got:nil", s)
+		}
+	}
+}
+
+func TestValidator(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemStore()
+	var rejects []QuarantinedReading
+	rules := map[string]ValidationRules{
+		"temperature": {Min: bound(5), Max: bound(45), MaxRate: 1},
+		"humidity":    {Neighbors: 3, MaxDeviation: 10},
+	}
+	v := NewValidator(s, rules, func(r QuarantinedReading) { rejects = append(rejects, r) })
+	base := time.Date(2018, 7, 1, 22, 0, 0, 0, time.UTC)
+
+	bs := NewBedroomService(v, DefaultRoom)
+	bs.UpdateTemperature(base, 25)
+	bs.UpdateTemperature(base.Add(time.Minute), 85)    // Out of range.
+	bs.UpdateTemperature(base.Add(2*time.Minute), 20)  // Dropped 5 degrees in 2 minutes.
+	bs.UpdateTemperature(base.Add(10*time.Minute), 22) // 3 degrees in 10 minutes.
+	trs, err := s.QuerySamples(bedroomSeries, base, base.Add(time.Hour))
+	if err != nil || len(trs) != 2 {
+		t.Fatalf("QuerySamples want 2 accepted readings got:%+v (%v)", trs, err)
+	}
+	if len(rejects) != 2 || rejects[0].Rule != RuleRange || rejects[1].Rule != RuleRate || rejects[1].Value != 20 {
+		t.Errorf("rejects want range and rate got:%+v", rejects)
+	}
+
+	// The median of the previous readings is checked once there are enough of them, readings of
+	// a batch being compared to the ones accepted before them.
+	ss := NewSensorService(v, DefaultRoom)
+	humidity := Metric{Name: "humidity"}
+	for i, h := range []float64{60, 95, 62, 61, 90, 63} {
+		if err := ss.Update(base.Add(time.Duration(i)*time.Minute), SensorReading{Metric: humidity, Value: h}); err != nil {
+			t.Fatalf("Update: %q", err)
+		}
+	}
+	if len(rejects) != 3 || rejects[2].Value != 90 || rejects[2].Rule != RuleMedian {
+		t.Errorf("rejects want humidity 90 by median got:%+v", rejects)
+	}
+
+	q, err := Quarantined(ctx, s, base, base.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Quarantined: %q", err)
+	}
+	if len(q) != 3 || q[0].Series.Field != humidity.field() || !q[2].Timestamp.Equal(base.Add(time.Minute)) || q[2].Value != 85 {
+		t.Errorf("Quarantined want 3 readings, latest first got:%+v", q)
+	}
+	if metrics, _ := ss.Metrics(); len(metrics) != 2 {
+		t.Errorf("Metrics want humidity and temperature got:%+v", metrics)
+	}
+
+	// The temperature drop was real.
+	if err := Release(ctx, s, bedroomSeries, base.Add(2*time.Minute)); err != nil {
+		t.Fatalf("Release: %q", err)
+	}
+	if trs, _ := s.QuerySamples(bedroomSeries, base, base.Add(time.Hour)); len(trs) != 3 {
+		t.Errorf("QuerySamples after release want 3 readings got:%+v", trs)
+	}
+	if err := Discard(ctx, s, bedroomSeries, base.Add(time.Minute)); err != nil {
+		t.Fatalf("Discard: %q", err)
+	}
+	if err := Discard(ctx, s, bedroomSeries, base.Add(time.Minute)); err != ErrNotFound {
+		t.Errorf("Discard twice want:%q got:%q", ErrNotFound, err)
+	}
+	if q, _ := Quarantined(ctx, s, base, base.Add(time.Hour)); len(q) != 1 || q[0].Value != 90 {
+		t.Errorf("Quarantined want humidity 90 got:%+v", q)
+	}
+}
+
+func TestValidationRulesOneBound(t *testing.T) {
+	base := time.Date(2018, 7, 1, 22, 0, 0, 0, time.UTC)
+	maxOnly, err := ParseValidationRules("max=45")
+	if err != nil {
+		t.Fatalf("ParseValidationRules: %q", err)
+	}
+	minOnly, err := ParseValidationRules("min=5")
+	if err != nil {
+		t.Fatalf("ParseValidationRules: %q", err)
+	}
+	testCases := []struct {
+		rules ValidationRules
+		value float64
+		want  string
+	}{
+		{maxOnly, -10, ""},
+		{maxOnly, 45, ""},
+		{maxOnly, 46, RuleRange},
+		{minOnly, 4, RuleRange},
+		{minOnly, 5, ""},
+		{minOnly, 100, ""},
+	}
+	for _, tc := range testCases {
+		if got := tc.rules.Check(TSRecord{base, tc.value}, nil); got != tc.want {
+			t.Errorf("Check(%v) with %+v want:%q got:%q", tc.value, tc.rules, tc.want, got)
+		}
+	}
+}
+
+// downStore is unreachable, as a database during an outage.
+type downStore struct {
+	*flakyStore
+}
+
+func (d downStore) QuerySamplesContext(ctx context.Context, series Series, start time.Time, finish time.Time) ([]TSRecord, error) {
+	return nil, &UnreachableError{d.err}
+}
+
+func TestValidatorWithoutHistory(t *testing.T) {
+	s := newFlakyStore()
+	s.setDown(true)
+	w, err := NewWriter(downStore{s}, fastRetries)
+	if err != nil {
+		t.Fatalf("NewWriter: %q", err)
+	}
+	defer w.Close()
+	v := NewValidator(w, map[string]ValidationRules{"temperature": {Min: bound(5), Max: bound(45), MaxRate: 1}}, nil)
+	bs := NewBedroomService(v, DefaultRoom)
+	base := time.Date(2018, 7, 1, 22, 0, 0, 0, time.UTC)
+	if err := bs.UpdateTemperature(base, 25); err != nil {
+		t.Fatalf("UpdateTemperature with unreachable history want:nil got:%q", err)
+	}
+	if d := w.QueueDepth(); d != 1 {
+		t.Errorf("QueueDepth want:1 got:%d", d)
+	}
+	// Rules which need no history are still enforced.
+	if err := bs.UpdateTemperature(base.Add(time.Minute), 85); err != nil {
+		t.Fatalf("UpdateTemperature: %q", err)
+	}
+	if d := w.QueueDepth(); d != 2 {
+		t.Errorf("QueueDepth want:2 got:%d", d)
+	}
+	s.setDown(false)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := w.Flush(ctx); err != nil {
+		t.Fatalf("Flush: %q", err)
+	}
+	if trs, _ := s.QuerySamples(bedroomSeries, base, base.Add(time.Hour)); len(trs) != 1 || trs[0].Value != 25.0 {
+		t.Errorf("QuerySamples want the plausible reading got:%+v", trs)
+	}
+	if trs, _ := s.QuerySamples(QuarantineSeries(bedroomSeries), base, base.Add(time.Hour)); len(trs) != 1 {
+		t.Errorf("QuerySamples of quarantine want the implausible reading got:%+v", trs)
+	}
+}
+
+func TestReleaseKeepsReadingOnError(t *testing.T) {
+	ctx := context.Background()
+	s := newFlakyStore()
+	base := time.Date(2018, 7, 1, 22, 0, 0, 0, time.UTC)
+	q := QuarantineSeries(bedroomSeries)
+	if err := s.UpsertSamples(q, TSRecord{base, quarantineDoc{85.0, RuleRange}}); err != nil {
+		t.Fatalf("UpsertSamples: %q", err)
+	}
+	s.setDown(true)
+	if err := Release(ctx, s, bedroomSeries, base); err == nil {
+		t.Fatalf("Release with failing store want error got:nil")
+	}
+	if trs, _ := s.QuerySamples(q, base, base); len(trs) != 1 {
+		t.Errorf("QuerySamples of quarantine want the reading kept got:%+v", trs)
+	}
+	s.setDown(false)
+	if err := Release(ctx, s, bedroomSeries, base); err != nil {
+		t.Fatalf("Release: %q", err)
+	}
+	if trs, _ := s.QuerySamples(bedroomSeries, base, base); len(trs) != 1 || trs[0].Value != 85.0 {
+		t.Errorf("QuerySamples want the released reading got:%+v", trs)
+	}
+	if trs, _ := s.QuerySamples(q, base, base); len(trs) != 0 {
+		t.Errorf("QuerySamples of quarantine want no readings got:%+v", trs)
+	}
+}
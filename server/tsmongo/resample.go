@@ -0,0 +1,261 @@
+package tsmongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/danielfireman/temp-to-go/server/weather"
+)
+
+// Fill specifies how resampling fills the steps without observations.
+type Fill int
+
+const (
+	// FillNone leaves steps without observations out.
+	FillNone Fill = iota
+	// FillPrevious carries the last observation forward.
+	FillPrevious
+	// FillLinear interpolates linearly between the observations around the step. Steps before
+	// the first or after the last observation are left out.
+	FillLinear
+)
+
+// ResampleOptions configures resampling.
+type ResampleOptions struct {
+	// Step is the distance between resampled points, which are aligned to multiples of it
+	// (e.g. to UTC hours, for hourly steps). Defaults to an hour.
+	Step time.Duration
+	// Fill is the strategy used to fill steps without observations.
+	Fill Fill
+	// MaxGap, if not zero, limits filling to steps at most MaxGap after the previous
+	// observation and, for FillLinear, to gaps between observations at most MaxGap long.
+	MaxGap time.Duration
+}
+
+// Point is a resampled value, which was either observed within its step or filled in.
+type Point struct {
+	Timestamp time.Time `json:"timestamp"`
+	Value     float64   `json:"value"`
+	Filled    bool      `json:"filled,omitempty"`
+}
+
+// observation is a record decoded to the numeric values being resampled.
+type observation struct {
+	TSRecord
+	values []float64
+}
+
+// resampledPoint is a resampled step, along with the observation it was taken from (for filled
+// steps, the previous observation).
+type resampledPoint struct {
+	timestamp time.Time
+	values    []float64
+	filled    bool
+	source    *observation
+}
+
+// errStopStream is used internally to stop streaming once the wanted records arrived.
+var errStopStream = errors.New("stream stopped")
+
+// resample aligns the records of the series within the range into a grid of steps, sorted by
+// ascending timestamp. Each step takes the last record within it, records are decoded by the
+// values function (records decoded to no values are skipped). Steps without records are
+// filled as specified by the options.
+func resample(ctx context.Context, s Store, series Series, start, finish time.Time, opts ResampleOptions, values func(TSRecord) ([]float64, error)) ([]resampledPoint, error) {
+	step := opts.Step
+	if step <= 0 {
+		step = time.Hour
+	}
+	first, last := start.Truncate(step), finish.Truncate(step)
+	if last.Before(first) {
+		return nil, nil
+	}
+	res := Hourly
+	if step%time.Hour != 0 {
+		res = FullResolution
+	}
+	decode := func(tr TSRecord) (*observation, error) {
+		vs, err := values(tr)
+		if err != nil || vs == nil {
+			return nil, err
+		}
+		return &observation{tr, vs}, nil
+	}
+	// Records right outside the range, used to fill the steps at its borders.
+	neighbour := func(start, finish time.Time, order Order) (*observation, error) {
+		var ret *observation
+		err := s.StreamContext(ctx, series, start, finish, res, order, func(tr TSRecord) error {
+			var err error
+			if ret, err = decode(tr); err != nil || ret == nil {
+				return err
+			}
+			return errStopStream
+		})
+		if err != nil && err != errStopStream {
+			return nil, err
+		}
+		return ret, nil
+	}
+
+	steps := make([]*observation, int(last.Sub(first)/step)+1)
+	err := s.StreamContext(ctx, series, first, last.Add(step-time.Nanosecond), res, Ascending, func(tr TSRecord) error {
+		i := int(tr.Timestamp.Sub(first) / step)
+		if i < 0 || i >= len(steps) {
+			return nil
+		}
+		o, err := decode(tr)
+		if o != nil {
+			steps[i] = o
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	var prev, next *observation
+	if opts.Fill != FillNone {
+		if prev, err = neighbour(time.Time{}, first.Add(-time.Nanosecond), Descending); err != nil {
+			return nil, err
+		}
+	}
+	// nexts[i] is the first observation after step i.
+	nexts := make([]*observation, len(steps))
+	if opts.Fill == FillLinear {
+		if next, err = neighbour(last.Add(step), watchHorizon, Ascending); err != nil {
+			return nil, err
+		}
+		for i := len(steps) - 1; i >= 0; i-- {
+			nexts[i] = next
+			if steps[i] != nil {
+				next = steps[i]
+			}
+		}
+	}
+
+	var ret []resampledPoint
+	for i, o := range steps {
+		t := first.Add(time.Duration(i) * step).Local()
+		if o != nil {
+			ret = append(ret, resampledPoint{t, o.values, false, o})
+			prev = o
+			continue
+		}
+		if prev == nil || (opts.MaxGap > 0 && t.Sub(prev.Timestamp) > opts.MaxGap) {
+			continue
+		}
+		switch opts.Fill {
+		case FillPrevious:
+			ret = append(ret, resampledPoint{t, prev.values, true, prev})
+		case FillLinear:
+			n := nexts[i]
+			if n == nil || (opts.MaxGap > 0 && n.Timestamp.Sub(prev.Timestamp) > opts.MaxGap) {
+				continue
+			}
+			w := float64(t.Sub(prev.Timestamp)) / float64(n.Timestamp.Sub(prev.Timestamp))
+			vs := make([]float64, len(prev.values))
+			for j := range vs {
+				vs[j] = prev.values[j] + w*(n.values[j]-prev.values[j])
+			}
+			ret = append(ret, resampledPoint{t, vs, true, prev})
+		}
+	}
+	return ret, nil
+}
+
+// resamplePoints resamples a series of numeric values.
+func resamplePoints(ctx context.Context, s Store, series Series, start, finish time.Time, opts ResampleOptions, decode func(Series, TSRecord) (float64, error)) ([]Point, error) {
+	rps, err := resample(ctx, s, series, start, finish, opts, func(tr TSRecord) ([]float64, error) {
+		v, err := decode(series, tr)
+		if err != nil {
+			return nil, err
+		}
+		return []float64{v}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]Point, len(rps))
+	for i, p := range rps {
+		ret[i] = Point{p.timestamp, p.values[0], p.filled}
+	}
+	return ret, nil
+}
+
+// Resample returns the bedroom temperature resampled into regular steps within the range,
+// sorted by ascending timestamp.
+func (b *BedroomService) Resample(start time.Time, finish time.Time, opts ResampleOptions) ([]Point, error) {
+	return b.ResampleContext(context.Background(), start, finish, opts)
+}
+
+// ResampleContext is like Resample, but honors the context deadline and cancellation.
+func (b *BedroomService) ResampleContext(ctx context.Context, start time.Time, finish time.Time, opts ResampleOptions) ([]Point, error) {
+	return resamplePoints(ctx, b.store, b.series, start, finish, opts, decodeFloat)
+}
+
+// Resample returns the fan status (as a number, e.g. 1 for FanLowSpeed) resampled into regular
+// steps within the range, sorted by ascending timestamp. FillPrevious is usually the fill which
+// makes sense for it.
+func (f FanService) Resample(start time.Time, finish time.Time, opts ResampleOptions) ([]Point, error) {
+	return f.ResampleContext(context.Background(), start, finish, opts)
+}
+
+// ResampleContext is like Resample, but honors the context deadline and cancellation.
+func (f FanService) ResampleContext(ctx context.Context, start time.Time, finish time.Time, opts ResampleOptions) ([]Point, error) {
+	return resamplePoints(ctx, f.store, f.series, start, finish, opts, func(series Series, tr TSRecord) (float64, error) {
+		s, err := decodeInt(series, tr)
+		return float64(s), err
+	})
+}
+
+// Resample returns the readings of the metric resampled into regular steps within the range,
+// sorted by ascending timestamp.
+func (ss *SensorService) Resample(metric string, start time.Time, finish time.Time, opts ResampleOptions) ([]Point, error) {
+	return ss.ResampleContext(context.Background(), metric, start, finish, opts)
+}
+
+// ResampleContext is like Resample, but honors the context deadline and cancellation.
+func (ss *SensorService) ResampleContext(ctx context.Context, metric string, start time.Time, finish time.Time, opts ResampleOptions) ([]Point, error) {
+	m, err := ss.metric(ctx, metric)
+	if err != nil {
+		return nil, err
+	}
+	return resamplePoints(ctx, ss.store, ss.series(m), start, finish, opts, decodeFloat)
+}
+
+// WeatherPoint is a resampled weather state, which was either observed within its step or
+// filled in.
+type WeatherPoint struct {
+	weather.State
+	Filled bool
+}
+
+// Resample returns the weather resampled into regular steps within the range, sorted by
+// ascending timestamp. When interpolating, the description and wind direction are the ones
+// of the previous observation.
+func (w *WeatherService) Resample(start time.Time, finish time.Time, opts ResampleOptions) ([]WeatherPoint, error) {
+	return w.ResampleContext(context.Background(), start, finish, opts)
+}
+
+// ResampleContext is like Resample, but honors the context deadline and cancellation.
+func (w *WeatherService) ResampleContext(ctx context.Context, start time.Time, finish time.Time, opts ResampleOptions) ([]WeatherPoint, error) {
+	rps, err := resample(ctx, w.store, weatherSeries, start, finish, opts, func(tr TSRecord) ([]float64, error) {
+		var s weatherState
+		if err := decodeValue(tr.Value, &s); err != nil {
+			return nil, err
+		}
+		return []float64{s.Temp, s.Humidity, s.Rain, s.Cloudiness, s.Wind.Speed}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]WeatherPoint, len(rps))
+	for i, p := range rps {
+		var s weatherState
+		decodeValue(p.source.Value, &s) // Already decoded once.
+		state := fromStore(s, p.timestamp)
+		state.Temp, state.Humidity, state.Rain, state.Cloudiness, state.Wind.Speed = p.values[0], p.values[1], p.values[2], p.values[3], p.values[4]
+		ret[i] = WeatherPoint{state, p.filled}
+	}
+	return ret, nil
+}
@@ -0,0 +1,95 @@
+package tsmongo
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/danielfireman/temp-to-go/server/weather"
+)
+
+func TestResample(t *testing.T) {
+	s := NewMemStore()
+	base := time.Date(2018, 7, 1, 10, 0, 0, 0, time.UTC)
+	bs := NewBedroomService(s, DefaultRoom)
+	bs.UpdateTemperature(base.Add(-2*time.Hour), 20) // Before the range.
+	bs.UpdateTemperature(base.Add(10*time.Minute), 24)
+	bs.UpdateTemperature(base.Add(20*time.Minute), 25) // Last within the hour wins.
+	bs.UpdateTemperature(base.Add(4*time.Hour), 29)    // 3 hours gap.
+	bs.UpdateTemperature(base.Add(7*time.Hour), 20)    // After the range.
+
+	obs := func(h int, v float64) Point { return Point{base.Add(time.Duration(h) * time.Hour), v, false} }
+	fill := func(h int, v float64) Point { return Point{base.Add(time.Duration(h) * time.Hour), v, true} }
+	cases := []struct {
+		name string
+		opts ResampleOptions
+		want []Point
+	}{
+		{"none", ResampleOptions{}, []Point{obs(0, 25), obs(4, 29)}},
+		{"previous", ResampleOptions{Fill: FillPrevious}, []Point{
+			fill(-1, 20), obs(0, 25), fill(1, 25), fill(2, 25), fill(3, 25), obs(4, 29), fill(5, 29),
+		}},
+		{"previous max gap", ResampleOptions{Fill: FillPrevious, MaxGap: 2 * time.Hour}, []Point{
+			fill(-1, 20), obs(0, 25), fill(1, 25), fill(2, 25), obs(4, 29), fill(5, 29),
+		}},
+		{"linear", ResampleOptions{Fill: FillLinear}, []Point{
+			fill(-1, 22.5), obs(0, 25), fill(1, 26), fill(2, 27), fill(3, 28), obs(4, 29), fill(5, 26),
+		}},
+		{"linear max gap", ResampleOptions{Fill: FillLinear, MaxGap: 3 * time.Hour}, []Point{
+			fill(-1, 22.5), obs(0, 25), obs(4, 29), fill(5, 26),
+		}},
+		{"two hours step", ResampleOptions{Step: 2 * time.Hour, Fill: FillPrevious}, []Point{
+			obs(-2, 20), obs(0, 25), fill(2, 25), obs(4, 29),
+		}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := bs.Resample(base.Add(-time.Hour), base.Add(5*time.Hour+30*time.Minute), c.opts)
+			if err != nil {
+				t.Fatalf("Resample: %q", err)
+			}
+			checkPoints(t, c.want, got)
+		})
+	}
+
+	// Sub-hour steps use samples.
+	got, err := bs.Resample(base, base.Add(29*time.Minute), ResampleOptions{Step: 10 * time.Minute})
+	if err != nil {
+		t.Fatalf("Resample: %q", err)
+	}
+	checkPoints(t, []Point{{base.Add(10 * time.Minute), 24, false}, {base.Add(20 * time.Minute), 25, false}}, got)
+}
+
+func TestResampleWeather(t *testing.T) {
+	ws := NewWeatherService(NewMemStore())
+	base := time.Date(2018, 7, 1, 10, 0, 0, 0, time.UTC)
+	ws.Update(weather.State{Timestamp: base, Temp: 26, Humidity: 80, Description: weather.Description{Text: "rain"}})
+	ws.Update(weather.State{Timestamp: base.Add(2 * time.Hour), Temp: 28, Humidity: 70, Description: weather.Description{Text: "clear"}})
+
+	got, err := ws.Resample(base, base.Add(2*time.Hour), ResampleOptions{Fill: FillLinear})
+	if err != nil {
+		t.Fatalf("Resample: %q", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("Resample want 3 points got:%+v", got)
+	}
+	filled := got[1]
+	if !filled.Filled || filled.Temp != 27 || filled.Humidity != 75 || filled.Description.Text != "rain" || !filled.Timestamp.Equal(base.Add(time.Hour)) {
+		t.Errorf("Resample filled point want:{27C 75%% rain} got:%+v", filled)
+	}
+	if got[0].Filled || got[2].Filled || got[2].Description.Text != "clear" {
+		t.Errorf("Resample observed points got:%+v", got)
+	}
+}
+
+func checkPoints(t *testing.T, want, got []Point) {
+	t.Helper()
+	if len(want) != len(got) {
+		t.Fatalf("want:%+v got:%+v", want, got)
+	}
+	for i := range want {
+		if !want[i].Timestamp.Equal(got[i].Timestamp) || math.Abs(want[i].Value-got[i].Value) > 1e-9 || want[i].Filled != got[i].Filled {
+			t.Errorf("[%d] want:%+v got:%+v", i, want[i], got[i])
+		}
+	}
+}
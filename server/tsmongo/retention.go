@@ -0,0 +1,200 @@
+package tsmongo
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Fields names the series types stored in the timeseries, which retention policies apply to.
+var Fields = []string{bedroomField, fanField, acField, weatherField, forecastField, predictionField}
+
+// rollupSuffix suffixes the fields which store rollups.
+const rollupSuffix = ".rollup"
+
+// rollupPaths maps the series which can be rolled up to the path of their numeric value.
+// Sensor metrics can also be rolled up.
+var rollupPaths = map[string]string{
+	bedroomField:  "",
+	fanField:      "",
+	weatherField:  "temp",
+	forecastField: "temp",
+}
+
+// RetentionPolicy specifies how long the data of a series is kept. Raw data older than Raw is
+// downsampled into rollups (one AggregateBucket per Window), which are kept for Rollup (counting
+// from now) and then deleted. If Rollup is zero, raw data is deleted without being rolled up.
+type RetentionPolicy struct {
+	Raw      time.Duration
+	Rollup   time.Duration
+	Window   Window         // Defaults to DayWindow.
+	Location *time.Location // Timezone windows are aligned to. Defaults to UTC.
+}
+
+// RetentionReport describes what enforcing a retention policy did.
+type RetentionReport struct {
+	Series   Series
+	RolledUp int // Number of rollup buckets written.
+	Removed  int // Number of raw hour buckets removed.
+	Expired  int // Number of rollup buckets removed.
+}
+
+func (r RetentionReport) String() string {
+	return fmt.Sprintf("%s: %d rollups written, %d raw hours removed, %d rollups expired", r.Series, r.RolledUp, r.Removed, r.Expired)
+}
+
+// ParseRetentionPolicy parses policies in the "raw=30d,rollup=730d" format. Durations accept
+// the "d" (day) unit, besides the ones accepted by time.ParseDuration. The window is optional
+// and can be set to hour, day or week (e.g. "raw=30d,rollup=730d,window=week").
+func ParseRetentionPolicy(s string) (RetentionPolicy, error) {
+	p := RetentionPolicy{Window: DayWindow}
+	for _, kv := range strings.Split(s, ",") {
+		parts := strings.SplitN(strings.TrimSpace(kv), "=", 2)
+		if len(parts) != 2 {
+			return RetentionPolicy{}, fmt.Errorf("invalid retention policy %q: expected key=value", s)
+		}
+		var err error
+		switch parts[0] {
+		case "raw":
+			p.Raw, err = parseDays(parts[1])
+		case "rollup":
+			p.Rollup, err = parseDays(parts[1])
+		case "window":
+			switch parts[1] {
+			case "hour":
+				p.Window = HourWindow
+			case "day":
+				p.Window = DayWindow
+			case "week":
+				p.Window = WeekWindow
+			default:
+				err = fmt.Errorf("unknown window %s", parts[1])
+			}
+		default:
+			err = fmt.Errorf("unknown key %s", parts[0])
+		}
+		if err != nil {
+			return RetentionPolicy{}, fmt.Errorf("invalid retention policy %q: %q", s, err)
+		}
+	}
+	if p.Raw <= 0 {
+		return RetentionPolicy{}, fmt.Errorf("invalid retention policy %q: raw retention must be positive", s)
+	}
+	if p.Rollup != 0 && p.Rollup < p.Raw {
+		return RetentionPolicy{}, fmt.Errorf("invalid retention policy %q: rollups must be kept longer than raw data", s)
+	}
+	return p, nil
+}
+
+func parseDays(s string) (time.Duration, error) {
+	if strings.HasSuffix(s, "d") {
+		d, err := strconv.Atoi(strings.TrimSuffix(s, "d"))
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(d) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(s)
+}
+
+// RollupField returns the name of the field which stores the rollups of the passed-in field.
+func RollupField(field string) string {
+	return field + rollupSuffix
+}
+
+// RollupSeries returns the series which stores the rollups of the passed-in series.
+func RollupSeries(series Series) Series {
+	return Series{RollupField(series.Field), series.Room, series.Tags}
+}
+
+// ApplyRetention enforces the retention policy over the series. Only complete windows
+// newer than the last rollup are rolled up, and rollups are written before raw data is removed,
+// so it is safe to run it repeatedly (even after an interrupted run). Raw hours are only removed
+// once they are entirely before the cutoff: windows may not be aligned to UTC hours (e.g. in the
+// Asia/Kolkata timezone).
+func ApplyRetention(ctx context.Context, s Store, series Series, p RetentionPolicy, now time.Time) (RetentionReport, error) {
+	report := RetentionReport{Series: series}
+	path, canRollup := rollupPaths[series.Field]
+	if isSensorField(series.Field) {
+		canRollup = true
+	}
+	if p.Rollup > 0 && !canRollup {
+		return report, fmt.Errorf("series %s can not be rolled up", series)
+	}
+	loc := p.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	// Moving the cutoff back to the beginning of its window, so partial windows are kept raw.
+	cutoff := p.Window.start(now.Add(-p.Raw).In(loc))
+	beginning := time.Unix(0, 0)
+	last := cutoff.Add(-time.Millisecond) // Mongo stores dates with millisecond precision.
+
+	if p.Rollup > 0 {
+		expiry := now.Add(-p.Rollup)
+		// Windows already rolled up may have lost part of their raw data (e.g. the beginning of
+		// an hour kept raw), so they are never rolled up again.
+		start := beginning
+		switch tr, err := s.LastContext(ctx, RollupSeries(series)); err {
+		case nil:
+			var b AggregateBucket
+			if err := decodeValue(tr.Value, &b); err != nil {
+				return report, &DecodeError{RollupSeries(series), tr.Timestamp, tr.Value, "rollup"}
+			}
+			start = p.Window.next(p.Window.start(b.Start.In(loc)))
+		case ErrNotFound:
+		default:
+			return report, err
+		}
+		buckets, err := s.AggregateContext(ctx, series, path, AggregateQuery{
+			Start:    start,
+			Finish:   last,
+			Window:   p.Window,
+			Location: loc,
+		})
+		if err != nil {
+			return report, err
+		}
+		var trs []TSRecord
+		for _, b := range buckets {
+			if !b.Start.Before(expiry) { // Rollups which would expire right away are skipped.
+				trs = append(trs, TSRecord{b.Start, b})
+			}
+		}
+		if err := s.UpsertContext(ctx, RollupSeries(series), trs...); err != nil {
+			return report, err
+		}
+		report.RolledUp = len(trs)
+
+		expired, err := s.RemoveRangeContext(ctx, RollupSeries(series), beginning, expiry)
+		if err != nil {
+			return report, err
+		}
+		report.Expired = expired
+	}
+
+	removed, err := s.RemoveRangeContext(ctx, series, beginning, cutoff.Add(-time.Hour))
+	if err != nil {
+		return report, err
+	}
+	report.Removed = removed
+	return report, nil
+}
+
+// FetchRollups returns the rollups of the series within the range, sorted by descending
+// window start.
+func FetchRollups(ctx context.Context, s Store, series Series, start time.Time, finish time.Time) ([]AggregateBucket, error) {
+	trs, err := s.QueryContext(ctx, RollupSeries(series), start, finish)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]AggregateBucket, len(trs))
+	for i, tr := range trs {
+		if err := decodeValue(tr.Value, &ret[i]); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
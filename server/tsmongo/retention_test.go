@@ -0,0 +1,114 @@
+package tsmongo
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestApplyRetention(t *testing.T) {
+	s := NewMemStore()
+	bs := NewBedroomService(s, DefaultRoom)
+	now := time.Date(2018, 7, 31, 12, 0, 0, 0, time.UTC)
+	for d := 0; d < 40; d++ {
+		day := now.AddDate(0, 0, -d).Truncate(24 * time.Hour)
+		bs.UpdateTemperature(day.Add(2*time.Hour), 20)
+		bs.UpdateTemperature(day.Add(3*time.Hour), 30)
+	}
+	p, err := ParseRetentionPolicy("raw=30d,rollup=35d")
+	if err != nil {
+		t.Fatalf("ParseRetentionPolicy: %q", err)
+	}
+
+	report, err := ApplyRetention(context.Background(), s, bedroomSeries, p, now)
+	if err != nil {
+		t.Fatalf("ApplyRetention: %q", err)
+	}
+	// Days before July 1st (31 to 39 days ago, each with two raw hours) are removed. Only the ones
+	// newer than 35 days are rolled up.
+	want := RetentionReport{Series: bedroomSeries, RolledUp: 4, Removed: 18}
+	if !reflect.DeepEqual(report, want) {
+		t.Errorf("report want:%+v got:%+v", want, report)
+	}
+	rollups, err := FetchRollups(context.Background(), s, bedroomSeries, now.AddDate(-1, 0, 0), now)
+	if err != nil {
+		t.Fatalf("FetchRollups: %q", err)
+	}
+	if len(rollups) != 4 {
+		t.Fatalf("len(rollups) want:4 got:%d", len(rollups))
+	}
+	if r := rollups[0]; r.Min != 20 || r.Max != 30 || r.Mean != 25 || r.Count != 2 {
+		t.Errorf("rollup want:{Min:20 Max:30 Mean:25 Count:2} got:%+v", r)
+	}
+	raw, _ := bs.FetchState(now.AddDate(-1, 0, 0), now, Hourly)
+	if len(raw) != 62 {
+		t.Errorf("len(raw) want:62 got:%d", len(raw))
+	}
+
+	// Running it again changes nothing, a day later the oldest rollup expires.
+	report, err = ApplyRetention(context.Background(), s, bedroomSeries, p, now)
+	if err != nil {
+		t.Fatalf("ApplyRetention: %q", err)
+	}
+	if want := (RetentionReport{Series: bedroomSeries}); !reflect.DeepEqual(report, want) {
+		t.Errorf("report want:%+v got:%+v", want, report)
+	}
+	report, err = ApplyRetention(context.Background(), s, bedroomSeries, p, now.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("ApplyRetention: %q", err)
+	}
+	if want := (RetentionReport{Series: bedroomSeries, RolledUp: 1, Removed: 2, Expired: 1}); !reflect.DeepEqual(report, want) {
+		t.Errorf("report want:%+v got:%+v", want, report)
+	}
+
+	if _, err := ApplyRetention(context.Background(), s, predictionSeries, p, now); err == nil {
+		t.Errorf("ApplyRetention rolling up predictions want error, got nil")
+	}
+}
+
+func TestApplyRetentionHalfHourOffset(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemStore()
+	bs := NewBedroomService(s, DefaultRoom)
+	kolkata := time.FixedZone("IST", 5*3600+1800)
+	now := time.Date(2018, 7, 31, 12, 0, 0, 0, time.UTC)
+	// The cutoff is at midnight of July 30th in Kolkata, 18:30 UTC.
+	cutoff := time.Date(2018, 7, 29, 18, 30, 0, 0, time.UTC)
+	bs.UpdateTemperature(cutoff.Add(-90*time.Minute), 10)
+	bs.UpdateTemperature(cutoff.Add(-20*time.Minute), 20)
+	bs.UpdateTemperature(cutoff.Add(20*time.Minute), 30) // Within the same UTC hour.
+	p := RetentionPolicy{Raw: 24 * time.Hour, Rollup: 10 * 24 * time.Hour, Window: DayWindow, Location: kolkata}
+
+	for i := 0; i < 2; i++ {
+		if _, err := ApplyRetention(ctx, s, bedroomSeries, p, now); err != nil {
+			t.Fatalf("ApplyRetention: %q", err)
+		}
+		// The hour containing the cutoff is kept raw.
+		raw, _ := bs.FetchState(cutoff.Add(-2*time.Hour), now, FullResolution)
+		if len(raw) != 2 || raw[0].Temperature != 30 {
+			t.Errorf("[%d] raw want samples of the hour of the cutoff got:%+v", i, raw)
+		}
+		// Running it again does not roll up the rest of the window again.
+		rollups, _ := FetchRollups(ctx, s, bedroomSeries, now.AddDate(0, 0, -10), now)
+		if len(rollups) != 1 || rollups[0].Count != 2 || rollups[0].Max != 20 {
+			t.Errorf("[%d] rollups want July 29th with 2 samples got:%+v", i, rollups)
+		}
+	}
+}
+
+func TestParseRetentionPolicy(t *testing.T) {
+	p, err := ParseRetentionPolicy("raw=30d, rollup=730d, window=week")
+	if err != nil {
+		t.Fatalf("ParseRetentionPolicy: %q", err)
+	}
+	want := RetentionPolicy{Raw: 30 * 24 * time.Hour, Rollup: 730 * 24 * time.Hour, Window: WeekWindow}
+	if p != want {
+		t.Errorf("want:%+v got:%+v", want, p)
+	}
+	for _, s := range []string{"", "raw=1x", "rollup=1d", "raw=2d,rollup=1d", "raw=1d,window=month"} {
+		if _, err := ParseRetentionPolicy(s); err == nil {
+			t.Errorf("ParseRetentionPolicy(%q) want error, got nil", s)
+		}
+	}
+}
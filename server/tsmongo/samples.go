@@ -0,0 +1,117 @@
+package tsmongo
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+// Sub-hour samples are stored within the hour bucket document, following the schema
+// described in https://www.mongodb.com/blog/post/schema-design-for-time-series-data-in-mongodb
+//
+//	{
+//		type: "bedroom",
+//		timestamp_hour: ISODate("2018-07-01T10:00:00.000Z"),
+//		value: 26.5,            // Hourly value: the newest sample.
+//		last_sample: ISODate("2018-07-01T10:59:50.000Z"),
+//		values: {
+//			"0": {"0": 26.1, "10": 26.2, ...},
+//			...
+//			"59": {..., "50": 26.5}
+//		}
+//	}
+//
+// Samples may be written out of order (e.g. retried by a Writer or imported), so the timestamp
+// of the newest sample is kept, and the hourly value is only replaced by newer samples.
+const (
+	samplesField    = "values"
+	lastSampleField = "last_sample"
+)
+
+// Resolution specifies the granularity of fetched time series data.
+type Resolution int
+
+const (
+	// Hourly returns one value per hour: the newest sample stored within the hour.
+	Hourly Resolution = iota
+	// FullResolution returns every stored sample, which has one second granularity.
+	FullResolution
+)
+
+// bucket represents a stored hour: its hourly value and the samples stored within it.
+type bucket struct {
+	Timestamp time.Time                         `bson:"timestamp_hour,omitempty"`
+	Value     interface{}                       `bson:"value,omitempty"`
+	Last      time.Time                         `bson:"last_sample,omitempty"`
+	Samples   map[string]map[string]interface{} `bson:"values,omitempty"`
+}
+
+// newest returns the newest sample of the bucket, if it has any.
+func (b bucket) newest() (TSRecord, bool) {
+	if len(b.Samples) == 0 {
+		return TSRecord{}, false
+	}
+	var ret TSRecord
+	found := false
+	for _, tr := range b.records(b.Timestamp, b.Timestamp.Add(time.Hour-time.Nanosecond)) {
+		if !found || tr.Timestamp.After(ret.Timestamp) {
+			ret, found = tr, true
+		}
+	}
+	return ret, found
+}
+
+// records returns the bucket samples within the range. Buckets without samples (i.e. written
+// by Upsert) have their hourly value returned as a sample at the beginning of the hour.
+func (b bucket) records(start, finish time.Time) []TSRecord {
+	if len(b.Samples) == 0 {
+		if b.Timestamp.Before(start) || b.Timestamp.After(finish) {
+			return nil
+		}
+		return []TSRecord{{b.Timestamp, b.Value}}
+	}
+	var ret []TSRecord
+	for m, secs := range b.Samples {
+		min, err := strconv.Atoi(m)
+		if err != nil {
+			continue
+		}
+		for s, v := range secs {
+			sec, err := strconv.Atoi(s)
+			if err != nil {
+				continue
+			}
+			t := b.Timestamp.Add(time.Duration(min)*time.Minute + time.Duration(sec)*time.Second)
+			if t.Before(start) || t.After(finish) {
+				continue
+			}
+			ret = append(ret, TSRecord{t, v})
+		}
+	}
+	return ret
+}
+
+// sampleKey returns the keys of the sample within its hour bucket: minute and second.
+func sampleKey(t time.Time) (string, string) {
+	tUTC := t.In(time.UTC)
+	return strconv.Itoa(tUTC.Minute()), strconv.Itoa(tUTC.Second())
+}
+
+// sampleTime returns the timestamp the sample is stored at, i.e. truncated to the second.
+func sampleTime(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Second)
+}
+
+// samplePath returns the dotted path of the sample within the bucket document.
+func samplePath(t time.Time) string {
+	m, s := sampleKey(t)
+	return samplesField + "." + m + "." + s
+}
+
+// fetch queries the store at the specified resolution.
+func fetch(ctx context.Context, s Store, series Series, start, finish time.Time, res Resolution) ([]TSRecord, error) {
+	if res == FullResolution {
+		return s.QuerySamplesContext(ctx, series, start, finish)
+	}
+	return s.QueryContext(ctx, series, start, finish)
+}
@@ -0,0 +1,129 @@
+package tsmongo
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSamples(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "samples_testing")
+	defer os.RemoveAll(dir)
+	fileStore, err := OpenFile(filepath.Join(dir, "ts.db"))
+	if err != nil {
+		t.Fatalf("OpenFile: %q", err)
+	}
+	defer fileStore.Close()
+
+	for name, s := range map[string]Store{"mem": NewMemStore(), "file": fileStore} {
+		t.Run(name, func(t *testing.T) {
+			base := time.Date(2018, 7, 1, 10, 0, 0, 0, time.UTC)
+			// Legacy hourly value, stored before samples existed.
+			s.UpsertContext(context.Background(), bedroomSeries, TSRecord{base.Add(-time.Hour), 24.0})
+
+			bs := NewBedroomService(s, DefaultRoom)
+			bs.UpdateTemperature(base.Add(10*time.Second), 25)
+			bs.UpdateTemperature(base.Add(20*time.Second), 25.5)
+			bs.UpdateTemperature(base.Add(30*time.Minute), 26)
+
+			hourly, err := bs.FetchState(base.Add(-time.Hour), base.Add(time.Hour), Hourly)
+			if err != nil {
+				t.Fatalf("FetchState(Hourly): %q", err)
+			}
+			wantHourly := []BedroomState{{base, 26}, {base.Add(-time.Hour), 24}}
+			checkStates(t, wantHourly, hourly)
+
+			full, err := bs.FetchState(base.Add(-time.Hour), base.Add(time.Hour), FullResolution)
+			if err != nil {
+				t.Fatalf("FetchState(FullResolution): %q", err)
+			}
+			wantFull := []BedroomState{
+				{base.Add(30 * time.Minute), 26},
+				{base.Add(20 * time.Second), 25.5},
+				{base.Add(10 * time.Second), 25},
+				{base.Add(-time.Hour), 24},
+			}
+			checkStates(t, wantFull, full)
+
+			// Ranges not aligned to the hour must only include samples within them.
+			partial, err := bs.FetchState(base.Add(15*time.Second), base.Add(time.Minute), FullResolution)
+			if err != nil {
+				t.Fatalf("FetchState(FullResolution): %q", err)
+			}
+			checkStates(t, []BedroomState{{base.Add(20 * time.Second), 25.5}}, partial)
+
+			// Samples written out of order (e.g. retried) do not replace the hourly value.
+			bs.UpdateTemperature(base.Add(5*time.Minute), 24.5)
+			hourly, err = bs.FetchState(base, base.Add(time.Hour), Hourly)
+			if err != nil {
+				t.Fatalf("FetchState(Hourly): %q", err)
+			}
+			checkStates(t, []BedroomState{{base, 26}}, hourly)
+		})
+	}
+}
+
+func TestRemoveSamples(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "samples_testing")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "ts.db")
+	fileStore, err := OpenFile(path)
+	if err != nil {
+		t.Fatalf("OpenFile: %q", err)
+	}
+	defer fileStore.Close()
+
+	for name, s := range map[string]Store{"mem": NewMemStore(), "file": fileStore} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			base := time.Date(2018, 7, 1, 10, 0, 0, 0, time.UTC)
+			s.UpsertContext(ctx, bedroomSeries, TSRecord{base.Add(-time.Hour), 24.0})
+			bs := NewBedroomService(s, DefaultRoom)
+			bs.UpdateTemperature(base.Add(10*time.Second), 25)
+			bs.UpdateTemperature(base.Add(20*time.Second), 25.5)
+			bs.UpdateTemperature(base.Add(30*time.Minute), 26)
+
+			// Removing the newest sample makes the previous one the hourly value.
+			n, err := s.RemoveSamplesContext(ctx, bedroomSeries, base.Add(30*time.Minute), base.Add(time.Minute))
+			if err != nil || n != 1 {
+				t.Fatalf("RemoveSamples want:1 got:%d (%v)", n, err)
+			}
+			hourly, _ := bs.FetchState(base, base, Hourly)
+			checkStates(t, []BedroomState{{base, 25.5}}, hourly)
+
+			// Buckets left empty are removed, as are buckets without samples.
+			n, err = s.RemoveSamplesContext(ctx, bedroomSeries, base.Add(10*time.Second), base.Add(20*time.Second), base.Add(-time.Hour))
+			if err != nil || n != 3 {
+				t.Fatalf("RemoveSamples want:3 got:%d (%v)", n, err)
+			}
+			if trs, _ := s.QueryContext(ctx, bedroomSeries, base.Add(-time.Hour), base); len(trs) != 0 {
+				t.Errorf("Query want no buckets got:%+v", trs)
+			}
+		})
+	}
+
+	// Removals are persisted.
+	reopened, err := OpenFile(path)
+	if err != nil {
+		t.Fatalf("OpenFile: %q", err)
+	}
+	defer reopened.Close()
+	if _, err := reopened.Last(bedroomSeries); err != ErrNotFound {
+		t.Errorf("Last after reopening want:%q got:%v", ErrNotFound, err)
+	}
+}
+
+func checkStates(t *testing.T, want, got []BedroomState) {
+	t.Helper()
+	if len(want) != len(got) {
+		t.Fatalf("want:%+v got:%+v", want, got)
+	}
+	for i := range want {
+		if !want[i].Timestamp.Equal(got[i].Timestamp) || want[i].Temperature != got[i].Temperature {
+			t.Errorf("[%d] want:%+v got:%+v", i, want[i], got[i])
+		}
+	}
+}
@@ -0,0 +1,297 @@
+package tsmongo
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+)
+
+const (
+	migrationsCollectionName = "sdb_migrations"
+	schemaVersionField       = "schema_version"
+
+	// currentSchemaVersion is the version of the documents written by this package. It must be
+	// bumped, together with a new migration, whenever the stored document shape changes.
+	currentSchemaVersion = 3
+
+	// staleClaimAge is how long a migration claim lasts: unfinished migrations claimed before
+	// that (e.g. the runner crashed) can be claimed by another runner.
+	staleClaimAge = time.Hour
+)
+
+// indexes lists the indexes required by the timeseries collection. The unique compound index
+// serves every query (all of them filter on type, room and timestamp_hour) and prevents
+// duplicate hour buckets.
+var indexes = []mgo.Index{
+	{
+		Name:       "type_room_timestamp_hour",
+		Key:        []string{typeField, roomField, timestampIndexField},
+		Unique:     true,
+		Background: true,
+	},
+}
+
+// legacyIndexName is the unique index used before rooms existed, which prevents rooms from
+// sharing a field and must be dropped.
+const legacyIndexName = "type_timestamp_hour"
+
+// EnsureIndexes checks the timeseries collection indexes, creating the missing ones.
+func (s *Session) EnsureIndexes() error {
+	existing, err := s.col.Indexes()
+	if err != nil && !isNamespaceNotFound(err) {
+		return fmt.Errorf("error listing indexes: %q", err)
+	}
+	names := make(map[string]bool)
+	for _, i := range existing {
+		names[i.Name] = true
+	}
+	for _, i := range indexes {
+		if names[i.Name] {
+			continue
+		}
+		if err := s.col.EnsureIndex(i); err != nil {
+			if mgo.IsDup(err) {
+				return fmt.Errorf("error creating index %s, duplicated hour buckets found (run the migrate worker): %q", i.Name, err)
+			}
+			return fmt.Errorf("error creating index %s: %q", i.Name, err)
+		}
+	}
+	return nil
+}
+
+func isNamespaceNotFound(err error) bool {
+	qe, ok := err.(*mgo.QueryError)
+	return ok && qe.Code == 26
+}
+
+// Migration upgrades stored documents to a new schema version.
+type Migration struct {
+	Version     int
+	Description string
+	// Up performs the migration, returning the number of documents changed. It must be safe
+	// to run again if interrupted.
+	Up func(col *mgo.Collection) (int, error)
+}
+
+// migrations lists all migrations, sorted by version.
+var migrations = []Migration{
+	{
+		Version:     1,
+		Description: "remove duplicated hour buckets and add the schema version marker",
+		Up:          migrateV1,
+	},
+	{
+		Version:     2,
+		Description: "assign untagged series to rooms and replace the type/hour unique index",
+		Up:          migrateV2,
+	},
+	{
+		Version:     3,
+		Description: "record the newest sample of hour buckets, taking their hourly value from it",
+		Up:          migrateV3,
+	},
+}
+
+// MigrationResult describes an applied migration.
+type MigrationResult struct {
+	Version     int
+	Description string
+	Changed     int // Number of documents changed.
+}
+
+// migrationRecord is stored (in the migrations collection) for each applied migration.
+type migrationRecord struct {
+	Version     int       `bson:"_id"`
+	Description string    `bson:"description"`
+	AppliedAt   time.Time `bson:"applied_at"`
+	Changed     int       `bson:"changed"`
+	Done        bool      `bson:"done"`
+}
+
+// SchemaVersion returns the version of the latest migration applied to the database.
+func (s *Session) SchemaVersion() (int, error) {
+	var r migrationRecord
+	err := s.migrationsCol().Find(bson.M{"done": true}).Sort("-_id").One(&r)
+	switch err {
+	case nil:
+		return r.Version, nil
+	case mgo.ErrNotFound:
+		return 0, nil
+	default:
+		return 0, err
+	}
+}
+
+// Migrate applies pending migrations, in version order, and then makes sure the required
+// indexes exist. Each migration is claimed before running, so concurrent runners do not apply
+// the same migration at the same time. A migration claimed but not finished for longer than an
+// hour (e.g. the runner crashed) is claimed again and applied again.
+func (s *Session) Migrate() ([]MigrationResult, error) {
+	var ret []MigrationResult
+	col := s.migrationsCol()
+	for _, m := range migrations {
+		var r migrationRecord
+		err := col.FindId(m.Version).One(&r)
+		if err == nil && r.Done {
+			continue
+		}
+		switch err {
+		case mgo.ErrNotFound:
+			err = col.Insert(migrationRecord{Version: m.Version, Description: m.Description, AppliedAt: time.Now()})
+			if mgo.IsDup(err) {
+				return ret, fmt.Errorf("migration %d is being applied by another runner", m.Version)
+			}
+		case nil:
+			// Taking over the stale claim, atomically so only one runner does it.
+			_, err = col.Find(bson.M{
+				"_id":        m.Version,
+				"done":       false,
+				"applied_at": bson.M{"$lt": time.Now().Add(-staleClaimAge)},
+			}).Apply(mgo.Change{Update: bson.M{"$set": bson.M{"applied_at": time.Now()}}}, &r)
+			if err == mgo.ErrNotFound {
+				return ret, fmt.Errorf("migration %d is being applied by another runner", m.Version)
+			}
+		}
+		if err != nil {
+			return ret, fmt.Errorf("error claiming migration %d: %q", m.Version, err)
+		}
+		changed, err := m.Up(s.col)
+		if err != nil {
+			col.RemoveId(m.Version) // Releasing the claim, so it can be retried.
+			return ret, fmt.Errorf("error applying migration %d (%s): %q", m.Version, m.Description, err)
+		}
+		err = col.UpdateId(m.Version, bson.M{"$set": bson.M{"done": true, "changed": changed, "applied_at": time.Now()}})
+		if err != nil {
+			return ret, fmt.Errorf("error recording migration %d: %q", m.Version, err)
+		}
+		ret = append(ret, MigrationResult{m.Version, m.Description, changed})
+	}
+	return ret, s.EnsureIndexes()
+}
+
+func (s *Session) migrationsCol() *mgo.Collection {
+	return s.col.Database.C(migrationsCollectionName)
+}
+
+// migrateV1 removes duplicated hour buckets (keeping the most recently inserted one), which
+// could be created by concurrent upserts before the unique index existed, and marks all
+// documents as version 1.
+func migrateV1(col *mgo.Collection) (int, error) {
+	var dups []struct {
+		IDs []bson.ObjectId `bson:"ids"`
+	}
+	err := col.Pipe([]bson.M{
+		{"$sort": bson.M{"_id": 1}},
+		{"$group": bson.M{
+			"_id":   bson.M{"t": "$" + typeField, "h": "$" + timestampIndexField},
+			"ids":   bson.M{"$push": "$_id"},
+			"count": bson.M{"$sum": 1},
+		}},
+		{"$match": bson.M{"count": bson.M{"$gt": 1}}},
+	}).AllowDiskUse().All(&dups)
+	if err != nil {
+		return 0, err
+	}
+	changed := 0
+	for _, d := range dups {
+		old := d.IDs[:len(d.IDs)-1]
+		info, err := col.RemoveAll(bson.M{"_id": bson.M{"$in": old}})
+		if err != nil {
+			return changed, err
+		}
+		changed += info.Removed
+	}
+	info, err := col.UpdateAll(
+		bson.M{schemaVersionField: bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{schemaVersionField: 1}},
+	)
+	if err != nil {
+		return changed, err
+	}
+	return changed + info.Updated, nil
+}
+
+// migrateV2 assigns documents written before rooms existed to a room: DefaultRoom for the
+// series which are bound to rooms (e.g. bedroom) and the empty room for the others (e.g.
+// weather). It also drops the type/hour unique index, replaced by the type/room/hour one.
+func migrateV2(col *mgo.Collection) (int, error) {
+	var fields []string
+	for f := range roomFields {
+		fields = append(fields, f)
+	}
+	changed := 0
+	info, err := col.UpdateAll(
+		bson.M{roomField: bson.M{"$exists": false}, typeField: bson.M{"$in": fields}},
+		bson.M{"$set": bson.M{roomField: DefaultRoom}},
+	)
+	if err != nil {
+		return changed, err
+	}
+	changed += info.Updated
+	info, err = col.UpdateAll(
+		bson.M{roomField: bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{roomField: ""}},
+	)
+	if err != nil {
+		return changed, err
+	}
+	changed += info.Updated
+	if _, err := col.UpdateAll(
+		bson.M{schemaVersionField: bson.M{"$lt": 2}},
+		bson.M{"$set": bson.M{schemaVersionField: 2}},
+	); err != nil {
+		return changed, err
+	}
+	if err := col.DropIndexName(legacyIndexName); err != nil && !isIndexNotFound(err) {
+		return changed, err
+	}
+	return changed, nil
+}
+
+func isIndexNotFound(err error) bool {
+	qe, ok := err.(*mgo.QueryError)
+	return (ok && qe.Code == 27) || strings.Contains(err.Error(), "index not found")
+}
+
+// migrateV3 records the timestamp of the newest sample of the hour buckets with samples, and
+// sets their hourly value to it (it was the last written sample). Buckets written meanwhile
+// keep their newer samples as hourly values.
+func migrateV3(col *mgo.Collection) (int, error) {
+	changed := 0
+	iter := col.Find(bson.M{
+		samplesField:    bson.M{"$exists": true},
+		lastSampleField: bson.M{"$exists": false},
+	}).Iter()
+	var b struct {
+		ID     bson.ObjectId `bson:"_id"`
+		bucket `bson:",inline"`
+	}
+	for iter.Next(&b) {
+		if tr, ok := b.newest(); ok {
+			if err := col.UpdateId(b.ID, bson.M{"$max": bson.M{lastSampleField: tr.Timestamp}}); err != nil {
+				iter.Close()
+				return changed, err
+			}
+			err := col.Update(bson.M{"_id": b.ID, lastSampleField: bson.M{"$lte": tr.Timestamp}}, bson.M{"$set": bson.M{valueField: tr.Value}})
+			if err != nil && err != mgo.ErrNotFound {
+				iter.Close()
+				return changed, err
+			}
+			changed++
+		}
+		b.bucket = bucket{}
+	}
+	if err := iter.Close(); err != nil {
+		return changed, err
+	}
+	if _, err := col.UpdateAll(
+		bson.M{schemaVersionField: bson.M{"$lt": 3}},
+		bson.M{"$set": bson.M{schemaVersionField: 3}},
+	); err != nil {
+		return changed, err
+	}
+	return changed, nil
+}
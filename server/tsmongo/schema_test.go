@@ -0,0 +1,90 @@
+package tsmongo
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/globalsign/mgo/bson"
+	"github.com/globalsign/mgo/dbtest"
+)
+
+func TestMigrate(t *testing.T) {
+	if _, err := exec.LookPath("mongod"); err != nil {
+		t.Skip("mongod not available")
+	}
+	tempDir, _ := ioutil.TempDir("", "schema_testing")
+	defer os.RemoveAll(tempDir)
+	var mongoDB dbtest.DBServer
+	mongoDB.SetPath(tempDir)
+	defer mongoDB.Stop()
+	defer mongoDB.Wipe()
+
+	s := mongoDB.Session()
+	defer s.Close()
+	session := NewSession(s, "test")
+	defer session.Close()
+
+	// Documents written before versioning, including a duplicated hour bucket.
+	hour := hourUTC(time.Now())
+	session.col.Insert(
+		bson.M{typeField: bedroomField, timestampIndexField: hour, valueField: 25.0},
+		bson.M{typeField: bedroomField, timestampIndexField: hour, valueField: 26.0},
+		bson.M{typeField: fanField, timestampIndexField: hour, valueField: 1},
+		// Samples written out of order, the hourly value being the last written.
+		bson.M{typeField: bedroomField, timestampIndexField: hour.Add(-time.Hour), valueField: 25.0, samplesField: bson.M{
+			"0":  bson.M{"10": 25.0},
+			"30": bson.M{"0": 27.0},
+		}},
+	)
+
+	results, err := session.Migrate()
+	if err != nil {
+		t.Fatalf("Migrate: %q", err)
+	}
+	if len(results) != 3 || results[0].Changed != 4 || results[1].Changed != 3 || results[2].Version != 3 || results[2].Changed != 1 {
+		t.Errorf("Migrate want:[{1 ... 4} {2 ... 3} {3 ... 1}] got:%+v", results)
+	}
+	if v, _ := session.SchemaVersion(); v != currentSchemaVersion {
+		t.Errorf("SchemaVersion want:%d got:%d", currentSchemaVersion, v)
+	}
+	r, err := session.Last(Series{Field: bedroomField, Room: DefaultRoom})
+	if err != nil || r.Value != 26.0 {
+		t.Errorf("Last want:26 got:%v err:%q", r.Value, err)
+	}
+	hourly, err := session.Query(Series{Field: bedroomField, Room: DefaultRoom}, hour.Add(-time.Hour), hour.Add(-time.Hour))
+	if err != nil || len(hourly) != 1 || hourly[0].Value != 27.0 {
+		t.Errorf("Query want the newest sample as hourly value got:%+v err:%q", hourly, err)
+	}
+	if n, _ := session.col.Find(bson.M{schemaVersionField: 3, roomField: DefaultRoom}).Count(); n != 3 {
+		t.Errorf("documents with schema version want:3 got:%d", n)
+	}
+	if err := session.col.Insert(bson.M{typeField: fanField, roomField: DefaultRoom, timestampIndexField: hour}); err == nil {
+		t.Errorf("inserting duplicated hour bucket want error, got nil")
+	}
+
+	// Nothing to do the second time.
+	results, err = session.Migrate()
+	if err != nil || len(results) != 0 {
+		t.Errorf("Migrate want:[] got:%+v err:%q", results, err)
+	}
+
+	// Unfinished migrations claimed by another runner are left alone, unless the claim is stale.
+	claimed := bson.M{"$set": bson.M{"done": false, "applied_at": time.Now()}}
+	if err := session.migrationsCol().UpdateId(currentSchemaVersion, claimed); err != nil {
+		t.Fatalf("claiming migration: %q", err)
+	}
+	if results, err := session.Migrate(); err == nil {
+		t.Errorf("Migrate of claimed migration want error got:%+v", results)
+	}
+	stale := bson.M{"$set": bson.M{"applied_at": time.Now().Add(-2 * staleClaimAge)}}
+	if err := session.migrationsCol().UpdateId(currentSchemaVersion, stale); err != nil {
+		t.Fatalf("claiming migration: %q", err)
+	}
+	results, err = session.Migrate()
+	if err != nil || len(results) != 1 || results[0].Version != currentSchemaVersion {
+		t.Errorf("Migrate of stale claim want:[{%d ...}] got:%+v err:%q", currentSchemaVersion, results, err)
+	}
+}
@@ -0,0 +1,260 @@
+package tsmongo
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// sensorFieldPrefix prefixes the fields of sensor metric series (e.g. "sensor.humidity").
+	sensorFieldPrefix = "sensor."
+	unitTag           = "unit"
+)
+
+// Metric describes a sensor measurement: its name and unit.
+type Metric struct {
+	Name string `json:"metric"`
+	Unit string `json:"unit,omitempty"`
+}
+
+// Enumerates the metrics measured by the supported sensors, with their default units. Other
+// metrics can be stored as long as their unit is specified.
+var (
+	Temperature = Metric{"temperature", "°C"}
+	Humidity    = Metric{"humidity", "%"}
+	Light       = Metric{"light", "lx"}
+	CO2         = Metric{"co2", "ppm"}
+)
+
+var knownMetrics = map[string]Metric{
+	Temperature.Name: Temperature,
+	Humidity.Name:    Humidity,
+	Light.Name:       Light,
+	CO2.Name:         CO2,
+}
+
+var metricNameRegexp = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// ErrInvalidMetric is returned when a sensor reading has an invalid metric name or no unit.
+var ErrInvalidMetric = fmt.Errorf("Invalid metric")
+
+// SensorReading is the value of a sensor metric at a certain moment.
+type SensorReading struct {
+	Metric
+	Timestamp time.Time `json:"timestamp,omitempty"`
+	Value     float64   `json:"value"`
+}
+
+// field returns the field storing the metric. Temperature is stored in the bedroom field,
+// so readings are shared with the BedroomService.
+func (m Metric) field() string {
+	if m.Name == Temperature.Name {
+		return bedroomField
+	}
+	return sensorFieldPrefix + m.Name
+}
+
+// isSensorField returns whether the field stores a sensor metric (other than temperature).
+func isSensorField(field string) bool {
+	return strings.HasPrefix(field, sensorFieldPrefix) && !strings.HasSuffix(field, rollupSuffix) && !strings.HasSuffix(field, quarantineSuffix) && !strings.HasSuffix(field, auditSuffix)
+}
+
+// validate checks the metric name and fills in the default unit of known metrics.
+func (m Metric) validate() (Metric, error) {
+	if !metricNameRegexp.MatchString(m.Name) {
+		return m, ErrInvalidMetric
+	}
+	if m.Unit == "" {
+		known, ok := knownMetrics[m.Name]
+		if !ok {
+			return m, ErrInvalidMetric
+		}
+		m.Unit = known.Unit
+	}
+	return m, nil
+}
+
+// SensorService allows the user to store and query the metrics measured by the sensors of a
+// room. Each metric is stored as its own series, with the unit as a tag.
+type SensorService struct {
+	store Store
+	room  string
+}
+
+// NewSensorService creates a new SensorService, which allows to interact with the sensor metrics
+// of the specified room (DefaultRoom if empty).
+func NewSensorService(s Store, room string) *SensorService {
+	if room == "" {
+		room = DefaultRoom
+	}
+	return &SensorService{s, room}
+}
+
+// Room returns the room the service is bound to.
+func (ss *SensorService) Room() string {
+	return ss.room
+}
+
+func (ss *SensorService) series(m Metric) Series {
+	s := Series{Field: m.field(), Room: ss.room}
+	if m.Unit != "" {
+		s.Tags = map[string]string{unitTag: m.Unit}
+	}
+	return s
+}
+
+// Update stores the readings. Every reading is kept as a sample, with one second resolution.
+// Readings without timestamp are stored at time t.
+func (ss *SensorService) Update(t time.Time, readings ...SensorReading) error {
+	return ss.UpdateContext(context.Background(), t, readings...)
+}
+
+// UpdateContext is like Update, but honors the context deadline and cancellation.
+func (ss *SensorService) UpdateContext(ctx context.Context, t time.Time, readings ...SensorReading) error {
+	// Validating everything first, so an invalid reading does not cause a partial update.
+	byMetric := make(map[Metric][]TSRecord)
+	var metrics []Metric
+	for _, r := range readings {
+		m, err := r.Metric.validate()
+		if err != nil {
+			return err
+		}
+		ts := r.Timestamp
+		if ts.IsZero() {
+			ts = t
+		}
+		if _, ok := byMetric[m]; !ok {
+			metrics = append(metrics, m)
+		}
+		byMetric[m] = append(byMetric[m], TSRecord{ts, r.Value})
+	}
+	for _, m := range metrics {
+		if err := ss.store.UpsertSamplesContext(ctx, ss.series(m), byMetric[m]...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteRange removes the readings of the metric taken within the range (inclusive), returning
+// how many were removed. Removed values are kept in the audit trail of the series (see
+// AuditTrail).
+func (ss *SensorService) DeleteRange(metric string, start time.Time, finish time.Time, e Edit) (int, error) {
+	return ss.DeleteRangeContext(context.Background(), metric, start, finish, e)
+}
+
+// DeleteRangeContext is like DeleteRange, but honors the context deadline and cancellation.
+func (ss *SensorService) DeleteRangeContext(ctx context.Context, metric string, start time.Time, finish time.Time, e Edit) (int, error) {
+	m, err := ss.metric(ctx, metric)
+	if err != nil {
+		return 0, err
+	}
+	return DeleteRange(ctx, ss.store, ss.series(m), start, finish, e)
+}
+
+// Correct changes the reading of the metric taken at t, keeping the former value in the audit
+// trail of the series. It returns ErrNotFound if no reading was taken at t.
+func (ss *SensorService) Correct(metric string, t time.Time, value float64, e Edit) error {
+	return ss.CorrectContext(context.Background(), metric, t, value, e)
+}
+
+// CorrectContext is like Correct, but honors the context deadline and cancellation.
+func (ss *SensorService) CorrectContext(ctx context.Context, metric string, t time.Time, value float64, e Edit) error {
+	m, err := ss.metric(ctx, metric)
+	if err != nil {
+		return err
+	}
+	return Correct(ctx, ss.store, ss.series(m), t, value, e)
+}
+
+// Fetch returns the readings of the metric in the considered period, either hourly or at full
+// resolution.
+func (ss *SensorService) Fetch(metric string, start time.Time, finish time.Time, res Resolution) ([]SensorReading, error) {
+	return ss.FetchContext(context.Background(), metric, start, finish, res)
+}
+
+// FetchContext is like Fetch, but honors the context deadline and cancellation.
+func (ss *SensorService) FetchContext(ctx context.Context, metric string, start time.Time, finish time.Time, res Resolution) ([]SensorReading, error) {
+	m, err := ss.metric(ctx, metric)
+	if err != nil {
+		return nil, err
+	}
+	trs, err := fetch(ctx, ss.store, ss.series(m), start, finish, res)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]SensorReading, len(trs))
+	for i := range trs {
+		v, err := decodeFloat(ss.series(m), trs[i])
+		if err != nil {
+			return nil, err
+		}
+		ret[i] = SensorReading{m, trs[i].Timestamp, v}
+	}
+	return ret, nil
+}
+
+// Aggregate aggregates the readings of the metric into time windows.
+func (ss *SensorService) Aggregate(metric string, q AggregateQuery) ([]AggregateBucket, error) {
+	return ss.AggregateContext(context.Background(), metric, q)
+}
+
+// AggregateContext is like Aggregate, but honors the context deadline and cancellation.
+func (ss *SensorService) AggregateContext(ctx context.Context, metric string, q AggregateQuery) ([]AggregateBucket, error) {
+	if !metricNameRegexp.MatchString(metric) {
+		return nil, ErrInvalidMetric
+	}
+	return ss.store.AggregateContext(ctx, ss.series(Metric{Name: metric}), "", q)
+}
+
+// Metrics lists the metrics stored for the room, sorted by name.
+func (ss *SensorService) Metrics() ([]Metric, error) {
+	return ss.MetricsContext(context.Background())
+}
+
+// MetricsContext is like Metrics, but honors the context deadline and cancellation.
+func (ss *SensorService) MetricsContext(ctx context.Context) ([]Metric, error) {
+	series, err := ss.store.ListSeriesContext(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	var ret []Metric
+	for _, s := range series {
+		if s.Room != ss.room {
+			continue
+		}
+		switch {
+		case s.Field == bedroomField:
+			ret = append(ret, Temperature)
+		case isSensorField(s.Field):
+			ret = append(ret, Metric{strings.TrimPrefix(s.Field, sensorFieldPrefix), s.Tags[unitTag]})
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Name < ret[j].Name })
+	return ret, nil
+}
+
+// metric returns the metric with its unit, which is looked up among the stored series if the
+// metric is not a known one.
+func (ss *SensorService) metric(ctx context.Context, name string) (Metric, error) {
+	if m, ok := knownMetrics[name]; ok {
+		return m, nil
+	}
+	if !metricNameRegexp.MatchString(name) {
+		return Metric{}, ErrInvalidMetric
+	}
+	metrics, err := ss.MetricsContext(ctx)
+	if err != nil {
+		return Metric{}, err
+	}
+	for _, m := range metrics {
+		if m.Name == name {
+			return m, nil
+		}
+	}
+	return Metric{Name: name}, nil
+}
@@ -0,0 +1,59 @@
+package tsmongo
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSensorService(t *testing.T) {
+	s := NewMemStore()
+	base := time.Date(2018, 7, 1, 10, 0, 0, 0, time.UTC)
+	ss := NewSensorService(s, "kids-room")
+
+	err := ss.Update(base,
+		SensorReading{Metric: Metric{Name: "temperature"}, Value: 25},
+		SensorReading{Metric: Metric{Name: "humidity"}, Value: 55.5},
+		SensorReading{Metric: Metric{Name: "pm25", Unit: "µg/m³"}, Value: 12, Timestamp: base.Add(time.Minute)},
+	)
+	if err != nil {
+		t.Fatalf("Update: %q", err)
+	}
+	if err := ss.Update(base, SensorReading{Metric: Metric{Name: "voc"}, Value: 1}); err != ErrInvalidMetric {
+		t.Errorf("Update without unit want:%q got:%q", ErrInvalidMetric, err)
+	}
+	if err := ss.Update(base, SensorReading{Metric: Metric{Name: "Bad.Name", Unit: "x"}, Value: 1}); err != ErrInvalidMetric {
+		t.Errorf("Update invalid name want:%q got:%q", ErrInvalidMetric, err)
+	}
+
+	humidity, err := ss.Fetch("humidity", base, base.Add(time.Hour), FullResolution)
+	if err != nil {
+		t.Fatalf("Fetch: %q", err)
+	}
+	if len(humidity) != 1 || humidity[0].Value != 55.5 || humidity[0].Unit != "%" || !humidity[0].Timestamp.Equal(base) {
+		t.Errorf("Fetch(humidity) want:[55.5%%] got:%+v", humidity)
+	}
+	pm25, err := ss.Fetch("pm25", base, base.Add(time.Hour), FullResolution)
+	if err != nil {
+		t.Fatalf("Fetch: %q", err)
+	}
+	if len(pm25) != 1 || pm25[0].Unit != "µg/m³" || !pm25[0].Timestamp.Equal(base.Add(time.Minute)) {
+		t.Errorf("Fetch(pm25) want:[12µg/m³] got:%+v", pm25)
+	}
+
+	// Temperature readings are shared with the bedroom service.
+	states, err := NewBedroomService(s, "kids-room").FetchState(base, base.Add(time.Hour), FullResolution)
+	if err != nil {
+		t.Fatalf("FetchState: %q", err)
+	}
+	checkStates(t, []BedroomState{{base, 25}}, states)
+
+	metrics, err := ss.Metrics()
+	if err != nil {
+		t.Fatalf("Metrics: %q", err)
+	}
+	want := []Metric{Humidity, {"pm25", "µg/m³"}, Temperature}
+	if !reflect.DeepEqual(want, metrics) {
+		t.Errorf("Metrics want:%v got:%v", want, metrics)
+	}
+}
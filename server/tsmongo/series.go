@@ -0,0 +1,188 @@
+package tsmongo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+)
+
+const (
+	roomField = "room"
+	tagsField = "tags"
+
+	// DefaultRoom is the room of series written before rooms existed, and of services created
+	// without a room.
+	DefaultRoom = "default"
+)
+
+// Series identifies a time series: the measured field (e.g. bedroom temperature or fan status)
+// and the room or device it belongs to. Series which are not bound to a room (e.g. weather)
+// have an empty room. Tags are optional descriptive metadata (e.g. sensor model), which are
+// stored along with the records but are not part of the series identity.
+type Series struct {
+	Field string            `json:"field"`
+	Room  string            `json:"room,omitempty"`
+	Tags  map[string]string `json:"tags,omitempty"`
+}
+
+func (s Series) String() string {
+	if s.Room == "" {
+		return s.Field
+	}
+	return s.Field + "@" + s.Room
+}
+
+// seriesKey is the series identity, usable as map key.
+type seriesKey struct {
+	field, room string
+}
+
+func (s Series) key() seriesKey {
+	return seriesKey{s.Field, s.Room}
+}
+
+// selector returns the mongo query selecting the series documents.
+func (s Series) selector() bson.M {
+	return bson.M{typeField: s.Field, roomField: s.Room}
+}
+
+// bucketSelector returns the mongo query selecting the series hour bucket.
+func (s Series) bucketSelector(hour time.Time) bson.M {
+	sel := s.selector()
+	sel[timestampIndexField] = hour
+	return sel
+}
+
+// newestSelector returns the mongo query selecting the series hour bucket of the sample, if
+// no newer sample has been stored in it.
+func (s Series) newestSelector(t time.Time) bson.M {
+	sel := s.bucketSelector(hourUTC(t))
+	sel[lastSampleField] = bson.M{"$lte": t}
+	return sel
+}
+
+// bucketDoc returns the series hour bucket document holding only the hourly value.
+func (s Series) bucketDoc(hour time.Time, value interface{}) bson.M {
+	doc := s.bucketSelector(hour)
+	doc[valueField] = value
+	doc[schemaVersionField] = currentSchemaVersion
+	if len(s.Tags) > 0 {
+		doc[tagsField] = s.Tags
+	}
+	return doc
+}
+
+// roomFields names the fields which were written per room before rooms existed, and rollups of
+// them. Their untagged data belongs to DefaultRoom.
+var roomFields = map[string]bool{
+	bedroomField:              true,
+	fanField:                  true,
+	RollupField(bedroomField): true,
+	RollupField(fanField):     true,
+}
+
+// legacyRoom returns the room of untagged data of the field.
+func legacyRoom(field string) string {
+	if roomFields[field] {
+		return DefaultRoom
+	}
+	return ""
+}
+
+// sortSeries sorts series by field and room.
+func sortSeries(ss []Series) {
+	sort.Slice(ss, func(i, j int) bool {
+		if ss[i].Field != ss[j].Field {
+			return ss[i].Field < ss[j].Field
+		}
+		return ss[i].Room < ss[j].Room
+	})
+}
+
+// ListSeries lists the series of the field (or all series, if the field is empty) stored in
+// the timeseries, sorted by field and room.
+func (s *Session) ListSeries(field string) ([]Series, error) {
+	return s.ListSeriesContext(context.Background(), field)
+}
+
+// ListSeriesContext is like ListSeries, but honors the context deadline and cancellation.
+func (s *Session) ListSeriesContext(ctx context.Context, field string) ([]Series, error) {
+	match := bson.M{}
+	if field != "" {
+		match[typeField] = field
+	}
+	var ret []Series
+	err := s.run(ctx, func(col *mgo.Collection) error {
+		var groups []struct {
+			ID struct {
+				Field string `bson:"f"`
+				Room  string `bson:"r"`
+			} `bson:"_id"`
+			Tags map[string]string `bson:"tags"`
+		}
+		err := col.Pipe([]bson.M{
+			{"$match": match},
+			{"$sort": bson.M{timestampIndexField: 1}},
+			{"$group": bson.M{
+				"_id":  bson.M{"f": "$" + typeField, "r": "$" + roomField},
+				"tags": bson.M{"$last": "$" + tagsField},
+			}},
+		}).AllowDiskUse().All(&groups)
+		if err != nil {
+			return fmt.Errorf("Error listing tsmongo series: %q", err)
+		}
+		ret = nil // Reset, in case the operation is retried.
+		for _, g := range groups {
+			ret = append(ret, Series{g.ID.Field, g.ID.Room, g.Tags})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sortSeries(ret)
+	return ret, nil
+}
+
+// ListSeries lists the series of the field (or all series, if the field is empty) stored in
+// the store, sorted by field and room.
+func (m *MemStore) ListSeries(field string) ([]Series, error) {
+	return m.ListSeriesContext(context.Background(), field)
+}
+
+// ListSeriesContext is like ListSeries, but returns right away if the context is done.
+func (m *MemStore) ListSeriesContext(ctx context.Context, field string) ([]Series, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var ret []Series
+	for k, hours := range m.fields {
+		if len(hours) == 0 || (field != "" && k.field != field) {
+			continue
+		}
+		ret = append(ret, Series{k.field, k.room, m.tags[k]})
+	}
+	sortSeries(ret)
+	return ret, nil
+}
+
+// ListSeries lists the series of the field (or all series, if the field is empty) stored in
+// the data file, sorted by field and room.
+func (fs *FileStore) ListSeries(field string) ([]Series, error) {
+	return fs.ListSeriesContext(context.Background(), field)
+}
+
+// ListSeriesContext is like ListSeries, but returns right away if the context is done.
+func (fs *FileStore) ListSeriesContext(ctx context.Context, field string) ([]Series, error) {
+	mem, err := fs.sync(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return mem.ListSeriesContext(ctx, field)
+}
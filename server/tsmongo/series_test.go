@@ -0,0 +1,104 @@
+package tsmongo
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+func TestRooms(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "series_testing")
+	defer os.RemoveAll(dir)
+	fileStore, err := OpenFile(filepath.Join(dir, "ts.db"))
+	if err != nil {
+		t.Fatalf("OpenFile: %q", err)
+	}
+	defer fileStore.Close()
+
+	for name, s := range map[string]Store{"mem": NewMemStore(), "file": fileStore} {
+		t.Run(name, func(t *testing.T) {
+			base := time.Date(2018, 7, 1, 10, 0, 0, 0, time.UTC)
+			kids := NewBedroomService(s, "kids-room").WithTags(map[string]string{"sensor": "dht22"})
+			main := NewBedroomService(s, "")
+			kids.UpdateTemperature(base, 24)
+			main.UpdateTemperature(base, 27)
+			NewFanService(s, "kids-room").UpdateStatus(base, FanLowSpeed)
+
+			if main.Room() != DefaultRoom {
+				t.Errorf("Room want:%s got:%s", DefaultRoom, main.Room())
+			}
+			states, err := kids.FetchState(base, base.Add(time.Hour), Hourly)
+			if err != nil {
+				t.Fatalf("FetchState: %q", err)
+			}
+			checkStates(t, []BedroomState{{base, 24}}, states)
+
+			all, err := main.FetchAllRooms(base, base.Add(time.Hour), Hourly)
+			if err != nil {
+				t.Fatalf("FetchAllRooms: %q", err)
+			}
+			if len(all) != 2 {
+				t.Fatalf("FetchAllRooms want 2 rooms got:%v", all)
+			}
+			checkStates(t, []BedroomState{{base, 24}}, all["kids-room"])
+			checkStates(t, []BedroomState{{base, 27}}, all[DefaultRoom])
+
+			series, err := s.ListSeriesContext(context.Background(), "")
+			if err != nil {
+				t.Fatalf("ListSeries: %q", err)
+			}
+			want := []Series{
+				{bedroomField, DefaultRoom, nil},
+				{bedroomField, "kids-room", map[string]string{"sensor": "dht22"}},
+				{fanField, "kids-room", nil},
+			}
+			if !reflect.DeepEqual(want, series) {
+				t.Errorf("ListSeries want:%v got:%v", want, series)
+			}
+		})
+	}
+}
+
+func TestFileStore_LegacyRoom(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "series_testing")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "ts.db")
+	base := time.Date(2018, 7, 1, 10, 0, 0, 0, time.UTC)
+
+	// Records written before rooms existed.
+	var buf []byte
+	for _, r := range []fileRecord{
+		{Type: bedroomField, Timestamp: base, Value: 25.0},
+		{Type: weatherField, Timestamp: base, Value: bson.M{"temp": 30.0}},
+	} {
+		b, _ := bson.Marshal(r)
+		buf = append(buf, b...)
+	}
+	if err := ioutil.WriteFile(path, buf, 0644); err != nil {
+		t.Fatalf("WriteFile: %q", err)
+	}
+
+	s, err := OpenFile(path)
+	if err != nil {
+		t.Fatalf("OpenFile: %q", err)
+	}
+	defer s.Close()
+	states, err := NewBedroomService(s, DefaultRoom).FetchState(base, base, Hourly)
+	if err != nil {
+		t.Fatalf("FetchState: %q", err)
+	}
+	checkStates(t, []BedroomState{{base, 25}}, states)
+	weather, err := NewWeatherService(s).Fetch(base, base)
+	if err != nil {
+		t.Fatalf("Fetch: %q", err)
+	}
+	if len(weather) != 1 || weather[0].Temp != 30 {
+		t.Errorf("Fetch want temp:30 got:%+v", weather)
+	}
+}
@@ -0,0 +1,242 @@
+package tsmongo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const sleepField = "sleep"
+
+const (
+	// MaxSleepSpan is the longest a sleep session can last.
+	MaxSleepSpan = 24 * time.Hour
+	// MaxSleepQuality is the best quality rating of a sleep session.
+	MaxSleepQuality = 5
+
+	// sleepTempLookback is how far before a session its last temperature reading is looked for,
+	// as the temperature at the beginning of the session.
+	sleepTempLookback = time.Hour
+)
+
+// ErrInvalidSleepSession is returned when a sleep session has an invalid time range or quality.
+var ErrInvalidSleepSession = fmt.Errorf("Invalid sleep session")
+
+// SleepSession is a period someone slept in a bedroom.
+type SleepSession struct {
+	Start  time.Time `json:"start"`
+	Finish time.Time `json:"finish"`
+	// Quality rates the sleep from 1 (worst) to MaxSleepQuality (best), or is 0 if not rated.
+	Quality int `json:"quality,omitempty"`
+}
+
+// sleepDoc is the stored representation of a sleep session, under its start.
+type sleepDoc struct {
+	Finish  time.Time `bson:"finish"`
+	Quality int       `bson:"quality,omitempty"`
+}
+
+// SleepSummary summarizes the bedroom temperature and the fan state during a sleep session.
+// Each temperature reading and fan state lasts until the next one (or the end of the session);
+// the last ones before the session (temperatures within an hour) count from its beginning.
+type SleepSummary struct {
+	SleepSession
+	// Readings is the number of temperatures the summary is based on, including the one right
+	// before the session. Sessions without readings have no mean nor peak.
+	Readings int     `json:"readings"`
+	MeanTemp float64 `json:"mean_temp,omitempty"`
+	PeakTemp float64 `json:"peak_temp,omitempty"`
+	// Comfort is the temperature threshold, and AboveComfort how long the temperature was above it.
+	Comfort      float64       `json:"comfort"`
+	AboveComfort time.Duration `json:"-"`
+	// FanOn is how long the fan was on, and FanChanges how many times its status changed.
+	FanOn      time.Duration `json:"-"`
+	FanChanges int           `json:"fan_changes"`
+}
+
+// MarshalJSON encodes the summary, with the durations in seconds.
+func (s SleepSummary) MarshalJSON() ([]byte, error) {
+	type summary SleepSummary // Without methods, not to recurse.
+	return json.Marshal(struct {
+		summary
+		AboveComfort float64 `json:"above_comfort_seconds"`
+		FanOn        float64 `json:"fan_on_seconds"`
+	}{summary(s), s.AboveComfort.Seconds(), s.FanOn.Seconds()})
+}
+
+// Duration returns how long the session lasted.
+func (s SleepSession) Duration() time.Duration {
+	return s.Finish.Sub(s.Start)
+}
+
+// SleepService allows the user to log and summarize the sleep sessions of a bedroom.
+type SleepService struct {
+	store  Store
+	series Series
+}
+
+// NewSleepService creates a new SleepService, which allows to interact with the sleep sessions
+// of the specified room (DefaultRoom if empty).
+func NewSleepService(s Store, room string) *SleepService {
+	return &SleepService{s, roomSeries(sleepField, room)}
+}
+
+// Room returns the room the service is bound to.
+func (ss *SleepService) Room() string {
+	return ss.series.Room
+}
+
+// Log stores the sleep session, with one second resolution. Sessions starting at the same
+// second replace each other. It returns ErrInvalidSleepSession if the finish is not after the
+// start, the session lasts more than MaxSleepSpan or the quality is out of range.
+func (ss *SleepService) Log(s SleepSession) error {
+	return ss.LogContext(context.Background(), s)
+}
+
+// LogContext is like Log, but honors the context deadline and cancellation.
+func (ss *SleepService) LogContext(ctx context.Context, s SleepSession) error {
+	if s.Start.IsZero() || !s.Finish.After(s.Start) || s.Duration() > MaxSleepSpan || s.Quality < 0 || s.Quality > MaxSleepQuality {
+		return ErrInvalidSleepSession
+	}
+	return ss.store.UpsertSamplesContext(ctx, ss.series, TSRecord{s.Start, sleepDoc{s.Finish.UTC().Truncate(time.Second), s.Quality}})
+}
+
+// Sessions returns the sleep sessions overlapping the considered period, sorted by ascending
+// start.
+func (ss *SleepService) Sessions(start time.Time, finish time.Time) ([]SleepSession, error) {
+	return ss.SessionsContext(context.Background(), start, finish)
+}
+
+// SessionsContext is like Sessions, but honors the context deadline and cancellation.
+func (ss *SleepService) SessionsContext(ctx context.Context, start time.Time, finish time.Time) ([]SleepSession, error) {
+	var ret []SleepSession
+	err := ss.store.StreamContext(ctx, ss.series, start.Add(-MaxSleepSpan), finish, FullResolution, Ascending, func(tr TSRecord) error {
+		var doc sleepDoc
+		if err := decodeValue(tr.Value, &doc); err != nil {
+			return &DecodeError{ss.series, tr.Timestamp, tr.Value, "sleep document"}
+		}
+		if doc.Finish.Before(start) {
+			return nil
+		}
+		ret = append(ret, SleepSession{tr.Timestamp.Local(), doc.Finish.Local(), doc.Quality})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// Summaries summarizes the sleep sessions overlapping the considered period, sorted by
+// ascending start, given the comfort temperature threshold (e.g. 26°C).
+func (ss *SleepService) Summaries(start time.Time, finish time.Time, comfort float64) ([]SleepSummary, error) {
+	return ss.SummariesContext(context.Background(), start, finish, comfort)
+}
+
+// SummariesContext is like Summaries, but honors the context deadline and cancellation.
+func (ss *SleepService) SummariesContext(ctx context.Context, start time.Time, finish time.Time, comfort float64) ([]SleepSummary, error) {
+	sessions, err := ss.SessionsContext(ctx, start, finish)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]SleepSummary, len(sessions))
+	for i, s := range sessions {
+		if ret[i], err = ss.summarize(ctx, s, comfort); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+// step is a value which lasts from its timestamp until the next one.
+type step struct {
+	t time.Time
+	v float64
+}
+
+// durations returns how long each of the steps, sorted by ascending timestamp, lasts until the
+// finish.
+func durations(steps []step, finish time.Time) []time.Duration {
+	ret := make([]time.Duration, len(steps))
+	for i := range steps {
+		next := finish
+		if i+1 < len(steps) {
+			next = steps[i+1].t
+		}
+		ret[i] = next.Sub(steps[i].t)
+	}
+	return ret
+}
+
+func (ss *SleepService) summarize(ctx context.Context, s SleepSession, comfort float64) (SleepSummary, error) {
+	ret := SleepSummary{SleepSession: s, Comfort: comfort}
+
+	// Temperatures.
+	bs := NewBedroomService(ss.store, ss.series.Room)
+	var temps []step
+	err := bs.StreamStateContext(ctx, s.Start.Add(-sleepTempLookback), s.Start.Add(-time.Nanosecond), FullResolution, Descending, func(b BedroomState) error {
+		temps = append(temps, step{s.Start, b.Temperature})
+		ret.Readings++
+		return errStopStream
+	})
+	if err != nil && err != errStopStream {
+		return SleepSummary{}, err
+	}
+	err = bs.StreamStateContext(ctx, s.Start, s.Finish, FullResolution, Ascending, func(b BedroomState) error {
+		temps = append(temps, step{b.Timestamp, b.Temperature})
+		ret.Readings++
+		return nil
+	})
+	if err != nil {
+		return SleepSummary{}, err
+	}
+	if len(temps) > 0 {
+		var sum float64
+		var total time.Duration
+		ret.PeakTemp = temps[0].v
+		for i, d := range durations(temps, s.Finish) {
+			sum += temps[i].v * d.Seconds()
+			total += d
+			if temps[i].v > ret.PeakTemp {
+				ret.PeakTemp = temps[i].v
+			}
+			if temps[i].v > comfort {
+				ret.AboveComfort += d
+			}
+		}
+		if total > 0 {
+			ret.MeanTemp = sum / total.Seconds()
+		} else { // Readings taken at the end of the session only.
+			ret.MeanTemp = temps[len(temps)-1].v
+		}
+	}
+
+	// Fan states. Rooms whose fan status never changed have it off.
+	fs := NewFanService(ss.store, ss.series.Room)
+	fan := []step{{s.Start, float64(FanOff)}}
+	err = fs.StreamStateContext(ctx, time.Time{}, s.Start.Add(-time.Nanosecond), FullResolution, Descending, func(f FanState) error {
+		fan[0].v = float64(f.Status)
+		return errStopStream
+	})
+	if err != nil && err != errStopStream {
+		return SleepSummary{}, err
+	}
+	err = fs.StreamStateContext(ctx, s.Start, s.Finish, FullResolution, Ascending, func(f FanState) error {
+		if FanStatus(fan[len(fan)-1].v) == f.Status {
+			return nil // Not a change.
+		}
+		fan = append(fan, step{f.Timestamp, float64(f.Status)})
+		ret.FanChanges++
+		return nil
+	})
+	if err != nil {
+		return SleepSummary{}, err
+	}
+	for i, d := range durations(fan, s.Finish) {
+		if FanStatus(fan[i].v) != FanOff {
+			ret.FanOn += d
+		}
+	}
+	return ret, nil
+}
@@ -0,0 +1,79 @@
+package tsmongo
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestSleepSessions(t *testing.T) {
+	s := NewMemStore()
+	base := time.Date(2018, 7, 1, 23, 0, 0, 0, time.UTC)
+	ss := NewSleepService(s, DefaultRoom)
+	night := SleepSession{Start: base, Finish: base.Add(8 * time.Hour), Quality: 4}
+	nap := SleepSession{Start: base.Add(15 * time.Hour), Finish: base.Add(16 * time.Hour)}
+	for _, session := range []SleepSession{night, nap} {
+		if err := ss.Log(session); err != nil {
+			t.Fatalf("Log(%+v): %q", session, err)
+		}
+	}
+	invalid := []SleepSession{
+		{Start: base, Finish: base},
+		{Start: base, Finish: base.Add(MaxSleepSpan + time.Hour)},
+		{Start: base, Finish: base.Add(time.Hour), Quality: MaxSleepQuality + 1},
+	}
+	for _, session := range invalid {
+		if err := ss.Log(session); err != ErrInvalidSleepSession {
+			t.Errorf("Log(%+v) want:%q got:%q", session, ErrInvalidSleepSession, err)
+		}
+	}
+	got, err := ss.Sessions(base.Add(2*time.Hour), base.Add(24*time.Hour))
+	if err != nil || len(got) != 2 || !got[0].Finish.Equal(night.Finish) || got[0].Quality != 4 || got[1].Quality != 0 {
+		t.Errorf("Sessions want night and nap got:%+v (%v)", got, err)
+	}
+}
+
+func TestSleepSummaries(t *testing.T) {
+	s := NewMemStore()
+	base := time.Date(2018, 7, 1, 23, 0, 0, 0, time.UTC)
+	bs := NewBedroomService(s, DefaultRoom)
+	bs.UpdateTemperature(base.Add(-10*time.Minute), 28) // Lasts until 01:00.
+	bs.UpdateTemperature(base.Add(2*time.Hour), 25)
+	bs.UpdateTemperature(base.Add(3*time.Hour), 24)
+	fs := NewFanService(s, DefaultRoom)
+	fs.UpdateStatus(base.Add(-2*time.Hour), FanHighSpeed)
+	fs.UpdateStatus(base.Add(time.Hour), FanLowSpeed)
+	fs.UpdateStatus(base.Add(80*time.Minute), FanLowSpeed) // Not a change.
+	fs.UpdateStatus(base.Add(90*time.Minute), FanOff)
+
+	ss := NewSleepService(s, DefaultRoom)
+	ss.Log(SleepSession{Start: base, Finish: base.Add(4 * time.Hour), Quality: 3})
+	// Nothing was measured during the nap, and the fan stayed off.
+	ss.Log(SleepSession{Start: base.Add(15 * time.Hour), Finish: base.Add(16 * time.Hour)})
+
+	got, err := ss.Summaries(base, base.Add(24*time.Hour), 26)
+	if err != nil || len(got) != 2 {
+		t.Fatalf("Summaries want 2 sessions got:%+v (%v)", got, err)
+	}
+	night := got[0]
+	// 28°C for 2 hours, 25°C and 24°C for 1 hour each.
+	if night.Readings != 3 || night.MeanTemp != 26.25 || night.PeakTemp != 28 || night.AboveComfort != 2*time.Hour {
+		t.Errorf("Summaries temperatures want mean:26.25 peak:28 above:2h got:%+v", night)
+	}
+	if night.FanOn != 90*time.Minute || night.FanChanges != 2 || night.Quality != 3 || night.Comfort != 26 {
+		t.Errorf("Summaries fan want on for 1h30m with 2 changes got:%+v", night)
+	}
+	b, err := json.Marshal(night)
+	if err != nil {
+		t.Fatalf("Marshal: %q", err)
+	}
+	var fields map[string]interface{}
+	json.Unmarshal(b, &fields)
+	if fields["fan_on_seconds"] != 5400.0 || fields["above_comfort_seconds"] != 7200.0 || fields["mean_temp"] != 26.25 || fields["start"] == nil {
+		t.Errorf("Marshal want fan_on_seconds:5400, above_comfort_seconds:7200 and mean_temp:26.25 got:%s", b)
+	}
+	nap := got[1]
+	if nap.Readings != 0 || nap.MeanTemp != 0 || nap.AboveComfort != 0 || nap.FanOn != 0 {
+		t.Errorf("Summaries of nap want no readings and fan off got:%+v", nap)
+	}
+}
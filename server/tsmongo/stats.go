@@ -0,0 +1,165 @@
+package tsmongo
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+// bucketOverhead approximates the size (bytes) of the fields of an hour bucket document other
+// than its value and samples: id, field, room, hour, schema version and sample keys.
+const bucketOverhead = 120
+
+// defaultMaxGaps is the number of gaps reported by Stats, unless specified.
+const defaultMaxGaps = 5
+
+// StatsQuery specifies the range summarized by Stats.
+type StatsQuery struct {
+	// Start defaults to the first record of the series, Finish to now.
+	Start  time.Time
+	Finish time.Time
+	// MaxGaps is the number of largest gaps reported. Defaults to 5.
+	MaxGaps int
+	// Now is the time the age of the last record is computed from. Defaults to time.Now().
+	Now time.Time
+}
+
+// Gap is a range of hours without records, from Start to Finish (exclusive).
+type Gap struct {
+	Start  time.Time `json:"start"`
+	Finish time.Time `json:"finish"`
+}
+
+// Hours returns the number of hours missing within the gap.
+func (g Gap) Hours() int {
+	return int(g.Finish.Sub(g.Start) / time.Hour)
+}
+
+// SeriesStats summarizes the records of a series within a range.
+type SeriesStats struct {
+	Series Series `json:"series"`
+	// Count is the number of records (samples, or hourly values of hours without samples).
+	Count int `json:"count"`
+	// Hours is the number of hours with records.
+	Hours int       `json:"hours"`
+	First time.Time `json:"first,omitempty"`
+	Last  time.Time `json:"last,omitempty"`
+	// LastAge is how long ago the last record was taken, in seconds in JSON.
+	LastAge time.Duration `json:"-"`
+	// MissingHours is the number of hours of the range without records. Gaps are the largest
+	// ranges of missing hours, sorted by decreasing length.
+	MissingHours int   `json:"missing_hours"`
+	Gaps         []Gap `json:"gaps,omitempty"`
+	// Size approximates the storage used by the series within the range (bytes), without
+	// indexes.
+	Size int64 `json:"size"`
+}
+
+// MarshalJSON encodes the stats, with the age of the last record in seconds.
+func (s SeriesStats) MarshalJSON() ([]byte, error) {
+	type stats SeriesStats // Without methods, not to recurse.
+	return json.Marshal(struct {
+		stats
+		LastAge float64 `json:"last_age_seconds"`
+	}{stats(s), s.LastAge.Seconds()})
+}
+
+// Span returns the time between the first and the last record.
+func (s SeriesStats) Span() time.Duration {
+	return s.Last.Sub(s.First)
+}
+
+// Stats summarizes the records of the series within the range, e.g. to check whether a sensor
+// was alive during the night.
+func Stats(ctx context.Context, s Store, series Series, q StatsQuery) (SeriesStats, error) {
+	now := q.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	finish := q.Finish
+	if finish.IsZero() {
+		finish = now
+	}
+	maxGaps := q.MaxGaps
+	if maxGaps <= 0 {
+		maxGaps = defaultMaxGaps
+	}
+	ret := SeriesStats{Series: series}
+	var hours []time.Time
+	var lastSize int64 // Size of the last record of the current hour, the hourly value.
+	err := s.StreamContext(ctx, series, q.Start, finish, FullResolution, Ascending, func(tr TSRecord) error {
+		if ret.Count == 0 {
+			ret.First = tr.Timestamp
+		}
+		ret.Count++
+		ret.Last = tr.Timestamp
+		if h := hourUTC(tr.Timestamp); len(hours) == 0 || !hours[len(hours)-1].Equal(h) {
+			hours = append(hours, h)
+			ret.Size += lastSize + bucketOverhead
+		}
+		lastSize = valueSize(tr.Value)
+		ret.Size += lastSize
+		return nil
+	})
+	if err != nil {
+		return SeriesStats{}, err
+	}
+	if ret.Count == 0 && q.Start.IsZero() {
+		return ret, nil
+	}
+	if ret.Count > 0 {
+		ret.Size += lastSize
+		ret.Hours = len(hours)
+		ret.LastAge = now.Sub(ret.Last).Round(time.Second)
+		ret.First, ret.Last = ret.First.Local(), ret.Last.Local()
+	}
+
+	// Gaps between the hours with records, and at both ends of the range.
+	start := hourUTC(q.Start)
+	if q.Start.IsZero() {
+		start = hours[0]
+	}
+	var gaps []Gap
+	next := start
+	for _, h := range append(hours, hourUTC(finish).Add(time.Hour)) {
+		if h.After(next) {
+			gaps = append(gaps, Gap{next.Local(), h.Local()})
+			ret.MissingHours += int(h.Sub(next) / time.Hour)
+		}
+		next = h.Add(time.Hour)
+	}
+	sort.SliceStable(gaps, func(i, j int) bool { return gaps[i].Hours() > gaps[j].Hours() })
+	if len(gaps) > maxGaps {
+		gaps = gaps[:maxGaps]
+	}
+	ret.Gaps = gaps
+	return ret, nil
+}
+
+// Inventory summarizes the series of the field (of every field, if empty) within the range,
+// sorted by field and room.
+func Inventory(ctx context.Context, s Store, field string, q StatsQuery) ([]SeriesStats, error) {
+	series, err := s.ListSeriesContext(ctx, field)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]SeriesStats, len(series))
+	for i, ser := range series {
+		if ret[i], err = Stats(ctx, s, ser, q); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+// valueSize approximates the size of the value, as stored within documents.
+func valueSize(v interface{}) int64 {
+	b, err := bson.Marshal(bson.M{"v": v})
+	if err != nil {
+		return 0
+	}
+	return int64(len(b))
+}
@@ -0,0 +1,70 @@
+package tsmongo
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestStats(t *testing.T) {
+	s := NewMemStore()
+	base := time.Date(2018, 7, 1, 22, 0, 0, 0, time.UTC)
+	bs := NewBedroomService(s, DefaultRoom)
+	bs.UpdateTemperature(base.Add(10*time.Minute), 25)
+	bs.UpdateTemperature(base.Add(20*time.Minute), 25.5)
+	bs.UpdateTemperature(base.Add(time.Hour), 25)
+	// The sensor was down from midnight to 3am.
+	bs.UpdateTemperature(base.Add(5*time.Hour), 24)
+	NewFanService(s, "kids-room").UpdateStatus(base, FanLowSpeed)
+
+	now := base.Add(7 * time.Hour)
+	q := StatsQuery{Start: base, Finish: base.Add(6 * time.Hour), Now: now}
+	got, err := Stats(context.Background(), s, bedroomSeries, q)
+	if err != nil {
+		t.Fatalf("Stats: %q", err)
+	}
+	if got.Count != 4 || got.Hours != 3 || !got.First.Equal(base.Add(10*time.Minute)) || !got.Last.Equal(base.Add(5*time.Hour)) {
+		t.Errorf("Stats want 4 records within 3 hours, from 22:10 to 03:00 got:%+v", got)
+	}
+	if got.Span() != 4*time.Hour+50*time.Minute || got.LastAge != 2*time.Hour {
+		t.Errorf("Stats span want:4h50m got:%v, last age want:2h got:%v", got.Span(), got.LastAge)
+	}
+	// Missing: 00:00 to 03:00 and 04:00 (the finish hour).
+	if got.MissingHours != 4 || len(got.Gaps) != 2 || got.Gaps[0].Hours() != 3 || !got.Gaps[0].Start.Equal(base.Add(2*time.Hour)) {
+		t.Errorf("Stats want 4 missing hours, largest gap from 00:00 got:%d %+v", got.MissingHours, got.Gaps)
+	}
+	if got.Size <= 3*bucketOverhead {
+		t.Errorf("Stats size want more than the bucket overhead got:%d", got.Size)
+	}
+
+	b, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("Marshal: %q", err)
+	}
+	var fields map[string]interface{}
+	json.Unmarshal(b, &fields)
+	if fields["last_age_seconds"] != 7200.0 || fields["missing_hours"] != 4.0 {
+		t.Errorf("Marshal want last_age_seconds:7200 and missing_hours:4 got:%s", b)
+	}
+
+	q.MaxGaps = 1
+	if got, _ := Stats(context.Background(), s, bedroomSeries, q); len(got.Gaps) != 1 {
+		t.Errorf("Stats gaps want:1 got:%+v", got.Gaps)
+	}
+
+	// Series without records within the range miss every hour.
+	q = StatsQuery{Start: base.Add(24 * time.Hour), Finish: base.Add(25 * time.Hour), Now: now}
+	got, err = Stats(context.Background(), s, bedroomSeries, q)
+	if err != nil || got.Count != 0 || got.MissingHours != 2 {
+		t.Errorf("Stats of empty range want 2 missing hours got:%+v (%v)", got, err)
+	}
+
+	all, err := Inventory(context.Background(), s, "", StatsQuery{Now: now})
+	if err != nil {
+		t.Fatalf("Inventory: %q", err)
+	}
+	if len(all) != 2 || all[0].Series.Field != bedroomField || all[1].Series.Room != "kids-room" || all[1].Count != 1 {
+		t.Errorf("Inventory want bedroom and fan@kids-room got:%+v", all)
+	}
+}
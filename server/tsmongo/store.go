@@ -1,7 +1,9 @@
 package tsmongo
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/globalsign/mgo"
@@ -15,17 +17,63 @@ const (
 	valueField             = "value"
 )
 
-// Session represents a connection to a mongo timeseries collection.
+// Store represents a timeseries storage. Records are bucketed by series and hour (UTC), each
+// bucket holding an hourly value and, optionally, sub-hour samples. Range queries return
+// records sorted by descending timestamp. All operations honor the context deadline and
+// cancellation; store implementations also provide context-less variants of them (e.g. Upsert).
+type Store interface {
+	// UpsertContext inserts the given data into the timeseries overriding the data if necessary.
+	UpsertContext(ctx context.Context, series Series, val ...TSRecord) error
+	// UpsertSamplesContext stores the given data as samples within their hour buckets, also
+	// updating the hourly value of the buckets they are the newest samples of.
+	UpsertSamplesContext(ctx context.Context, series Series, val ...TSRecord) error
+	// QueryContext fetches all records of the series within the specified range.
+	QueryContext(ctx context.Context, series Series, start time.Time, finish time.Time) ([]TSRecord, error)
+	// QuerySamplesContext fetches all samples of the series within the specified range.
+	QuerySamplesContext(ctx context.Context, series Series, start time.Time, finish time.Time) ([]TSRecord, error)
+	// LastContext returns the last element in the timeseries. It returns ErrNotFound if there
+	// is none.
+	LastContext(ctx context.Context, series Series) (TSRecord, error)
+	// RemoveRangeContext removes the hour buckets of the series within the specified range,
+	// returning how many were removed.
+	RemoveRangeContext(ctx context.Context, series Series, start time.Time, finish time.Time) (int, error)
+	// RemoveSamplesContext removes the samples of the series taken at the specified times (with
+	// one second resolution), returning how many were removed. The hourly value of their
+	// buckets becomes the newest remaining sample, and buckets left empty are removed.
+	RemoveSamplesContext(ctx context.Context, series Series, t ...time.Time) (int, error)
+	// StreamContext calls fn for each record of the series within the specified range, at the
+	// specified resolution and order. Records are decoded lazily, so arbitrarily long ranges
+	// can be processed in constant memory. Streaming stops at the first error, which is
+	// returned.
+	StreamContext(ctx context.Context, series Series, start, finish time.Time, res Resolution, order Order, fn func(TSRecord) error) error
+	// ListSeriesContext lists the series of the field (or all series, if the field is empty),
+	// sorted by field and room.
+	ListSeriesContext(ctx context.Context, field string) ([]Series, error)
+	// AggregateContext aggregates the samples of the series (at the path within stored
+	// documents, if not empty) into time windows.
+	AggregateContext(ctx context.Context, series Series, path string, q AggregateQuery) ([]AggregateBucket, error)
+	// Close releases resources associated with the store.
+	Close()
+}
+
+// ErrNotFound is returned when the timeseries has no records.
+var ErrNotFound = mgo.ErrNotFound
+
+// Session represents a connection to a mongo timeseries collection. Operations failing because
+// the database could not be reached refresh the session and are retried once, and then return
+// an *UnreachableError.
 type Session struct {
 	session *mgo.Session
 	dbName  string
 	col     *mgo.Collection
+	health  health
 }
 
 // NewSession creates a new Session instance, which allows the communication to the underlying
 // timeseries mongo database;
 func NewSession(s *mgo.Session, dbName string) *Session {
-	return &Session{s.Copy(), dbName, s.DB(dbName).C(statusDBCollectionName)}
+	c := s.Copy()
+	return &Session{session: c, dbName: dbName, col: c.DB(dbName).C(statusDBCollectionName)}
 }
 
 // TSRecord represents a value to be added to timeseries database.
@@ -34,7 +82,23 @@ type TSRecord struct {
 	Value     interface{} `bson:"value,omitempty"`
 }
 
-// Dial sets up a connection to the specified timeseries database specified by the passed-in URI.
+// Open opens the timeseries store specified by the passed-in URI. URIs using the file scheme
+// (e.g. file:///var/lib/temp-to-go/ts.db) open an embedded FileStore, any other URI is dialed
+// as a mongo database.
+func Open(uri string) (Store, error) {
+	if strings.HasPrefix(uri, fileURIScheme) {
+		return OpenFile(strings.TrimPrefix(uri, fileURIScheme))
+	}
+	s, err := Dial(uri)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Dial sets up a connection to the specified timeseries database specified by the passed-in URI,
+// applying pending schema migrations (see Migrate), so data written by older versions is found
+// and the required indexes exist.
 func Dial(uri string) (*Session, error) {
 	info, err := mgo.ParseURL(uri)
 	if err != nil {
@@ -46,82 +110,372 @@ func Dial(uri string) (*Session, error) {
 	}
 	s.SetMode(mgo.Monotonic, true)
 	dbName := info.Database
-	return NewSession(s, dbName), nil
+	session := NewSession(s, dbName)
+	if _, err := session.Migrate(); err != nil {
+		session.Close()
+		return nil, fmt.Errorf("error migrating db:\"%s\" err:%q", dbName, err)
+	}
+	return session, nil
 }
 
 // Upsert inserts the given data into the timeseries database overriding the data if necessary.
 // If more than one values are passed, it performs a bulk-upsert.
-func (s *Session) Upsert(field string, val ...TSRecord) error {
+func (s *Session) Upsert(series Series, val ...TSRecord) error {
+	return s.UpsertContext(context.Background(), series, val...)
+}
+
+// UpsertContext is like Upsert, but honors the context deadline and cancellation.
+func (s *Session) UpsertContext(ctx context.Context, series Series, val ...TSRecord) error {
 	// Inspiration: https://www.mongodb.com/blog/post/schema-design-for-time-series-data-in-mongodb
-	switch len(val) {
-	case 0:
+	if len(val) == 0 {
 		return nil
-	case 1:
-		utc := hourUTC(val[0].Timestamp)
-		_, err := s.col.Upsert(
-			bson.M{timestampIndexField: utc, typeField: field},
-			bson.M{
-				timestampIndexField: utc,
-				typeField:           field,
-				valueField:          val[0].Value,
-			},
-		)
-		return err
-	default:
-		bulk := s.col.Bulk()
+	}
+	return s.run(ctx, func(col *mgo.Collection) error {
+		if len(val) == 1 {
+			utc := hourUTC(val[0].Timestamp)
+			_, err := col.Upsert(series.bucketSelector(utc), series.bucketDoc(utc, val[0].Value))
+			return err
+		}
+		bulk := col.Bulk()
 		for _, v := range val {
 			utc := hourUTC(v.Timestamp)
-			bulk.Upsert(
-				bson.M{timestampIndexField: utc, typeField: field},
-				bson.M{
-					timestampIndexField: utc,
-					typeField:           field,
-					valueField:          v.Value,
-				},
-			)
+			bulk.Upsert(series.bucketSelector(utc), series.bucketDoc(utc, v.Value))
 		}
 		_, err := bulk.Run()
 		return err
+	})
+}
+
+// UpsertSamples stores the given data as samples within their hour buckets, also updating the
+// hourly value of the buckets they are the newest samples of. Other samples stored in the same
+// bucket are preserved.
+func (s *Session) UpsertSamples(series Series, val ...TSRecord) error {
+	return s.UpsertSamplesContext(context.Background(), series, val...)
+}
+
+// UpsertSamplesContext is like UpsertSamples, but honors the context deadline and cancellation.
+func (s *Session) UpsertSamplesContext(ctx context.Context, series Series, val ...TSRecord) error {
+	if len(val) == 0 {
+		return nil
 	}
+	return s.run(ctx, func(col *mgo.Collection) error {
+		bulk := col.Bulk()
+		for _, v := range val {
+			t := sampleTime(v.Timestamp)
+			set := bson.M{
+				samplePath(t):      v.Value,
+				schemaVersionField: currentSchemaVersion,
+			}
+			if len(series.Tags) > 0 {
+				set[tagsField] = series.Tags
+			}
+			bulk.Upsert(series.bucketSelector(hourUTC(t)), bson.M{"$set": set, "$max": bson.M{lastSampleField: t}})
+			// The hourly value is only replaced by the newest sample of the bucket.
+			bulk.Update(series.newestSelector(t), bson.M{"$set": bson.M{valueField: v.Value}})
+		}
+		_, err := bulk.Run()
+		return err
+	})
 }
 
 // Query fetches all records from timeseries mongo with the specified range.
-func (s *Session) Query(field string, start time.Time, finish time.Time) ([]TSRecord, error) {
+func (s *Session) Query(series Series, start time.Time, finish time.Time) ([]TSRecord, error) {
+	return s.QueryContext(context.Background(), series, start, finish)
+}
+
+// QueryContext is like Query, but honors the context deadline and cancellation.
+func (s *Session) QueryContext(ctx context.Context, series Series, start time.Time, finish time.Time) ([]TSRecord, error) {
 	startUTC := start.In(time.UTC)
 	finishUTC := finish.In(time.UTC)
-	iter := s.col.Find(
-		bson.M{
+	var ret []TSRecord
+	err := s.run(ctx, func(col *mgo.Collection) error {
+		iter := col.Find(
+			bson.M{
+				timestampIndexField: bson.M{
+					"$gte": startUTC,
+					"$lte": finishUTC,
+				},
+				typeField: series.Field,
+				roomField: series.Room,
+			}).Sort("-" + timestampIndexField).Iter()
+
+		var err error
+		if ret, err = readRecords(iter); err != nil {
+			return fmt.Errorf("Error querying tsmongo within range(%v,%v): %q", start, finish, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// QuerySamples fetches all samples from timeseries mongo within the specified range.
+func (s *Session) QuerySamples(series Series, start time.Time, finish time.Time) ([]TSRecord, error) {
+	return s.QuerySamplesContext(context.Background(), series, start, finish)
+}
+
+// QuerySamplesContext is like QuerySamples, but honors the context deadline and cancellation.
+func (s *Session) QuerySamplesContext(ctx context.Context, series Series, start time.Time, finish time.Time) ([]TSRecord, error) {
+	var ret []TSRecord
+	err := s.run(ctx, func(col *mgo.Collection) error {
+		iter := col.Find(
+			bson.M{
+				timestampIndexField: bson.M{
+					"$gte": hourUTC(start),
+					"$lte": finish.In(time.UTC),
+				},
+				typeField: series.Field,
+				roomField: series.Room,
+			}).Iter()
+
+		var err error
+		if ret, err = readSamples(iter, start, finish); err != nil {
+			return fmt.Errorf("Error querying tsmongo samples within range(%v,%v): %q", start, finish, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sortDesc(ret)
+	return ret, nil
+}
+
+// RemoveRange removes the hour buckets of the series within the specified range, returning how
+// many were removed.
+func (s *Session) RemoveRange(series Series, start time.Time, finish time.Time) (int, error) {
+	return s.RemoveRangeContext(context.Background(), series, start, finish)
+}
+
+// RemoveRangeContext is like RemoveRange, but honors the context deadline and cancellation.
+func (s *Session) RemoveRangeContext(ctx context.Context, series Series, start time.Time, finish time.Time) (int, error) {
+	removed := 0
+	err := s.run(ctx, func(col *mgo.Collection) error {
+		info, err := col.RemoveAll(bson.M{
 			timestampIndexField: bson.M{
-				"$gte": startUTC,
-				"$lte": finishUTC,
+				"$gte": start.In(time.UTC),
+				"$lte": finish.In(time.UTC),
 			},
-			typeField: field,
-		}).Sort("-" + timestampIndexField).Iter()
+			typeField: series.Field,
+			roomField: series.Room,
+		})
+		if err != nil {
+			return fmt.Errorf("Error removing tsmongo records within range(%v,%v): %q", start, finish, err)
+		}
+		removed = info.Removed
+		return nil
+	})
+	return removed, err
+}
 
-	var ret []TSRecord
-	var d TSRecord
-	for iter.Next(&d) {
-		ret = append(ret, d)
+// RemoveSamples removes the samples of the series taken at the specified times (with one second
+// resolution), returning how many were removed. The hourly value of their buckets becomes the
+// newest remaining sample, and buckets left empty are removed. Buckets without samples hold a
+// single record, at the beginning of the hour.
+func (s *Session) RemoveSamples(series Series, t ...time.Time) (int, error) {
+	return s.RemoveSamplesContext(context.Background(), series, t...)
+}
+
+// RemoveSamplesContext is like RemoveSamples, but honors the context deadline and cancellation.
+func (s *Session) RemoveSamplesContext(ctx context.Context, series Series, t ...time.Time) (int, error) {
+	removed := 0
+	for _, t := range t {
+		var found bool
+		err := s.run(ctx, func(col *mgo.Collection) error {
+			var err error
+			found, err = removeSample(col, series, sampleTime(t))
+			if err != nil {
+				return fmt.Errorf("Error removing tsmongo sample at %v: %q", t, err)
+			}
+			return nil
+		})
+		if err != nil {
+			return removed, err
+		}
+		if found {
+			removed++
+		}
 	}
-	if err := iter.Close(); err != nil {
+	return removed, nil
+}
+
+// removeSample removes the sample of the series taken at t from its bucket, returning whether it
+// was found. Samples other than the newest one are unset right away, as the hourly value does
+// not change. Otherwise, the bucket is updated to have the previous sample as hourly value (or
+// removed, if there is none) provided no newer sample has been written meanwhile, which is
+// retried if it was.
+func removeSample(col *mgo.Collection, series Series, t time.Time) (bool, error) {
+	hour := hourUTC(t)
+	path := samplePath(t)
+	for {
+		older := series.bucketSelector(hour)
+		older[path] = bson.M{"$exists": true}
+		older[lastSampleField] = bson.M{"$gt": t}
+		err := col.Update(older, bson.M{"$unset": bson.M{path: ""}})
+		if err != mgo.ErrNotFound {
+			return err == nil, err
+		}
+
+		var b bucket
+		err = col.Find(series.bucketSelector(hour)).One(&b)
 		if err == mgo.ErrNotFound {
-			return ret, nil
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		unchanged := series.bucketSelector(hour)
+		if len(b.Samples) == 0 {
+			if !t.Equal(hour) {
+				return false, nil
+			}
+			unchanged[samplesField] = bson.M{"$exists": false}
+			err = col.Remove(unchanged)
+		} else {
+			min, sec := sampleKey(t)
+			if _, ok := b.Samples[min][sec]; !ok {
+				return false, nil
+			}
+			delete(b.Samples[min], sec)
+			if b.Last.IsZero() { // Written before the newest sample was recorded.
+				unchanged[lastSampleField] = bson.M{"$exists": false}
+			} else {
+				unchanged[lastSampleField] = b.Last
+			}
+			if prev, ok := b.newest(); ok {
+				err = col.Update(unchanged, bson.M{
+					"$unset": bson.M{path: ""},
+					"$set":   bson.M{valueField: prev.Value, lastSampleField: prev.Timestamp},
+				})
+			} else {
+				err = col.Remove(unchanged)
+			}
+		}
+		if err != mgo.ErrNotFound {
+			return err == nil, err
 		}
-		return nil, fmt.Errorf("Error querying tsmongo within range(%v,%v): %q", start, finish, err)
 	}
-	return ret, nil
 }
 
 // Last returns the last element in the timeseries, if any.
-func (s *Session) Last(field string) (TSRecord, error) {
+func (s *Session) Last(series Series) (TSRecord, error) {
+	return s.LastContext(context.Background(), series)
+}
+
+// LastContext is like Last, but honors the context deadline and cancellation.
+func (s *Session) LastContext(ctx context.Context, series Series) (TSRecord, error) {
 	var r TSRecord
-	err := s.col.Find(bson.M{typeField: field}).Sort("-" + timestampIndexField).One(&r)
+	err := s.run(ctx, func(col *mgo.Collection) error {
+		return col.Find(series.selector()).Sort("-" + timestampIndexField).One(&r)
+	})
 	if err != nil {
 		return TSRecord{}, err
 	}
 	return r, nil
 }
 
+// run runs the operation over the timeseries collection honoring the context. If the database
+// could not be reached, the session is refreshed and the operation is retried once (operations
+// are idempotent upserts, removals or reads).
+func (s *Session) run(ctx context.Context, op func(col *mgo.Collection) error) error {
+	return retryOnce(ctx, func() error { return s.runOnce(ctx, op) }, s.Refresh)
+}
+
+// retryOnce runs the attempt, refreshing and retrying once if the database could not be
+// reached. Attempts may be interrupted halfway, e.g. while iterating over the results of a
+// query, so they must not accumulate state across runs.
+func retryOnce(ctx context.Context, attempt func() error, refresh func()) error {
+	err := attempt()
+	if !unreachable(err) {
+		return err
+	}
+	refresh()
+	if ctx.Err() == nil {
+		err = attempt()
+	}
+	if unreachable(err) {
+		refresh()
+		return &UnreachableError{err}
+	}
+	return err
+}
+
+// iterator iterates over the results of a query, as *mgo.Iter does.
+type iterator interface {
+	Next(result interface{}) bool
+	Close() error
+}
+
+// readRecords reads the records the iterator yields. Queries without results are not an error.
+func readRecords(iter iterator) ([]TSRecord, error) {
+	var ret []TSRecord
+	var d TSRecord
+	for iter.Next(&d) {
+		ret = append(ret, d)
+	}
+	if err := iter.Close(); err != nil && err != mgo.ErrNotFound {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// readSamples reads the samples within the specified range of the buckets the iterator yields.
+// Queries without results are not an error.
+func readSamples(iter iterator, start time.Time, finish time.Time) ([]TSRecord, error) {
+	var ret []TSRecord
+	var b bucket
+	for iter.Next(&b) {
+		ret = append(ret, b.records(start, finish)...)
+		b = bucket{}
+	}
+	if err := iter.Close(); err != nil && err != mgo.ErrNotFound {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// runOnce runs the operation over the timeseries collection honoring the context. mgo does not
+// support contexts, so operations run with a copy of the session, whose timeouts are set
+// after the context deadline and which is closed (interrupting the operation) if the context
+// is done first.
+func (s *Session) runOnce(ctx context.Context, op func(col *mgo.Collection) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if ctx.Done() == nil { // Context can never be canceled, e.g. context.Background().
+		return op(s.col)
+	}
+	col, release := s.collection(ctx)
+	defer release()
+	done := make(chan error, 1)
+	go func() {
+		done <- op(col)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// collection returns the timeseries collection bound to a copy of the session, whose timeouts
+// are set after the context deadline, and a function which releases the copy.
+func (s *Session) collection(ctx context.Context) (*mgo.Collection, func()) {
+	if ctx.Done() == nil {
+		return s.col, func() {}
+	}
+	c := s.session.Copy()
+	if d, ok := ctx.Deadline(); ok {
+		timeout := time.Until(d)
+		c.SetSocketTimeout(timeout)
+		c.SetSyncTimeout(timeout)
+	}
+	return c.DB(s.dbName).C(statusDBCollectionName), c.Close
+}
+
 // Close release resources associated with this connection.
 func (s *Session) Close() {
 	s.session.Close()
@@ -130,9 +484,16 @@ func (s *Session) Close() {
 // Copy works just like New, but preserves the database and any authentication
 // information from the original session.
 func (s *Session) Copy() *Session {
-	c := s.session.Copy()
-	dbName := s.dbName
-	return NewSession(c, dbName)
+	return NewSession(s.session, s.dbName)
+}
+
+// decodeValue decodes a stored document value into out.
+func decodeValue(v interface{}, out interface{}) error {
+	b, err := bson.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return bson.Unmarshal(b, out)
 }
 
 func hourUTC(ts time.Time) time.Time {
@@ -141,31 +502,38 @@ func hourUTC(ts time.Time) time.Time {
 }
 
 // NewBedroomService creates a new BedroomService, which allows to interact with the bedroom field
-// of the timeseries.
-func NewBedroomService(s *Session) *BedroomService {
-	return &BedroomService{s}
+// of the timeseries, for the specified room (DefaultRoom if empty).
+func NewBedroomService(s Store, room string) *BedroomService {
+	return &BedroomService{s, roomSeries(bedroomField, room)}
 }
 
-// NewFanService creates a new BedroomService, which allows to interact with the fan field
-// of the timeseries.
-func NewFanService(s *Session) *FanService {
-	return &FanService{s}
+// NewFanService creates a new FanService, which allows to interact with the fan field
+// of the timeseries, for the specified room (DefaultRoom if empty).
+func NewFanService(s Store, room string) *FanService {
+	return &FanService{s, roomSeries(fanField, room)}
+}
+
+func roomSeries(field, room string) Series {
+	if room == "" {
+		room = DefaultRoom
+	}
+	return Series{Field: field, Room: room}
 }
 
 // NewWeatherService creates a new WeatherService, which allows to interact with the weather field
 // of the timeseries.
-func NewWeatherService(s *Session) *WeatherService {
+func NewWeatherService(s Store) *WeatherService {
 	return &WeatherService{s}
 }
 
 // NewForecastService creates a new ForecastService, which allows to interact with the forecast field
 // of the timeseries.
-func NewForecastService(s *Session) *ForecastService {
+func NewForecastService(s Store) *ForecastService {
 	return &ForecastService{s}
 }
 
 // NewPredictionService creates a new PredictionService, which allows to interact with the predictions field
 // of the timeseries.
-func NewPredictionService(s *Session) *PredictionService {
+func NewPredictionService(s Store) *PredictionService {
 	return &PredictionService{s}
 }
@@ -0,0 +1,140 @@
+package tsmongo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+// Order specifies the order records are streamed.
+type Order int
+
+// Enumerates the possible orders, by timestamp.
+const (
+	Descending Order = iota
+	Ascending
+)
+
+// Stream calls fn for each record of the series within the range, at the specified resolution
+// and order. Documents are fetched in batches and decoded lazily, so memory usage does not
+// depend on the size of the range.
+func (s *Session) Stream(series Series, start, finish time.Time, res Resolution, order Order, fn func(TSRecord) error) error {
+	return s.StreamContext(context.Background(), series, start, finish, res, order, fn)
+}
+
+// StreamContext is like Stream, but honors the context deadline and cancellation. The
+// context is checked between records and its deadline is also enforced server-side.
+func (s *Session) StreamContext(ctx context.Context, series Series, start, finish time.Time, res Resolution, order Order, fn func(TSRecord) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	// Not using s.run, fn must not be called after this call returns.
+	col, release := s.collection(ctx)
+	defer release()
+	first := start.In(time.UTC)
+	if res == FullResolution {
+		first = hourUTC(start)
+	}
+	q := col.Find(
+		bson.M{
+			timestampIndexField: bson.M{
+				"$gte": first,
+				"$lte": finish.In(time.UTC),
+			},
+			typeField: series.Field,
+			roomField: series.Room,
+		}).Sort(order.sortKey(timestampIndexField))
+	if d, ok := ctx.Deadline(); ok {
+		q = q.SetMaxTime(time.Until(d))
+	}
+	iter := q.Iter()
+	var b bucket
+	for iter.Next(&b) {
+		if err := ctx.Err(); err != nil {
+			iter.Close()
+			return err
+		}
+		trs := []TSRecord{{b.Timestamp, b.Value}}
+		if res == FullResolution {
+			trs = b.records(start, finish)
+			order.sort(trs)
+		}
+		for _, tr := range trs {
+			if err := fn(tr); err != nil {
+				iter.Close()
+				return err
+			}
+		}
+		b = bucket{}
+	}
+	if err := iter.Close(); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if unreachable(err) {
+			// Not retrying, fn may have been called already.
+			s.Refresh()
+			return &UnreachableError{err}
+		}
+		return fmt.Errorf("Error streaming tsmongo within range(%v,%v): %q", start, finish, err)
+	}
+	return nil
+}
+
+// Stream calls fn for each record of the series within the range, at the specified resolution
+// and order.
+func (m *MemStore) Stream(series Series, start, finish time.Time, res Resolution, order Order, fn func(TSRecord) error) error {
+	return m.StreamContext(context.Background(), series, start, finish, res, order, fn)
+}
+
+// StreamContext is like Stream, but stops if the context is done. Records are collected
+// before fn is called (all data is in memory anyway), so fn can write to the store.
+func (m *MemStore) StreamContext(ctx context.Context, series Series, start, finish time.Time, res Resolution, order Order, fn func(TSRecord) error) error {
+	trs, err := fetch(ctx, m, series, start, finish, res)
+	if err != nil {
+		return err
+	}
+	order.sort(trs)
+	for _, tr := range trs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(tr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Stream calls fn for each record of the series within the range, at the specified resolution
+// and order.
+func (fs *FileStore) Stream(series Series, start, finish time.Time, res Resolution, order Order, fn func(TSRecord) error) error {
+	return fs.StreamContext(context.Background(), series, start, finish, res, order, fn)
+}
+
+// StreamContext is like Stream, but stops if the context is done.
+func (fs *FileStore) StreamContext(ctx context.Context, series Series, start, finish time.Time, res Resolution, order Order, fn func(TSRecord) error) error {
+	mem, err := fs.sync(ctx)
+	if err != nil {
+		return err
+	}
+	return mem.StreamContext(ctx, series, start, finish, res, order, fn)
+}
+
+func (o Order) sortKey(field string) string {
+	if o == Ascending {
+		return field
+	}
+	return "-" + field
+}
+
+func (o Order) sort(trs []TSRecord) {
+	if o == Ascending {
+		sort.Slice(trs, func(i, j int) bool { return trs[i].Timestamp.Before(trs[j].Timestamp) })
+		return
+	}
+	sortDesc(trs)
+}
@@ -0,0 +1,72 @@
+package tsmongo
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStream(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "stream_testing")
+	defer os.RemoveAll(dir)
+	fileStore, err := OpenFile(filepath.Join(dir, "ts.db"))
+	if err != nil {
+		t.Fatalf("OpenFile: %q", err)
+	}
+	defer fileStore.Close()
+
+	for name, s := range map[string]Store{"mem": NewMemStore(), "file": fileStore} {
+		t.Run(name, func(t *testing.T) {
+			base := time.Date(2018, 7, 1, 10, 0, 0, 0, time.UTC)
+			bs := NewBedroomService(s, DefaultRoom)
+			bs.UpdateTemperature(base.Add(-time.Hour), 24)
+			bs.UpdateTemperature(base.Add(10*time.Second), 25)
+			bs.UpdateTemperature(base.Add(30*time.Minute), 26)
+
+			var asc []BedroomState
+			err := bs.StreamState(base.Add(-time.Hour), base.Add(time.Hour), FullResolution, Ascending, func(st BedroomState) error {
+				asc = append(asc, st)
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("StreamState(Ascending): %q", err)
+			}
+			checkStates(t, []BedroomState{{base.Add(-time.Hour), 24}, {base.Add(10 * time.Second), 25}, {base.Add(30 * time.Minute), 26}}, asc)
+
+			var desc []BedroomState
+			err = bs.StreamState(base.Add(-time.Hour), base.Add(time.Hour), Hourly, Descending, func(st BedroomState) error {
+				desc = append(desc, st)
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("StreamState(Descending): %q", err)
+			}
+			checkStates(t, []BedroomState{{base, 26}, {base.Add(-time.Hour), 24}}, desc)
+
+			// Errors returned by the callback stop the stream.
+			errStop := errors.New("stop")
+			calls := 0
+			err = bs.StreamState(base.Add(-time.Hour), base.Add(time.Hour), FullResolution, Ascending, func(BedroomState) error {
+				calls++
+				return errStop
+			})
+			if err != errStop || calls != 1 {
+				t.Errorf("StreamState stop want:(%q, 1) got:(%q, %d)", errStop, err, calls)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			err = bs.StreamStateContext(ctx, base.Add(-time.Hour), base.Add(time.Hour), FullResolution, Ascending, func(BedroomState) error {
+				t.Errorf("StreamStateContext called fn with canceled context")
+				return nil
+			})
+			if err != context.Canceled {
+				t.Errorf("StreamStateContext want:%q got:%q", context.Canceled, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,328 @@
+package tsmongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+)
+
+// PollInterval is how often watchers poll the store for new records, when the store does not
+// support change streams (e.g. MongoDB is not running as a replica set).
+var PollInterval = 5 * time.Second
+
+// PollWindow is how far before the newest record polling watchers look for records written
+// late (e.g. retried or replayed writes) or rewritten with a new value (e.g. corrections).
+var PollWindow = 24 * time.Hour
+
+// watchHorizon is the timestamp upper bound used when polling for new records.
+var watchHorizon = time.Date(9999, 12, 31, 23, 59, 59, 0, time.UTC)
+
+// errWatchStopped is used internally to interrupt streaming when the watcher is closed.
+var errWatchStopped = errors.New("watcher stopped")
+
+// watcher runs the subscription and keeps track of its state.
+type watcher struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+	mu     sync.Mutex
+	err    error
+}
+
+// Close stops the watcher, waiting until its channel is closed. It returns the same as Err.
+func (w *watcher) Close() error {
+	w.cancel()
+	<-w.done
+	return w.Err()
+}
+
+// Err returns the error which stopped the watcher, if any. Closing the watcher or canceling
+// its context are not considered errors.
+func (w *watcher) Err() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.err
+}
+
+// Watcher delivers the records written to a series, as they arrive.
+type Watcher struct {
+	// C receives the new records. It is closed when the watcher stops.
+	C <-chan TSRecord
+	*watcher
+}
+
+// Watch subscribes to records written to the series after the call. MongoDB change streams are
+// used when available, otherwise the store is polled every PollInterval, which delivers the
+// records written within PollWindow of the newest one. The watcher stops when closed, when the
+// context is done or at the first error.
+func Watch(ctx context.Context, s Store, series Series) (*Watcher, error) {
+	c := make(chan TSRecord)
+	w, err := startWatch(ctx, s, series, func(ctx context.Context, tr TSRecord) bool {
+		select {
+		case c <- tr:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}, func() { close(c) })
+	if err != nil {
+		return nil, err
+	}
+	return &Watcher{C: c, watcher: w}, nil
+}
+
+// startWatch subscribes to the series and delivers new records in background, calling emit for
+// each of them. Emit returns false if the record could not be delivered because the watcher is
+// stopping. Stop is called once the subscription is over.
+func startWatch(ctx context.Context, s Store, series Series, emit func(context.Context, TSRecord) bool, stop func()) (*watcher, error) {
+	// The subscription starting point must be set before returning, otherwise records written
+	// right after the call could be missed.
+	var run func(ctx context.Context, deliver func(TSRecord) bool) error
+	if session, ok := s.(*Session); ok {
+		if cs, release, err := session.changeStream(series); err == nil {
+			run = func(ctx context.Context, deliver func(TSRecord) bool) error {
+				defer release()
+				return watchChanges(ctx, cs, deliver)
+			}
+		}
+	}
+	if run == nil {
+		cursor, err := newPollCursor(ctx, s, series)
+		if err != nil {
+			return nil, err
+		}
+		run = func(ctx context.Context, deliver func(TSRecord) bool) error {
+			return poll(ctx, s, series, cursor, deliver)
+		}
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	w := &watcher{cancel: cancel, done: make(chan struct{})}
+	go func() {
+		defer close(w.done)
+		defer stop()
+		err := run(ctx, func(tr TSRecord) bool { return emit(ctx, tr) })
+		if err != nil && ctx.Err() == nil {
+			w.mu.Lock()
+			w.err = err
+			w.mu.Unlock()
+		}
+	}()
+	return w, nil
+}
+
+// pollCursor keeps track of the records already seen by a polling watcher.
+type pollCursor struct {
+	last time.Time             // Timestamp of the newest record.
+	seen map[int64]interface{} // Values of the records within PollWindow of the newest one, by Unix nanoseconds.
+}
+
+// newPollCursor creates a cursor which has seen the records currently stored in the series.
+func newPollCursor(ctx context.Context, s Store, series Series) (*pollCursor, error) {
+	last, err := lastTimestamp(ctx, s, series)
+	if err != nil {
+		return nil, err
+	}
+	c := &pollCursor{last: last, seen: make(map[int64]interface{})}
+	err = c.stream(ctx, s, series, func(TSRecord) bool { return true })
+	if err != nil {
+		return nil, fmt.Errorf("Error polling tsmongo series %q: %q", series, err)
+	}
+	return c, nil
+}
+
+// stream streams the records within PollWindow of the newest one, calling deliver for the ones
+// not seen yet (or seen with another value), which are then marked as seen.
+func (c *pollCursor) stream(ctx context.Context, s Store, series Series, deliver func(TSRecord) bool) error {
+	var start time.Time
+	if !c.last.IsZero() {
+		start = c.last.Add(-PollWindow)
+	}
+	err := s.StreamContext(ctx, series, start, watchHorizon, FullResolution, Ascending, func(tr TSRecord) error {
+		key := tr.Timestamp.UnixNano()
+		if v, ok := c.seen[key]; ok && reflect.DeepEqual(v, tr.Value) {
+			return nil
+		}
+		if !deliver(tr) {
+			return errWatchStopped
+		}
+		c.seen[key] = tr.Value
+		if tr.Timestamp.After(c.last) {
+			c.last = tr.Timestamp
+		}
+		return nil
+	})
+	// Forgetting the records which will not be polled again.
+	horizon := c.last.Add(-PollWindow).UnixNano()
+	for key := range c.seen {
+		if key < horizon {
+			delete(c.seen, key)
+		}
+	}
+	return err
+}
+
+// poll periodically streams the records not seen by the cursor.
+func poll(ctx context.Context, s Store, series Series, c *pollCursor, deliver func(TSRecord) bool) error {
+	ticker := time.NewTicker(PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		err := c.stream(ctx, s, series, deliver)
+		switch {
+		case err == errWatchStopped || ctx.Err() != nil:
+			return nil
+		case err != nil:
+			return fmt.Errorf("Error polling tsmongo series %q: %q", series, err)
+		}
+	}
+}
+
+// lastTimestamp returns the timestamp of the most recent record of the series, or the zero time
+// if there is none.
+func lastTimestamp(ctx context.Context, s Store, series Series) (time.Time, error) {
+	last, err := s.LastContext(ctx, series)
+	switch {
+	case err == ErrNotFound:
+		return time.Time{}, nil
+	case err != nil:
+		return time.Time{}, fmt.Errorf("Error fetching last tsmongo record of series %q: %q", series, err)
+	}
+	// Last returns the hour bucket, the most recent sample might be anywhere within it.
+	trs, err := s.QuerySamplesContext(ctx, series, last.Timestamp, watchHorizon)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("Error fetching last tsmongo record of series %q: %q", series, err)
+	}
+	if len(trs) == 0 {
+		return last.Timestamp, nil
+	}
+	return trs[0].Timestamp, nil
+}
+
+// changeEvent is the subset of the change stream event used by watchers.
+type changeEvent struct {
+	OperationType     string `bson:"operationType"`
+	FullDocument      bucket `bson:"fullDocument"`
+	UpdateDescription struct {
+		UpdatedFields bson.M `bson:"updatedFields"`
+	} `bson:"updateDescription"`
+}
+
+// changeStreamAwait is how long the change stream waits for changes before checking whether
+// the watcher has been stopped.
+const changeStreamAwait = time.Second
+
+// changeStream opens a change stream over the series bucket documents, using a copy of the
+// session (so waiting for changes does not block other operations) which is closed along with
+// the stream. It fails if the server does not support change streams.
+func (s *Session) changeStream(series Series) (*mgo.ChangeStream, func(), error) {
+	c := s.session.Copy()
+	pipeline := []bson.M{{"$match": bson.M{
+		"operationType":             bson.M{"$in": []string{"insert", "update", "replace"}},
+		"fullDocument." + typeField: series.Field,
+		"fullDocument." + roomField: series.Room,
+	}}}
+	cs, err := c.DB(s.dbName).C(statusDBCollectionName).Watch(pipeline, mgo.ChangeStreamOptions{
+		FullDocument:   mgo.UpdateLookup,
+		MaxAwaitTimeMS: changeStreamAwait,
+	})
+	if err != nil {
+		c.Close()
+		return nil, nil, err
+	}
+	return cs, func() {
+		cs.Close()
+		c.Close()
+	}, nil
+}
+
+// watchChanges delivers the records written to the bucket documents reported by the change
+// stream, until the context is done.
+func watchChanges(ctx context.Context, cs *mgo.ChangeStream, deliver func(TSRecord) bool) error {
+	for {
+		var ev changeEvent
+		for cs.Next(&ev) {
+			for _, tr := range ev.records() {
+				if !deliver(tr) {
+					return nil
+				}
+			}
+			ev = changeEvent{}
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		if !cs.Timeout() {
+			if err := cs.Err(); err != nil {
+				return fmt.Errorf("Error watching tsmongo changes: %q", err)
+			}
+			return errors.New("Error watching tsmongo changes: change stream closed")
+		}
+	}
+}
+
+// records returns the records written by the change, in ascending order.
+func (ev changeEvent) records() []TSRecord {
+	b := ev.FullDocument
+	hourEnd := b.Timestamp.Add(time.Hour - time.Nanosecond)
+	if ev.OperationType != "update" {
+		trs := b.records(b.Timestamp, hourEnd)
+		Ascending.sort(trs)
+		return trs
+	}
+	// Only the samples set by the update are new, which are reported as dotted paths, e.g.
+	// "values.10.30", or as sub-documents when the path did not exist yet.
+	samples := make(map[string]map[string]interface{})
+	for k, v := range ev.UpdateDescription.UpdatedFields {
+		path := strings.Split(k, ".")
+		if path[0] != samplesField {
+			continue
+		}
+		switch len(path) {
+		case 3:
+			addSample(samples, path[1], path[2], v)
+		case 2:
+			if secs, ok := v.(bson.M); ok {
+				for sec, v := range secs {
+					addSample(samples, path[1], sec, v)
+				}
+			}
+		case 1:
+			if mins, ok := v.(bson.M); ok {
+				for min, secs := range mins {
+					if secs, ok := secs.(bson.M); ok {
+						for sec, v := range secs {
+							addSample(samples, min, sec, v)
+						}
+					}
+				}
+			}
+		}
+	}
+	if len(samples) > 0 {
+		trs := bucket{Timestamp: b.Timestamp, Samples: samples}.records(b.Timestamp, hourEnd)
+		Ascending.sort(trs)
+		return trs
+	}
+	// The hourly value of buckets with samples is their newest sample, already reported.
+	if v, ok := ev.UpdateDescription.UpdatedFields[valueField]; ok && len(b.Samples) == 0 {
+		return []TSRecord{{b.Timestamp, v}}
+	}
+	return nil
+}
+
+func addSample(samples map[string]map[string]interface{}, min, sec string, v interface{}) {
+	if samples[min] == nil {
+		samples[min] = make(map[string]interface{})
+	}
+	samples[min][sec] = v
+}
@@ -0,0 +1,150 @@
+package tsmongo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+func TestWatch_Polling(t *testing.T) {
+	defer func(d time.Duration) { PollInterval = d }(PollInterval)
+	PollInterval = 10 * time.Millisecond
+
+	s := NewMemStore()
+	base := time.Date(2018, 7, 1, 10, 0, 0, 0, time.UTC)
+	bs := NewBedroomService(s, DefaultRoom)
+	// Records written before the subscription must not be delivered.
+	bs.UpdateTemperature(base, 24)
+
+	w, err := bs.Watch(context.Background())
+	if err != nil {
+		t.Fatalf("Watch: %q", err)
+	}
+	bs.UpdateTemperature(base.Add(10*time.Second), 25)
+	bs.UpdateTemperature(base.Add(time.Hour), 26)
+
+	var got []BedroomState
+	for len(got) < 2 {
+		select {
+		case st := <-w.C:
+			got = append(got, st)
+		case <-time.After(time.Second):
+			t.Fatalf("Watch timed out, got:%v", got)
+		}
+	}
+	checkStates(t, []BedroomState{{base.Add(10 * time.Second), 25}, {base.Add(time.Hour), 26}}, got)
+
+	if err := w.Close(); err != nil {
+		t.Errorf("Close want:nil got:%q", err)
+	}
+	if _, ok := <-w.C; ok {
+		t.Errorf("Close must close the channel")
+	}
+}
+
+func TestWatch_PollingLateRecords(t *testing.T) {
+	defer func(d time.Duration) { PollInterval = d }(PollInterval)
+	PollInterval = 10 * time.Millisecond
+
+	s := NewMemStore()
+	base := time.Date(2018, 7, 1, 10, 0, 0, 0, time.UTC)
+	bs := NewBedroomService(s, DefaultRoom)
+	bs.UpdateTemperature(base, 24)
+	w, err := bs.Watch(context.Background())
+	if err != nil {
+		t.Fatalf("Watch: %q", err)
+	}
+	defer w.Close()
+	next := func() BedroomState {
+		select {
+		case st := <-w.C:
+			return st
+		case <-time.After(time.Second):
+			t.Fatalf("Watch timed out")
+		}
+		return BedroomState{}
+	}
+
+	bs.UpdateTemperature(base.Add(time.Hour), 26)
+	checkStates(t, []BedroomState{{base.Add(time.Hour), 26}}, []BedroomState{next()})
+	// Written after the newer record was delivered, e.g. replayed after an outage.
+	bs.UpdateTemperature(base.Add(30*time.Minute), 25)
+	checkStates(t, []BedroomState{{base.Add(30 * time.Minute), 25}}, []BedroomState{next()})
+	// Rewritten with a new value, e.g. corrected.
+	bs.UpdateTemperature(base, 23)
+	checkStates(t, []BedroomState{{base, 23}}, []BedroomState{next()})
+	// Rewritten with the same value.
+	bs.UpdateTemperature(base, 23)
+	select {
+	case st := <-w.C:
+		t.Errorf("Watch delivered an unchanged record: %+v", st)
+	case <-time.After(10 * PollInterval):
+	}
+}
+
+func TestWatch_ContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	w, err := NewFanService(NewMemStore(), DefaultRoom).Watch(ctx)
+	if err != nil {
+		t.Fatalf("Watch: %q", err)
+	}
+	cancel()
+	select {
+	case _, ok := <-w.C:
+		if ok {
+			t.Errorf("Watch delivered state after the context was canceled")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Watch did not stop after the context was canceled")
+	}
+	if err := w.Err(); err != nil {
+		t.Errorf("Err want:nil got:%q", err)
+	}
+}
+
+func TestChangeEvent_Records(t *testing.T) {
+	hour := time.Date(2018, 7, 1, 10, 0, 0, 0, time.UTC)
+	full := bucket{
+		Timestamp: hour,
+		Value:     26.0,
+		Samples:   map[string]map[string]interface{}{"0": {"10": 25.0}, "30": {"0": 26.0}},
+	}
+	testCases := []struct {
+		desc string
+		ev   changeEvent
+		want []TSRecord
+	}{
+		{"insert", changeEvent{OperationType: "insert", FullDocument: full},
+			[]TSRecord{{hour.Add(10 * time.Second), 25.0}, {hour.Add(30 * time.Minute), 26.0}}},
+		{"update sample", changeEvent{OperationType: "update", FullDocument: full, UpdateDescription: updateDescription(bson.M{"value": 26.0, "values.30.0": 26.0})},
+			[]TSRecord{{hour.Add(30 * time.Minute), 26.0}}},
+		{"update new minute", changeEvent{OperationType: "update", FullDocument: full, UpdateDescription: updateDescription(bson.M{"values.30": bson.M{"0": 26.0}})},
+			[]TSRecord{{hour.Add(30 * time.Minute), 26.0}}},
+		{"update hourly value of samples", changeEvent{OperationType: "update", FullDocument: full, UpdateDescription: updateDescription(bson.M{"value": 26.0})},
+			nil},
+		{"update hourly", changeEvent{OperationType: "update", FullDocument: bucket{Timestamp: hour, Value: 2}, UpdateDescription: updateDescription(bson.M{"value": 2})},
+			[]TSRecord{{hour, 2}}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := tc.ev.records()
+			if len(got) != len(tc.want) {
+				t.Fatalf("records want:%v got:%v", tc.want, got)
+			}
+			for i := range tc.want {
+				if !got[i].Timestamp.Equal(tc.want[i].Timestamp) || got[i].Value != tc.want[i].Value {
+					t.Errorf("records[%d] want:%+v got:%+v", i, tc.want[i], got[i])
+				}
+			}
+		})
+	}
+}
+
+func updateDescription(fields bson.M) (d struct {
+	UpdatedFields bson.M `bson:"updatedFields"`
+}) {
+	d.UpdatedFields = fields
+	return d
+}
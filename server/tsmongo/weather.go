@@ -1,10 +1,10 @@
 package tsmongo
 
 import (
+	"context"
 	"time"
 
 	"github.com/danielfireman/temp-to-go/server/weather"
-	"github.com/globalsign/mgo/bson"
 )
 
 const (
@@ -12,58 +12,109 @@ const (
 	forecastField = "forecast"
 )
 
+// Weather series are not bound to a room.
+var (
+	weatherSeries  = Series{Field: weatherField}
+	forecastSeries = Series{Field: forecastField}
+)
+
 // ForecastService allows the user to update and get information about the weather forecast.
 type ForecastService struct {
-	session *Session
+	store Store
 }
 
 // Update updates the database with the new information about the weather forecast. This call
-// assumes the weather.State.Timestamp is a future timestamp, so it overrides whichever information is
-// associated to it (it should be none).
+// assumes the weather.State.Timestamp is a future timestamp, so it overrides the latest forecast
+// associated to it. Previous forecasts are still kept as vintages, see UpdateIssued.
 func (wf *ForecastService) Update(states ...weather.State) error {
-	if len(states) == 0 {
-		return nil
-	}
+	return wf.UpdateContext(context.Background(), states...)
+}
+
+// UpdateContext is like Update, but honors the context deadline and cancellation.
+func (wf *ForecastService) UpdateContext(ctx context.Context, states ...weather.State) error {
+	return wf.UpdateIssuedContext(ctx, time.Now(), states...)
+}
+
+// updateLatest overrides the latest forecast of the hours of the states.
+func (wf *ForecastService) updateLatest(ctx context.Context, states ...weather.State) error {
 	trs := make([]TSRecord, len(states))
 	for i := range states {
 		trs[i] = TSRecord{states[i].Timestamp, states[i]}
 	}
-	return wf.session.Upsert(forecastField, trs...)
+	return wf.store.UpsertContext(ctx, forecastSeries, trs...)
 }
 
 // Fetch fetches a time range of weather forecast samples.
 func (wf *ForecastService) Fetch(start time.Time, finish time.Time) ([]weather.State, error) {
-	return query(wf.session, forecastField, start, finish)
+	return wf.FetchContext(context.Background(), start, finish)
+}
+
+// FetchContext is like Fetch, but honors the context deadline and cancellation.
+func (wf *ForecastService) FetchContext(ctx context.Context, start time.Time, finish time.Time) ([]weather.State, error) {
+	return query(ctx, wf.store, forecastSeries, start, finish)
 }
 
 // WeatherService allows the user to update and get information about the weather.
 type WeatherService struct {
-	session *Session
+	store Store
 }
 
 // Update updates the StatusDB with the new information about the current weather.
 func (w *WeatherService) Update(s weather.State) error {
-	return w.session.Upsert(weatherField, TSRecord{s.Timestamp, toStore(s)})
+	return w.UpdateContext(context.Background(), s)
+}
+
+// UpdateContext is like Update, but honors the context deadline and cancellation.
+func (w *WeatherService) UpdateContext(ctx context.Context, s weather.State) error {
+	return w.store.UpsertContext(ctx, weatherSeries, TSRecord{s.Timestamp, toStore(s)})
 }
 
 // Fetch fetches a time range of weather temperatures (which do not include forecasts).
 func (w *WeatherService) Fetch(start time.Time, finish time.Time) ([]weather.State, error) {
-	return query(w.session, weatherField, start, finish)
+	return w.FetchContext(context.Background(), start, finish)
+}
+
+// FetchContext is like Fetch, but honors the context deadline and cancellation.
+func (w *WeatherService) FetchContext(ctx context.Context, start time.Time, finish time.Time) ([]weather.State, error) {
+	return query(ctx, w.store, weatherSeries, start, finish)
 }
 
-func query(session *Session, field string, start time.Time, finish time.Time) ([]weather.State, error) {
-	trs, err := session.Query(field, start, finish)
+// Stream calls fn for each hourly weather state in the time range, in the specified order.
+// Unlike Fetch, states are not loaded into memory all at once.
+func (w *WeatherService) Stream(start time.Time, finish time.Time, order Order, fn func(weather.State) error) error {
+	return w.StreamContext(context.Background(), start, finish, order, fn)
+}
+
+// StreamContext is like Stream, but honors the context deadline and cancellation.
+func (w *WeatherService) StreamContext(ctx context.Context, start time.Time, finish time.Time, order Order, fn func(weather.State) error) error {
+	return w.store.StreamContext(ctx, weatherSeries, start, finish, Hourly, order, func(tr TSRecord) error {
+		var s weatherState
+		if err := decodeValue(tr.Value, &s); err != nil {
+			return err
+		}
+		return fn(fromStore(s, tr.Timestamp))
+	})
+}
+
+// Aggregate aggregates the weather temperature (Celsius) into time windows.
+func (w *WeatherService) Aggregate(q AggregateQuery) ([]AggregateBucket, error) {
+	return w.AggregateContext(context.Background(), q)
+}
+
+// AggregateContext is like Aggregate, but honors the context deadline and cancellation.
+func (w *WeatherService) AggregateContext(ctx context.Context, q AggregateQuery) ([]AggregateBucket, error) {
+	return w.store.AggregateContext(ctx, weatherSeries, "temp", q)
+}
+
+func query(ctx context.Context, store Store, series Series, start time.Time, finish time.Time) ([]weather.State, error) {
+	trs, err := store.QueryContext(ctx, series, start, finish)
 	if err != nil {
 		return nil, err
 	}
 	ret := make([]weather.State, len(trs))
 	for i := range trs {
-		b, err := bson.Marshal(trs[i].Value)
-		if err != nil {
-			return nil, err
-		}
 		var s weatherState
-		if err := bson.Unmarshal(b, &s); err != nil {
+		if err := decodeValue(trs[i].Value, &s); err != nil {
 			return nil, err
 		}
 		ret[i] = fromStore(s, trs[i].Timestamp)
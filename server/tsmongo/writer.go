@@ -0,0 +1,476 @@
+package tsmongo
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+)
+
+// ErrQueueFull is returned by Writer when its memory queue is full and there is no spill file.
+var ErrQueueFull = fmt.Errorf("Writer queue is full")
+
+// ErrWriterClosed is returned by Writer writes after it has been shut down.
+var ErrWriterClosed = fmt.Errorf("Writer is closed")
+
+// WriterOptions configures a Writer. Zero values are replaced by defaults.
+type WriterOptions struct {
+	// BatchSize is the maximum number of records upserted at once. Defaults to 100.
+	BatchSize int
+	// MaxQueue is the maximum number of records queued in memory. Defaults to 10000.
+	MaxQueue int
+	// SpillPath, if not empty, is the file records are spilled to when the memory queue is
+	// full, and when the writer is shut down before writing them. Records spilled by a
+	// previous process are written when the writer is created.
+	SpillPath string
+	// MinBackoff and MaxBackoff bound the time waited before retrying a batch which failed
+	// with a transient error (e.g. the database is unreachable), which doubles at each retry.
+	// They default to 100ms and 30s.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// WriteTimeout is the deadline of each batch upsert. Defaults to 10s.
+	WriteTimeout time.Duration
+	// ShutdownTimeout is how long Close waits for queued records to be written. Defaults to 10s.
+	ShutdownTimeout time.Duration
+	// OnError, if not nil, is called with the errors of batches dropped because of
+	// non-transient errors (e.g. invalid values).
+	OnError func(error)
+}
+
+func (o *WriterOptions) setDefaults() {
+	if o.BatchSize <= 0 {
+		o.BatchSize = 100
+	}
+	if o.MaxQueue <= 0 {
+		o.MaxQueue = 10000
+	}
+	if o.MinBackoff <= 0 {
+		o.MinBackoff = 100 * time.Millisecond
+	}
+	if o.MaxBackoff < o.MinBackoff {
+		o.MaxBackoff = 30 * time.Second
+	}
+	if o.WriteTimeout <= 0 {
+		o.WriteTimeout = 10 * time.Second
+	}
+	if o.ShutdownTimeout <= 0 {
+		o.ShutdownTimeout = 10 * time.Second
+	}
+}
+
+// queuedRecord is a record waiting to be written.
+type queuedRecord struct {
+	series Series
+	sample bool
+	TSRecord
+}
+
+// Writer is a Store which queues upserts and writes them in background, batching them through
+// the bulk upsert path of the underlying store and retrying them with exponential backoff on
+// transient errors, so short database outages do not lose data. Queries go straight to the
+// underlying store, so records still queued are not returned by them.
+type Writer struct {
+	Store
+	opts WriterOptions
+
+	mu        sync.Mutex // Protects the fields below.
+	queue     []queuedRecord
+	spill     *os.File
+	spillRead int64 // Offset of the first spilled record not loaded into the queue.
+	spilled   int   // Number of spilled records not loaded into the queue.
+	closed    bool
+	drained   chan struct{} // Closed (and replaced) whenever the queue depth decreases.
+
+	wake chan struct{}
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewWriter creates a Writer on top of the store and starts writing in background.
+func NewWriter(s Store, opts WriterOptions) (*Writer, error) {
+	opts.setDefaults()
+	w := &Writer{
+		Store:   s,
+		opts:    opts,
+		drained: make(chan struct{}),
+		wake:    make(chan struct{}, 1),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	if opts.SpillPath != "" {
+		f, err := os.OpenFile(opts.SpillPath, os.O_RDWR|os.O_CREATE, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("Error opening tsmongo spill file %s: %q", opts.SpillPath, err)
+		}
+		w.spill = f
+		// Counting the records spilled by a previous process.
+		if err := w.readSpill(-1, func(queuedRecord) { w.spilled++ }); err != nil {
+			f.Close()
+			return nil, err
+		}
+		w.spillRead = 0
+	}
+	go w.run()
+	return w, nil
+}
+
+// UpsertContext queues the records to be upserted. It returns ErrQueueFull if the queue is full.
+func (w *Writer) UpsertContext(ctx context.Context, series Series, val ...TSRecord) error {
+	return w.enqueue(ctx, series, false, val)
+}
+
+// UpsertSamplesContext queues the records to be upserted as samples. It returns ErrQueueFull if
+// the queue is full.
+func (w *Writer) UpsertSamplesContext(ctx context.Context, series Series, val ...TSRecord) error {
+	return w.enqueue(ctx, series, true, val)
+}
+
+func (w *Writer) enqueue(ctx context.Context, series Series, sample bool, val []TSRecord) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if len(val) == 0 {
+		return nil
+	}
+	recs := make([]queuedRecord, len(val))
+	for i := range val {
+		recs[i] = queuedRecord{series, sample, val[i]}
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return ErrWriterClosed
+	}
+	// Once records are spilled, newer ones are spilled as well, so they are written in order.
+	if w.spilled > 0 || len(w.queue)+len(recs) > w.opts.MaxQueue {
+		if w.spill == nil {
+			return ErrQueueFull
+		}
+		if err := w.appendSpill(recs); err != nil {
+			return err
+		}
+	} else {
+		w.queue = append(w.queue, recs...)
+	}
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// QueueDepth returns the number of records waiting to be written, both in memory and spilled.
+func (w *Writer) QueueDepth() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.queue) + w.spilled
+}
+
+// Flush waits until all queued records are written (or dropped), or the context is done.
+func (w *Writer) Flush(ctx context.Context) error {
+	for {
+		w.mu.Lock()
+		depth, drained := len(w.queue)+w.spilled, w.drained
+		w.mu.Unlock()
+		if depth == 0 {
+			return nil
+		}
+		select {
+		case <-drained:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Shutdown stops accepting records and flushes the queue until the context is done. Records
+// which could not be written are spilled, to be written by the next writer using the same
+// spill file. It returns an error if records were lost.
+func (w *Writer) Shutdown(ctx context.Context) error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	w.mu.Unlock()
+
+	flushErr := w.Flush(ctx)
+	close(w.stop)
+	<-w.done
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.spill == nil {
+		if n := len(w.queue); n > 0 {
+			return fmt.Errorf("Error flushing tsmongo writer, %d records were lost: %q", n, flushErr)
+		}
+		return nil
+	}
+	defer w.spill.Close()
+	if len(w.queue) > 0 {
+		if err := w.prependSpill(w.queue); err != nil {
+			return fmt.Errorf("Error flushing tsmongo writer, %d records were lost: %q", len(w.queue), err)
+		}
+	}
+	return nil
+}
+
+// Close shuts the writer down, waiting up to the shutdown timeout, and closes the underlying
+// store.
+func (w *Writer) Close() {
+	ctx, cancel := context.WithTimeout(context.Background(), w.opts.ShutdownTimeout)
+	defer cancel()
+	w.Shutdown(ctx)
+	w.Store.Close()
+}
+
+// run writes the queued records until the writer is stopped.
+func (w *Writer) run() {
+	defer close(w.done)
+	backoff := w.opts.MinBackoff
+	for {
+		batch, err := w.next()
+		if err != nil {
+			w.report(err)
+		}
+		if len(batch) == 0 {
+			select {
+			case <-w.wake:
+				continue
+			case <-w.stop:
+				return
+			}
+		}
+		err = w.write(batch)
+		if err != nil && isTransient(err) {
+			select {
+			case <-time.After(backoff):
+			case <-w.stop:
+				return
+			}
+			if backoff *= 2; backoff > w.opts.MaxBackoff {
+				backoff = w.opts.MaxBackoff
+			}
+			continue
+		}
+		backoff = w.opts.MinBackoff
+		if err != nil {
+			w.report(fmt.Errorf("Error writing tsmongo series %q, dropping %d records: %q", batch[0].series, len(batch), err))
+		}
+		w.mu.Lock()
+		w.queue = w.queue[len(batch):]
+		close(w.drained)
+		w.drained = make(chan struct{})
+		w.mu.Unlock()
+	}
+}
+
+// next returns the next batch to be written: the records at the head of the queue with the same
+// series and kind. Spilled records are loaded into the queue as it empties.
+func (w *Writer) next() ([]queuedRecord, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var err error
+	if w.spilled > 0 && len(w.queue) < w.opts.MaxQueue/2 {
+		err = w.readSpill(w.opts.MaxQueue-len(w.queue), func(r queuedRecord) {
+			w.queue = append(w.queue, r)
+			w.spilled--
+		})
+	}
+	if len(w.queue) == 0 {
+		if w.spill != nil && w.spilled == 0 && w.spillRead > 0 {
+			// Every spilled record was written.
+			if err := w.spill.Truncate(0); err == nil {
+				w.spillRead = 0
+			}
+		}
+		return nil, err
+	}
+	head := w.queue[0]
+	n := 1
+	for n < len(w.queue) && n < w.opts.BatchSize && w.queue[n].series.key() == head.series.key() && w.queue[n].sample == head.sample {
+		n++
+	}
+	return w.queue[:n:n], err
+}
+
+func (w *Writer) write(batch []queuedRecord) error {
+	ctx, cancel := context.WithTimeout(context.Background(), w.opts.WriteTimeout)
+	defer cancel()
+	trs := make([]TSRecord, len(batch))
+	for i := range batch {
+		trs[i] = batch[i].TSRecord
+	}
+	if batch[0].sample {
+		return w.Store.UpsertSamplesContext(ctx, batch[0].series, trs...)
+	}
+	return w.Store.UpsertContext(ctx, batch[0].series, trs...)
+}
+
+func (w *Writer) report(err error) {
+	if w.opts.OnError != nil {
+		w.opts.OnError(err)
+	}
+}
+
+// appendSpill appends the records to the spill file. Callers must hold w.mu.
+func (w *Writer) appendSpill(recs []queuedRecord) error {
+	buf, err := encodeSpill(recs)
+	if err != nil {
+		return err
+	}
+	end, err := w.spill.Seek(0, io.SeekEnd)
+	if err != nil {
+		return fmt.Errorf("Error spilling tsmongo records: %q", err)
+	}
+	if _, err := w.spill.WriteAt(buf, end); err != nil {
+		return fmt.Errorf("Error spilling tsmongo records: %q", err)
+	}
+	w.spilled += len(recs)
+	return nil
+}
+
+// prependSpill rewrites the spill file with the records before the spilled records which were
+// not loaded yet. Callers must hold w.mu.
+func (w *Writer) prependSpill(recs []queuedRecord) error {
+	buf, err := encodeSpill(recs)
+	if err != nil {
+		return err
+	}
+	info, err := w.spill.Stat()
+	if err != nil {
+		return err
+	}
+	rest := make([]byte, info.Size()-w.spillRead)
+	if _, err := w.spill.ReadAt(rest, w.spillRead); err != nil && err != io.EOF {
+		return err
+	}
+	tmp := w.opts.SpillPath + ".tmp"
+	if err := writeFileSync(tmp, append(buf, rest...)); err != nil {
+		return err
+	}
+	return os.Rename(tmp, w.opts.SpillPath)
+}
+
+func writeFileSync(path string, b []byte) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// encodeSpill encodes the records as the data file records of FileStore.
+func encodeSpill(recs []queuedRecord) ([]byte, error) {
+	var buf []byte
+	for _, r := range recs {
+		b, err := bson.Marshal(fileRecord{Type: r.series.Field, Room: r.series.Room, Tags: r.series.Tags, Timestamp: r.Timestamp, Value: r.Value, Sample: r.sample})
+		if err != nil {
+			return nil, fmt.Errorf("Error spilling tsmongo records: %q", err)
+		}
+		buf = append(buf, b...)
+	}
+	return buf, nil
+}
+
+// readSpill reads up to max (all, if negative) spilled records from w.spillRead, moving it
+// forward. A truncated trailing record (e.g. a write interrupted by a crash) is ignored.
+// Callers must hold w.mu.
+func (w *Writer) readSpill(max int, fn func(queuedRecord)) error {
+	info, err := w.spill.Stat()
+	if err != nil {
+		return fmt.Errorf("Error reading tsmongo spill file: %q", err)
+	}
+	r := bufio.NewReader(io.NewSectionReader(w.spill, w.spillRead, info.Size()-w.spillRead))
+	for n := 0; max < 0 || n < max; n++ {
+		var size [4]byte
+		if _, err := io.ReadFull(r, size[:]); err != nil {
+			return nil
+		}
+		l := int(binary.LittleEndian.Uint32(size[:]))
+		if l < len(size) {
+			return fmt.Errorf("Error reading tsmongo spill file: corrupted at offset %d", w.spillRead)
+		}
+		doc := make([]byte, l)
+		copy(doc, size[:])
+		if _, err := io.ReadFull(r, doc[len(size):]); err != nil {
+			return nil
+		}
+		var rec fileRecord
+		if err := bson.Unmarshal(doc, &rec); err != nil {
+			return fmt.Errorf("Error reading tsmongo spill file: corrupted at offset %d: %q", w.spillRead, err)
+		}
+		fn(queuedRecord{Series{rec.Type, rec.Room, rec.Tags}, rec.Sample, TSRecord{rec.Timestamp, rec.Value}})
+		w.spillRead += int64(l)
+	}
+	return nil
+}
+
+// isTransient returns whether the error is likely to go away by retrying the operation (e.g.
+// the database is unreachable or a replica set is electing a new primary).
+func isTransient(err error) bool {
+	if err == context.DeadlineExceeded || err == io.EOF || err == io.ErrUnexpectedEOF {
+		return true
+	}
+	if _, ok := err.(net.Error); ok {
+		return true
+	}
+	switch e := err.(type) {
+	case *UnreachableError:
+		return true
+	case *mgo.LastError:
+		return transientCodes[e.Code]
+	case *mgo.QueryError:
+		return transientCodes[e.Code]
+	case *mgo.BulkError:
+		for _, c := range e.Cases() {
+			if !isTransient(c.Err) {
+				return false
+			}
+		}
+		return true
+	}
+	msg := err.Error()
+	for _, s := range transientMessages {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// transientCodes are the codes of MongoDB errors caused by the server state, instead of by the
+// operation (e.g. HostUnreachable, NotMaster and PrimarySteppedDown).
+var transientCodes = map[int]bool{
+	6: true, 7: true, 89: true, 91: true, 189: true, 262: true, 9001: true,
+	10107: true, 11600: true, 11602: true, 13435: true, 13436: true,
+}
+
+// transientMessages are found in errors returned by mgo when the server can not be reached.
+var transientMessages = []string{
+	"no reachable servers",
+	"Closed explicitly",
+	"connection reset",
+	"connection refused",
+	"broken pipe",
+	"i/o timeout",
+	"not master",
+}
@@ -0,0 +1,185 @@
+package tsmongo
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// flakyStore fails writes while down, as an unreachable database would.
+type flakyStore struct {
+	*MemStore
+	mu      sync.Mutex
+	down    bool
+	err     error
+	batches int
+}
+
+func (f *flakyStore) setDown(down bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.down = down
+}
+
+func (f *flakyStore) check() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.down {
+		return f.err
+	}
+	f.batches++
+	return nil
+}
+
+func (f *flakyStore) UpsertContext(ctx context.Context, series Series, val ...TSRecord) error {
+	if err := f.check(); err != nil {
+		return err
+	}
+	return f.MemStore.UpsertContext(ctx, series, val...)
+}
+
+func (f *flakyStore) UpsertSamplesContext(ctx context.Context, series Series, val ...TSRecord) error {
+	if err := f.check(); err != nil {
+		return err
+	}
+	return f.MemStore.UpsertSamplesContext(ctx, series, val...)
+}
+
+func newFlakyStore() *flakyStore {
+	return &flakyStore{MemStore: NewMemStore(), err: errors.New("no reachable servers")}
+}
+
+var fastRetries = WriterOptions{MinBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond}
+
+func TestWriter(t *testing.T) {
+	s := newFlakyStore()
+	s.setDown(true)
+	opts := fastRetries
+	opts.MaxQueue = 10
+	w, err := NewWriter(s, opts)
+	if err != nil {
+		t.Fatalf("NewWriter: %q", err)
+	}
+	base := time.Date(2018, 7, 1, 10, 0, 0, 0, time.UTC)
+	bs := NewBedroomService(w, DefaultRoom)
+	for i := 0; i < 10; i++ {
+		if err := bs.UpdateTemperature(base.Add(time.Duration(i)*time.Second), float64(20+i)); err != nil {
+			t.Fatalf("UpdateTemperature: %q", err)
+		}
+	}
+	if err := bs.UpdateTemperature(base.Add(time.Minute), 30); err != ErrQueueFull {
+		t.Errorf("UpdateTemperature on full queue want:%q got:%q", ErrQueueFull, err)
+	}
+	if d := w.QueueDepth(); d != 10 {
+		t.Errorf("QueueDepth want:10 got:%d", d)
+	}
+
+	// Records are written, batched, once the database is back.
+	s.setDown(false)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := w.Flush(ctx); err != nil {
+		t.Fatalf("Flush: %q", err)
+	}
+	if d := w.QueueDepth(); d != 0 {
+		t.Errorf("QueueDepth want:0 got:%d", d)
+	}
+	if s.batches != 1 {
+		t.Errorf("batches want:1 got:%d", s.batches)
+	}
+	got, err := bs.FetchState(base, base.Add(time.Hour), FullResolution)
+	if err != nil || len(got) != 10 {
+		t.Errorf("FetchState want 10 samples got:%+v (%v)", got, err)
+	}
+
+	if err := w.Shutdown(ctx); err != nil {
+		t.Errorf("Shutdown: %q", err)
+	}
+	if err := bs.UpdateTemperature(base, 20); err != ErrWriterClosed {
+		t.Errorf("UpdateTemperature after shutdown want:%q got:%q", ErrWriterClosed, err)
+	}
+}
+
+func TestWriterDropsInvalidRecords(t *testing.T) {
+	s := newFlakyStore()
+	s.err = errors.New("invalid value")
+	s.setDown(true)
+	var dropped []error
+	opts := fastRetries
+	opts.OnError = func(err error) { dropped = append(dropped, err) }
+	w, err := NewWriter(s, opts)
+	if err != nil {
+		t.Fatalf("NewWriter: %q", err)
+	}
+	defer w.Close()
+	w.UpsertSamplesContext(context.Background(), bedroomSeries, TSRecord{time.Now(), 20.0})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := w.Flush(ctx); err != nil {
+		t.Fatalf("Flush: %q", err)
+	}
+	if len(dropped) != 1 {
+		t.Errorf("dropped batches want:1 got:%v", dropped)
+	}
+}
+
+func TestWriterSpill(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "writer_testing")
+	defer os.RemoveAll(dir)
+	s := newFlakyStore()
+	s.setDown(true)
+	opts := fastRetries
+	opts.MaxQueue = 2
+	opts.SpillPath = filepath.Join(dir, "spill")
+	w, err := NewWriter(s, opts)
+	if err != nil {
+		t.Fatalf("NewWriter: %q", err)
+	}
+	base := time.Date(2018, 7, 1, 10, 0, 0, 0, time.UTC)
+	fs := NewFanService(w, DefaultRoom)
+	for i := 0; i < 5; i++ {
+		if err := fs.UpdateStatus(base.Add(time.Duration(i)*time.Minute), FanStatus(i%3)); err != nil {
+			t.Fatalf("UpdateStatus: %q", err)
+		}
+	}
+	if d := w.QueueDepth(); d != 5 {
+		t.Errorf("QueueDepth want:5 got:%d", d)
+	}
+	// The database is still down at shutdown, every record must be spilled.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := w.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown: %q", err)
+	}
+
+	// A new writer picks the spilled records up.
+	s.setDown(false)
+	w, err = NewWriter(s, opts)
+	if err != nil {
+		t.Fatalf("NewWriter: %q", err)
+	}
+	if d := w.QueueDepth(); d != 5 {
+		t.Errorf("QueueDepth after restart want:5 got:%d", d)
+	}
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := w.Flush(ctx); err != nil {
+		t.Fatalf("Flush: %q", err)
+	}
+	got, err := fs.FetchState(base, base.Add(time.Hour), FullResolution)
+	if err != nil {
+		t.Fatalf("FetchState: %q", err)
+	}
+	if len(got) != 5 || got[0].Status != FanStatus(4%3) || !got[0].Timestamp.Equal(base.Add(4*time.Minute)) {
+		t.Errorf("FetchState want 5 states got:%+v", got)
+	}
+	w.Close()
+	if info, err := os.Stat(opts.SpillPath); err != nil || info.Size() != 0 {
+		t.Errorf("spill file want empty got:%v (%v)", info, err)
+	}
+}
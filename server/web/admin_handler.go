@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/danielfireman/temp-to-go/server/tsmongo"
+	"github.com/labstack/echo"
+)
+
+const (
+	// Query parameters of the admin pages.
+	fieldParam = "field"
+	hoursParam = "hours"
+
+	defaultStatsHours = 24
+)
+
+type adminHandler struct {
+}
+
+// inventory summarizes the series within the last hours, optionally filtered by field and room.
+func (h *adminHandler) inventory(c echo.Context) ([]tsmongo.SeriesStats, int, error) {
+	hours := defaultStatsHours
+	if v := c.QueryParam(hoursParam); v != "" {
+		var err error
+		if hours, err = strconv.Atoi(v); err != nil || hours <= 0 {
+			return nil, 0, errInvalidHours
+		}
+	}
+	now := time.Now()
+	q := tsmongo.StatsQuery{Start: now.Add(-time.Duration(hours) * time.Hour), Finish: now, Now: now}
+	stats, err := tsmongo.Inventory(c.Request().Context(), requestStore(c), c.QueryParam(fieldParam), q)
+	if err != nil {
+		return nil, 0, err
+	}
+	room := c.QueryParam(roomParam)
+	if room == "" {
+		return stats, hours, nil
+	}
+	var ret []tsmongo.SeriesStats
+	for _, s := range stats {
+		if s.Series.Room == room {
+			ret = append(ret, s)
+		}
+	}
+	return ret, hours, nil
+}
+
+var errInvalidHours = fmt.Errorf("Invalid hours")
+
+// handle renders the stats of the stored series.
+func (h *adminHandler) handle(c echo.Context) error {
+	stats, hours, err := h.inventory(c)
+	switch err {
+	case nil:
+		return c.Render(http.StatusOK, "admin", struct {
+			Hours int
+			Stats []tsmongo.SeriesStats
+		}{hours, stats})
+	case errInvalidHours:
+		return c.NoContent(http.StatusBadRequest)
+	default:
+		c.Logger().Errorf("[/restricted/admin] %q\n", err)
+		return c.NoContent(storeErrorStatus(err))
+	}
+}
+
+// handleStats returns the stats of the stored series.
+func (h *adminHandler) handleStats(c echo.Context) error {
+	stats, _, err := h.inventory(c)
+	switch err {
+	case nil:
+		return c.JSON(http.StatusOK, stats)
+	case errInvalidHours:
+		return c.NoContent(http.StatusBadRequest)
+	default:
+		c.Logger().Errorf("[/restricted/admin/stats] %q\n", err)
+		return c.NoContent(storeErrorStatus(err))
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/danielfireman/temp-to-go/server/tsmongo"
+	"github.com/labstack/echo"
+)
+
+const (
+	annotationsPath = "/restricted/annotations"
+	// Form parameters of new annotations. Times are in the "2006-01-02T15:04" format (as sent
+	// by datetime-local inputs), in the server timezone.
+	startParam     = "start"
+	finishParam    = "finish"
+	textParam      = "text"
+	formTimeLayout = "2006-01-02T15:04"
+
+	// annotationHours is how far back annotations are shown, matching the charts.
+	annotationHours = 25
+)
+
+type annotationHandler struct {
+}
+
+// handleGet returns the annotations of the room overlapping the last 25 hours, along with the
+// hour (in the timezone of the request) they are shown at on the charts.
+func (h *annotationHandler) handleGet(c echo.Context) error {
+	start := time.Now().Add(-annotationHours * time.Hour)
+	as, err := tsmongo.NewAnnotationService(requestStore(c), c.QueryParam(roomParam)).AnnotationsContext(c.Request().Context(), start, time.Now())
+	if err != nil {
+		c.Logger().Errorf("[/restricted/annotations] %q\n", err)
+		return c.NoContent(storeErrorStatus(err))
+	}
+	loc, err := time.LoadLocation(c.Request().Header.Get(timezoneHeader))
+	if err != nil {
+		c.Logger().Error(err)
+		loc = time.UTC
+	}
+	resp := make([]annotationResponse, len(as))
+	for i, a := range as {
+		t := a.Start
+		if t.Before(start) {
+			t = start
+		}
+		resp[i] = annotationResponse{a, t.In(loc).Format("3pm")}
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+type annotationResponse struct {
+	tsmongo.Annotation
+	Hour string `json:"hour"`
+}
+
+// handlePost annotates the room, by the logged in user.
+func (h *annotationHandler) handlePost(c echo.Context) error {
+	start, err := time.ParseInLocation(formTimeLayout, c.FormValue(startParam), time.Local)
+	if err != nil {
+		return c.NoContent(http.StatusBadRequest)
+	}
+	finish := start
+	if v := c.FormValue(finishParam); v != "" {
+		if finish, err = time.ParseInLocation(formTimeLayout, v, time.Local); err != nil {
+			return c.NoContent(http.StatusBadRequest)
+		}
+	}
+	a := tsmongo.Annotation{Start: start, Finish: finish, Text: c.FormValue(textParam), Actor: sessionUser(c)}
+	switch err := tsmongo.NewAnnotationService(requestStore(c), c.FormValue(roomParam)).AnnotateContext(c.Request().Context(), a); err {
+	case nil:
+		return c.Redirect(http.StatusFound, restrictedPath)
+	case tsmongo.ErrInvalidAnnotation:
+		return c.NoContent(http.StatusBadRequest)
+	default:
+		c.Logger().Errorf("[/restricted/annotations] %q\n", err)
+		return c.NoContent(storeErrorStatus(err))
+	}
+}
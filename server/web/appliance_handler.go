@@ -0,0 +1,91 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/danielfireman/temp-to-go/server/tsmongo"
+	"github.com/labstack/echo"
+)
+
+const (
+	appliancePath = "/restricted/appliance"
+	// Query (or form) parameters of the appliance API.
+	kindParam     = "kind"
+	onParam       = "on"
+	speedParam    = "speed"
+	setpointParam = "setpoint"
+	modeParam     = "mode"
+)
+
+type applianceHandler struct {
+}
+
+func (h *applianceHandler) service(c echo.Context) (*tsmongo.ApplianceService, error) {
+	return tsmongo.NewApplianceService(requestStore(c), tsmongo.ApplianceKind(c.FormValue(kindParam)), c.FormValue(roomParam))
+}
+
+// handleGet returns the capabilities and the last state of the appliance.
+func (h *applianceHandler) handleGet(c echo.Context) error {
+	as, err := h.service(c)
+	if err != nil {
+		return c.NoContent(http.StatusBadRequest)
+	}
+	s, err := as.LastStateContext(c.Request().Context())
+	if err != nil {
+		c.Logger().Errorf("[/restricted/appliance] %q\n", err)
+		return c.NoContent(storeErrorStatus(err))
+	}
+	return c.JSON(http.StatusOK, applianceResponse{as.Kind(), as.Room(), as.Capabilities(), s})
+}
+
+// handlePost changes the state of the appliance, on behalf of the logged in user.
+func (h *applianceHandler) handlePost(c echo.Context) error {
+	as, err := h.service(c)
+	if err != nil {
+		return c.NoContent(http.StatusBadRequest)
+	}
+	s := tsmongo.ApplianceState{
+		Timestamp: time.Now(),
+		Mode:      tsmongo.Mode(c.FormValue(modeParam)),
+		FanChange: tsmongo.FanChange{
+			Source: tsmongo.FanSourceWeb,
+			Actor:  sessionUser(c),
+			Reason: c.FormValue(fanReasonFieldName),
+		},
+	}
+	if v := c.FormValue(onParam); v != "" {
+		if s.On, err = strconv.ParseBool(v); err != nil {
+			return c.NoContent(http.StatusBadRequest)
+		}
+	}
+	// The speed is ignored when turning the appliance off.
+	if v := c.FormValue(speedParam); v != "" && s.On {
+		if s.Speed, err = strconv.Atoi(v); err != nil {
+			return c.NoContent(http.StatusBadRequest)
+		}
+	}
+	if v := c.FormValue(setpointParam); v != "" {
+		if s.Setpoint, err = strconv.ParseFloat(v, 64); err != nil {
+			return c.NoContent(http.StatusBadRequest)
+		}
+	}
+	switch err := as.UpdateStateContext(c.Request().Context(), s); err {
+	case nil:
+		return c.Redirect(http.StatusFound, restrictedPath)
+	case tsmongo.ErrInvalidApplianceState:
+		c.Logger().Errorf("[/restricted/appliance] Invalid %s state: %+v\n", as.Kind(), s)
+		return c.NoContent(http.StatusBadRequest)
+	default:
+		c.Logger().Errorf("[/restricted/appliance] %q\n", err)
+		return c.NoContent(storeErrorStatus(err))
+	}
+}
+
+type applianceResponse struct {
+	Kind         tsmongo.ApplianceKind  `json:"kind"`
+	Room         string                 `json:"room"`
+	Capabilities tsmongo.Capabilities   `json:"capabilities"`
+	State        tsmongo.ApplianceState `json:"state"`
+}
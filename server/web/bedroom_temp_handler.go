@@ -3,6 +3,7 @@ package main
 import (
 	"crypto/aes"
 	"crypto/cipher"
+	"encoding/json"
 	"errors"
 	"io/ioutil"
 	"math"
@@ -14,9 +15,17 @@ import (
 	"github.com/labstack/echo"
 )
 
+// roomParam is the query (or form) parameter selecting the room handlers act on. Requests
+// without it act on tsmongo.DefaultRoom.
+const roomParam = "room"
+
 type bedroomAPIHandler struct {
-	key            []byte
-	bedroomService *tsmongo.BedroomService
+	key   []byte
+	store tsmongo.Store
+}
+
+func (h *bedroomAPIHandler) bedroomService(c echo.Context) *tsmongo.BedroomService {
+	return tsmongo.NewBedroomService(h.store, c.QueryParam(roomParam))
 }
 
 func (h *bedroomAPIHandler) handlePost(c echo.Context) error {
@@ -32,22 +41,54 @@ func (h *bedroomAPIHandler) handlePost(c echo.Context) error {
 		c.Logger().Errorf("Error decrypting request body: %q", err)
 		return c.NoContent(http.StatusForbidden)
 	}
-	temp, err := strconv.ParseFloat(string(d), 64)
-	if err != nil {
-		c.Logger().Errorf("Error request body: %q", err)
+	// Plain numbers are temperatures, sent by sensors which only measure it.
+	if temp, err := strconv.ParseFloat(string(d), 64); err == nil {
+		switch err := h.bedroomService(c).UpdateTemperatureContext(c.Request().Context(), time.Now(), temp); err {
+		case nil:
+			return nil
+		case tsmongo.ErrQueueFull:
+			c.Logger().Errorf("StoreBedroomTemperature: %q\n", err)
+			return c.NoContent(http.StatusServiceUnavailable)
+		default:
+			c.Logger().Errorf("StoreBedroomTemperature: %q\n", err)
+			return c.NoContent(storeErrorStatus(err))
+		}
+	}
+	var req sensorRequest
+	if err := json.Unmarshal(d, &req); err != nil || len(req.Readings) == 0 {
+		c.Logger().Errorf("Error request body: %q", d)
 		return c.NoContent(http.StatusBadRequest)
 	}
-	if err := h.bedroomService.UpdateTemperature(time.Now(), temp); err != nil {
-		c.Logger().Errorf("StoreBedroomTemperature: %q\n", err)
-		return c.NoContent(http.StatusInternalServerError)
+	sensorService := tsmongo.NewSensorService(h.store, c.QueryParam(roomParam))
+	switch err := sensorService.UpdateContext(c.Request().Context(), time.Now(), req.Readings...); err {
+	case nil:
+		return nil
+	case tsmongo.ErrInvalidMetric:
+		c.Logger().Errorf("Error request body: %q", d)
+		return c.NoContent(http.StatusBadRequest)
+	case tsmongo.ErrQueueFull:
+		c.Logger().Errorf("StoreSensorReadings: %q\n", err)
+		return c.NoContent(http.StatusServiceUnavailable)
+	default:
+		c.Logger().Errorf("StoreSensorReadings: %q\n", err)
+		return c.NoContent(storeErrorStatus(err))
 	}
-	return nil
+}
+
+// sensorRequest is sent by sensors which measure many metrics, for instance:
+//
+//	{"readings": [{"metric": "temperature", "value": 25.1}, {"metric": "co2", "unit": "ppm", "value": 600}]}
+//
+// The unit can be omitted for the metrics known by tsmongo.
+type sensorRequest struct {
+	Readings []tsmongo.SensorReading `json:"readings"`
 }
 
 func (h *bedroomAPIHandler) handleGet(c echo.Context) error {
-	bs, err := h.bedroomService.FetchState(time.Now().Add(-25*time.Hour), time.Now())
+	bs, err := tsmongo.NewBedroomService(requestStore(c), c.QueryParam(roomParam)).FetchStateContext(c.Request().Context(), time.Now().Add(-25*time.Hour), time.Now(), tsmongo.Hourly)
 	if err != nil {
-		return c.NoContent(http.StatusInternalServerError)
+		c.Logger().Errorf("[/restricted/indoortemp] %q\n", err)
+		return c.NoContent(storeErrorStatus(err))
 	}
 	loc, err := time.LoadLocation(c.Request().Header.Get(timezoneHeader))
 	if err != nil {
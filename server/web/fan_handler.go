@@ -12,10 +12,10 @@ import (
 const (
 	fanPath            = "/restricted/fan"
 	fanStatusFieldName = "fanStatus"
+	fanReasonFieldName = "reason"
 )
 
 type fanHandler struct {
-	fanService *tsmongo.FanService
 }
 
 // FanHandlerFunc is a handler for APIs relate to the fan.
@@ -26,7 +26,12 @@ func (h *fanHandler) handle(c echo.Context) error {
 		return c.NoContent(http.StatusBadRequest)
 	}
 	s := tsmongo.FanStatus(byte(i))
-	switch h.fanService.UpdateStatus(time.Now(), s) {
+	change := tsmongo.FanChange{
+		Source: tsmongo.FanSourceWeb,
+		Actor:  sessionUser(c),
+		Reason: c.FormValue(fanReasonFieldName),
+	}
+	switch err := tsmongo.NewFanService(requestStore(c), c.FormValue(roomParam)).UpdateStatusByContext(c.Request().Context(), time.Now(), s, change); err {
 	case nil:
 		return c.Redirect(http.StatusFound, restrictedPath)
 	case tsmongo.ErrInvalidFanStatus:
@@ -34,6 +39,6 @@ func (h *fanHandler) handle(c echo.Context) error {
 		return c.NoContent(http.StatusBadRequest)
 	default:
 		c.Logger().Errorf("[/restricted/fan] %q\n", err)
-		return c.NoContent(http.StatusInternalServerError)
+		return c.NoContent(storeErrorStatus(err))
 	}
 }
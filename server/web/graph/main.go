@@ -15,6 +15,12 @@ type tempResponse struct {
 	Temp []float64 `js:"temp"`
 }
 
+type annotationResponse struct {
+	*js.Object
+	Hour string `js:"hour"`
+	Text string `js:"text"`
+}
+
 const timezoneHeader = "TZ"
 
 func main() {
@@ -44,8 +50,30 @@ func main() {
 	}
 	bs := &tempResponse{Object: reqBS.Response}
 
+	reqAnn := xhr.NewRequest("GET", "/restricted/annotations")
+	reqAnn.Timeout = 5000
+	reqAnn.SetRequestHeader("Content-Type", "application/json")
+	reqAnn.SetRequestHeader("TZ", tz)
+	reqAnn.ResponseType = xhr.JSON
+	if err := reqAnn.Send(nil); err != nil {
+		println(err)
+		return
+	}
+
+	// Annotated hours are labeled with the annotation text.
+	labels := ws.Hour
+	for i := 0; i < reqAnn.Response.Length(); i++ {
+		a := &annotationResponse{Object: reqAnn.Response.Index(i)}
+		for j := range labels {
+			if labels[j] == a.Hour {
+				labels[j] += " (" + a.Text + ")"
+				break
+			}
+		}
+	}
+
 	chartData := charts.NewChartData()
-	chartData.Labels = ws.Hour
+	chartData.Labels = labels
 	chartData.SpecificValues = []*charts.SpecificValue{charts.NewSpecificValue("", "solid", 0)} // Workaround to set the minimum value: https://github.com/frappe/charts/issues/86
 	chartData.Datasets = []*charts.Dataset{
 		charts.NewDataset(
@@ -1,11 +1,16 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
 	"path/filepath"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/danielfireman/temp-to-go/server/tsmongo"
@@ -20,6 +25,9 @@ import (
 
 const (
 	restrictedPath = "/restricted"
+
+	// Heroku kills dynos 30 seconds after asking them to stop.
+	shutdownTimeout = 25 * time.Second
 )
 
 // Specification represents a map of enviornment variables
@@ -27,9 +35,16 @@ type Specification struct {
 	UserPassword  string `envconfig:"USERPASSWD" required:"true"`
 	EncryptionKey string `envconfig:"ENCRYPTION_KEY" required:"true"`
 
-	MongodbURI string `default:"mongodb://127.0.0.1:27017/db" envconfig:"MONGODB_URI"`
-	Port       string `default:"8080"`
-	PublicHTML string `envconfig:"PUBLIC_HTML" default:"public"`
+	MongodbURI string        `default:"mongodb://127.0.0.1:27017/db" envconfig:"MONGODB_URI"`
+	DBTimeout  time.Duration `default:"10s" envconfig:"DB_TIMEOUT"`
+	SpillPath  string        `envconfig:"WRITE_SPILL_PATH"`
+	// DBPoolLimit limits the mongo sessions (and sockets) used by requests at once.
+	DBPoolLimit      int           `default:"64" envconfig:"DB_POOL_LIMIT"`
+	DBHealthInterval time.Duration `default:"10s" envconfig:"DB_HEALTH_INTERVAL"`
+	// ComfortTemp is the default temperature threshold of sleep summaries (Celsius).
+	ComfortTemp float64 `default:"26" envconfig:"COMFORT_TEMP"`
+	Port        string  `default:"8080"`
+	PublicHTML  string  `envconfig:"PUBLIC_HTML" default:"public"`
 }
 
 func main() {
@@ -44,14 +59,39 @@ func main() {
 	if len(key) != 32 {
 		log.Fatalf("ENCRYPTION_KEY must be 32-bytes long. Current key is \"%s\" which is %d bytes long.", key, len(key))
 	}
-	tsmongoSession, err := tsmongo.Dial(spec.MongodbURI)
+	tsmongoSession, err := tsmongo.Open(spec.MongodbURI)
 	if err != nil {
-		log.Fatalf("Error connecting to StatusDB: %s", spec.MongodbURI)
+		log.Fatalf("Error connecting to StatusDB: %q", err)
 	}
 	defer tsmongoSession.Close()
-	fanService := tsmongo.NewFanService(tsmongoSession)
-	bedroomService := tsmongo.NewBedroomService(tsmongoSession)
-	weatherService := tsmongo.NewWeatherService(tsmongoSession)
+	// Sensor readings are written in background, so they are not lost during short database
+	// outages.
+	writer, err := tsmongo.NewWriter(tsmongoSession, tsmongo.WriterOptions{
+		SpillPath: spec.SpillPath,
+		OnError:   func(err error) { log.Println(err) },
+	})
+	if err != nil {
+		log.Fatalf("Error creating writer: %q", err)
+	}
+	// Implausible sensor readings are quarantined, to be reviewed from the web UI.
+	rules, err := validationRules()
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	validator := tsmongo.NewValidator(writer, rules, func(r tsmongo.QuarantinedReading) {
+		log.Printf("Quarantined reading of %s at %s: %v (%s)", r.Series, r.Timestamp, r.Value, r.Rule)
+	})
+	// Requests use their own copies of mongo sessions, which are kept alive by pinging the
+	// database (refreshing broken connections, e.g. after a failover).
+	var pool *tsmongo.SessionPool
+	mongoSession, _ := tsmongoSession.(*tsmongo.Session)
+	if mongoSession != nil {
+		mongoSession.SetPoolLimit(spec.DBPoolLimit)
+		pool = tsmongo.NewSessionPool(mongoSession, spec.DBPoolLimit)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go mongoSession.KeepAlive(ctx, spec.DBHealthInterval)
+	}
 
 	publicHTML := filepath.Join(spec.PublicHTML)
 
@@ -69,9 +109,10 @@ func main() {
 	e.Use(middleware.Logger())
 	e.Use(session.Middleware(sessions.NewCookieStore(key)))
 	e.Use(middleware.GzipWithConfig(middleware.GzipConfig{Level: 5}))
+	e.Use(timeoutMiddleware(spec.DBTimeout))
 
 	// Public Routes.
-	bedroomAPIHandler := bedroomAPIHandler{key, bedroomService}
+	bedroomAPIHandler := bedroomAPIHandler{key, validator}
 	loginHandler := loginHandler{spec.UserPassword}
 	e.Static("/", publicHTML)
 	e.GET("/", func(c echo.Context) error {
@@ -81,19 +122,42 @@ func main() {
 	e.File("/favicon.ico", filepath.Join(publicHTML, "favicon.ico"))
 	e.POST("/indoortemp", bedroomAPIHandler.handlePost)
 	e.POST("/login", loginHandler.handle)
+	healthHandler := healthHandler{mongoSession}
+	e.GET("/health", healthHandler.handle)
 
 	// Routes which should only be accessed after login.
-	restricted := e.Group(restrictedPath, loginCheckMiddleware)
-
-	restrictedMainHandler := restrictedMainHandler{fanService}
-	weatherHandler := weatherHandler{weatherService}
-	fanHandler := fanHandler{fanService}
+	restricted := e.Group(restrictedPath, loginCheckMiddleware, storeMiddleware(tsmongoSession, pool))
+
+	restrictedMainHandler := restrictedMainHandler{}
+	weatherHandler := weatherHandler{}
+	fanHandler := fanHandler{}
+	applianceHandler := applianceHandler{}
+	sensorHandler := sensorHandler{}
+	predictionsHandler := predictionsHandler{}
+	adminHandler := adminHandler{}
+	quarantineHandler := quarantineHandler{}
+	annotationHandler := annotationHandler{}
+	sleepHandler := sleepHandler{spec.ComfortTemp}
 	logoutHandler := logoutHandler{}
 	restricted.GET("", restrictedMainHandler.handle)
 	restricted.POST("/fan", fanHandler.handle)
+	restricted.GET("/appliance", applianceHandler.handleGet)
+	restricted.POST("/appliance", applianceHandler.handlePost)
 	restricted.POST("/logout", logoutHandler.handle)
 	restricted.GET("/weather", weatherHandler.handle)
 	restricted.GET("/indoortemp", bedroomAPIHandler.handleGet)
+	restricted.GET("/sensor", sensorHandler.handleGet)
+	restricted.GET("/annotations", annotationHandler.handleGet)
+	restricted.POST("/annotations", annotationHandler.handlePost)
+	restricted.GET("/sleep", sleepHandler.handle)
+	restricted.GET("/sleep/summaries", sleepHandler.handleSummaries)
+	restricted.POST("/sleep", sleepHandler.handlePost)
+	restricted.GET("/predictions", predictionsHandler.handle)
+	restricted.GET("/admin", adminHandler.handle)
+	restricted.GET("/admin/stats", adminHandler.handleStats)
+	restricted.GET("/quarantine", quarantineHandler.handle)
+	restricted.POST("/quarantine/release", quarantineHandler.handleRelease)
+	restricted.POST("/quarantine/discard", quarantineHandler.handleDiscard)
 
 	// Starting server.
 
@@ -102,7 +166,60 @@ func main() {
 		ReadTimeout:  5 * time.Minute,
 		WriteTimeout: 5 * time.Minute,
 	}
-	e.Logger.Fatal(e.StartServer(s))
+	go func() {
+		if err := e.StartServer(s); err != nil && err != http.ErrServerClosed {
+			e.Logger.Fatal(err)
+		}
+	}()
+
+	// Shutting down gracefully (e.g. on dyno restarts), flushing queued sensor readings.
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	<-sig
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := e.Shutdown(ctx); err != nil {
+		log.Printf("Error shutting the server down: %q", err)
+	}
+	log.Printf("Flushing %d queued records.", writer.QueueDepth())
+	if err := writer.Shutdown(ctx); err != nil {
+		log.Println(err)
+	}
+}
+
+// validationRules returns the rules incoming readings of the known metrics are validated by,
+// configured through VALIDATE_<METRIC> environment variables, for instance:
+//
+//	VALIDATE_TEMPERATURE="min=5,max=45,rate=0.5,median=5,deviation=3"
+//
+// Metrics without rules are not validated.
+func validationRules() (map[string]tsmongo.ValidationRules, error) {
+	rules := make(map[string]tsmongo.ValidationRules)
+	for _, m := range []tsmongo.Metric{tsmongo.Temperature, tsmongo.Humidity, tsmongo.Light, tsmongo.CO2} {
+		env := "VALIDATE_" + strings.ToUpper(m.Name)
+		v := os.Getenv(env)
+		if v == "" {
+			continue
+		}
+		r, err := tsmongo.ParseValidationRules(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %q", env, err)
+		}
+		rules[m.Name] = r
+	}
+	return rules, nil
+}
+
+// timeoutMiddleware sets a deadline to the request context, which is honored by database calls.
+func timeoutMiddleware(d time.Duration) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx, cancel := context.WithTimeout(c.Request().Context(), d)
+			defer cancel()
+			c.SetRequest(c.Request().WithContext(ctx))
+			return next(c)
+		}
+	}
 }
 
 type template struct {
@@ -2,21 +2,37 @@ package main
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/danielfireman/temp-to-go/server/tsmongo"
 	"github.com/labstack/echo"
 )
 
 type restrictedMainHandler struct {
-	fan *tsmongo.FanService
 }
 
 func (h *restrictedMainHandler) handle(c echo.Context) error {
-	s, err := h.fan.LastState()
+	store := requestStore(c)
+	s, err := tsmongo.NewFanService(store, tsmongo.DefaultRoom).LastStateContext(c.Request().Context())
+	if err != nil {
+		c.Logger().Errorf("[main] %q\n", err)
+		return c.NoContent(storeErrorStatus(err))
+	}
+	acService, err := tsmongo.NewApplianceService(store, tsmongo.ApplianceAC, tsmongo.DefaultRoom)
 	if err != nil {
 		c.Logger().Errorf("[main] %q\n", err)
 		return c.NoContent(http.StatusInternalServerError)
 	}
+	ac, err := acService.LastStateContext(c.Request().Context())
+	if err != nil {
+		c.Logger().Errorf("[main] %q\n", err)
+		return c.NoContent(storeErrorStatus(err))
+	}
+	annotations, err := tsmongo.NewAnnotationService(store, tsmongo.DefaultRoom).AnnotationsContext(c.Request().Context(), time.Now().Add(-annotationHours*time.Hour), time.Now())
+	if err != nil {
+		c.Logger().Errorf("[main] %q\n", err)
+		return c.NoContent(storeErrorStatus(err))
+	}
 
 	// Converting the FanSpeed to text.
 	currSpeed := "Off"
@@ -33,17 +49,68 @@ func (h *restrictedMainHandler) handle(c echo.Context) error {
 		Value tsmongo.FanStatus
 		Name  string
 	}
+	// Struct containing the air conditioner state and options to draw its form.
+	type acForm struct {
+		State         tsmongo.ApplianceState
+		Caps          tsmongo.Capabilities
+		Speeds        []int
+		Kind          tsmongo.ApplianceKind
+		Action        string
+		KindParam     string
+		OnParam       string
+		SpeedParam    string
+		SetpointParam string
+		ModeParam     string
+	}
+	// Struct containing the recent annotations and options to draw the annotation form.
+	type annotationForm struct {
+		Annotations []tsmongo.Annotation
+		Action      string
+		StartParam  string
+		FinishParam string
+		TextParam   string
+	}
+	caps := acService.Capabilities()
+	speeds := make([]int, caps.Speeds)
+	for i := range speeds {
+		speeds[i] = i + 1
+	}
 	return c.Render(http.StatusOK, "main", struct {
 		Speed  string
+		Change tsmongo.FanChange
 		Opts   []fanOpt
 		Action string
+		Reason string
+		AC     acForm
+		Notes  annotationForm
 	}{
-		Speed: currSpeed,
+		Speed:  currSpeed,
+		Change: s.FanChange,
 		Opts: []fanOpt{
 			{"Off", tsmongo.FanOff, fanStatusFieldName},
 			{"Low", tsmongo.FanLowSpeed, fanStatusFieldName},
 			{"High", tsmongo.FanHighSpeed, fanStatusFieldName},
 		},
 		Action: fanPath,
+		Reason: fanReasonFieldName,
+		AC: acForm{
+			State:         ac,
+			Caps:          caps,
+			Speeds:        speeds,
+			Kind:          acService.Kind(),
+			Action:        appliancePath,
+			KindParam:     kindParam,
+			OnParam:       onParam,
+			SpeedParam:    speedParam,
+			SetpointParam: setpointParam,
+			ModeParam:     modeParam,
+		},
+		Notes: annotationForm{
+			Annotations: annotations,
+			Action:      annotationsPath,
+			StartParam:  startParam,
+			FinishParam: finishParam,
+			TextParam:   textParam,
+		},
 	})
 }
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/danielfireman/temp-to-go/server/tsmongo"
+	"github.com/labstack/echo"
+)
+
+type predictionsHandler struct {
+}
+
+// handle returns the latest predictor run and its predictions.
+func (h *predictionsHandler) handle(c echo.Context) error {
+	run, ps, err := tsmongo.NewPredictionService(requestStore(c)).LatestContext(c.Request().Context())
+	switch err {
+	case nil:
+		return c.JSON(http.StatusOK, predictionsResponse{run, ps})
+	case tsmongo.ErrNotFound:
+		return c.NoContent(http.StatusNotFound)
+	default:
+		c.Logger().Errorf("[/restricted/predictions] %q\n", err)
+		return c.NoContent(storeErrorStatus(err))
+	}
+}
+
+type predictionsResponse struct {
+	Run         tsmongo.PredictionRun `json:"run"`
+	Predictions []tsmongo.Prediction  `json:"predictions"`
+}
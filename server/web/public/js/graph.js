@@ -1,21 +1,32 @@
 "use strict";
 (function() {
 
-var $global,$module;if(Error.stackTraceLimit=1/0,"undefined"!=typeof window?$global=window:"undefined"!=typeof self?$global=self:"undefined"!=typeof global?($global=global).require=require:$global=this,void 0===$global||void 0===$global.Array)throw new Error("no global object found");"undefined"!=typeof module&&($module=module);var $throwRuntimeError,$packages={},$idCounter=0,$keys=function(e){return e?Object.keys(e):[]},$flushConsole=function(){},$throwNilPointerError=function(){$throwRuntimeError("invalid memory address or nil pointer dereference")},$call=function(e,n,r){return e.apply(n,r)},$makeFunc=function(e){return function(){return $externalize(e(this,new($sliceType($jsObjectPtr))($global.Array.prototype.slice.call(arguments,[]))),$emptyInterface)}},$unused=function(e){},$mapArray=function(e,n){for(var r=new e.constructor(e.length),t=0;t<e.length;t++)r[t]=n(e[t]);return r},$methodVal=function(e,n){var r=e.$methodVals||{};e.$methodVals=r;var t=r[n];if(void 0!==t)return t;var i=e[n];return t=function(){$stackDepthOffset--;try{return i.apply(e,arguments)}finally{$stackDepthOffset++}},r[n]=t,t},$methodExpr=function(e,n){var r=e.prototype[n];return void 0===r.$expr&&(r.$expr=function(){$stackDepthOffset--;try{return e.wrapped&&(arguments[0]=new e(arguments[0])),Function.call.apply(r,arguments)}finally{$stackDepthOffset++}}),r.$expr},$ifaceMethodExprs={},$ifaceMethodExpr=function(e){var n=$ifaceMethodExprs["$"+e];return void 0===n&&(n=$ifaceMethodExprs["$"+e]=function(){$stackDepthOffset--;try{return Function.call.apply(arguments[0][e],arguments)}finally{$stackDepthOffset++}}),n},$subslice=function(e,n,r,t){void 0===r&&(r=e.$length),void 0===t&&(t=e.$capacity),(n<0||r<n||t<r||r>e.$capacity||t>e.$capacity)&&$throwRuntimeError("slice bounds out of range");var i=new e.constructor(e.$array);return i.$offset=e.$offset+n,i.$length=r-n,i.$capacity=t-n,i},$substring=function(e,n,r){return(n<0||r<n||r>e.length)&&$throwRuntimeError("slice bounds out of range"),e.substring(n,r)},$sliceToArray=function(e){return e.$array.constructor!==Array?e.$array.subarray(e.$offset,e.$offset+e.$length):e.$array.slice(e.$offset,e.$offset+e.$length)},$decodeRune=function(e,n){var r=e.charCodeAt(n);if(r<128)return[r,1];if(r!=r||r<192)return[65533,1];var t=e.charCodeAt(n+1);if(t!=t||t<128||192<=t)return[65533,1];if(r<224)return(a=(31&r)<<6|63&t)<=127?[65533,1]:[a,2];var i=e.charCodeAt(n+2);if(i!=i||i<128||192<=i)return[65533,1];if(r<240)return(a=(15&r)<<12|(63&t)<<6|63&i)<=2047?[65533,1]:55296<=a&&a<=57343?[65533,1]:[a,3];var a,o=e.charCodeAt(n+3);return o!=o||o<128||192<=o?[65533,1]:r<248?(a=(7&r)<<18|(63&t)<<12|(63&i)<<6|63&o)<=65535||1114111<a?[65533,1]:[a,4]:[65533,1]},$encodeRune=function(e){return(e<0||e>1114111||55296<=e&&e<=57343)&&(e=65533),e<=127?String.fromCharCode(e):e<=2047?String.fromCharCode(192|e>>6,128|63&e):e<=65535?String.fromCharCode(224|e>>12,128|e>>6&63,128|63&e):String.fromCharCode(240|e>>18,128|e>>12&63,128|e>>6&63,128|63&e)},$stringToBytes=function(e){for(var n=new Uint8Array(e.length),r=0;r<e.length;r++)n[r]=e.charCodeAt(r);return n},$bytesToString=function(e){if(0===e.$length)return"";for(var n="",r=0;r<e.$length;r+=1e4)n+=String.fromCharCode.apply(void 0,e.$array.subarray(e.$offset+r,e.$offset+Math.min(e.$length,r+1e4)));return n},$stringToRunes=function(e){for(var n,r=new Int32Array(e.length),t=0,i=0;i<e.length;i+=n[1],t++)n=$decodeRune(e,i),r[t]=n[0];return r.subarray(0,t)},$runesToString=function(e){if(0===e.$length)return"";for(var n="",r=0;r<e.$length;r++)n+=$encodeRune(e.$array[e.$offset+r]);return n},$copyString=function(e,n){for(var r=Math.min(n.length,e.$length),t=0;t<r;t++)e.$array[e.$offset+t]=n.charCodeAt(t);return r},$copySlice=function(e,n){var r=Math.min(n.$length,e.$length);return $copyArray(e.$array,n.$array,e.$offset,n.$offset,r,e.constructor.elem),r},$copyArray=function(e,n,r,t,i,a){if(0!==i&&(e!==n||r!==t))if(n.subarray)e.set(n.subarray(t,t+i),r);else{switch(a.kind){case $kindArray:case $kindStruct:if(e===n&&r>t){for(var o=i-1;o>=0;o--)a.copy(e[r+o],n[t+o]);return}for(o=0;o<i;o++)a.copy(e[r+o],n[t+o]);return}if(e===n&&r>t)for(o=i-1;o>=0;o--)e[r+o]=n[t+o];else for(o=0;o<i;o++)e[r+o]=n[t+o]}},$clone=function(e,n){var r=n.zero();return n.copy(r,e),r},$pointerOfStructConversion=function(e,n){void 0===e.$proxies&&(e.$proxies={},e.$proxies[e.constructor.string]=e);var r=e.$proxies[n.string];if(void 0===r){for(var t={},i=0;i<n.elem.fields.length;i++)!function(n){t[n]={get:function(){return e[n]},set:function(r){e[n]=r}}}(n.elem.fields[i].prop);(r=Object.create(n.prototype,t)).$val=r,e.$proxies[n.string]=r,r.$proxies=e.$proxies}return r},$append=function(e){return $internalAppend(e,arguments,1,arguments.length-1)},$appendSlice=function(e,n){if(n.constructor===String){var r=$stringToBytes(n);return $internalAppend(e,r,0,r.length)}return $internalAppend(e,n.$array,n.$offset,n.$length)},$internalAppend=function(e,n,r,t){if(0===t)return e;var i=e.$array,a=e.$offset,o=e.$length+t,$=e.$capacity;if(o>$)if(a=0,$=Math.max(o,e.$capacity<1024?2*e.$capacity:Math.floor(5*e.$capacity/4)),e.$array.constructor===Array){(i=e.$array.slice(e.$offset,e.$offset+e.$length)).length=$;for(var c=e.constructor.elem.zero,u=e.$length;u<$;u++)i[u]=c()}else(i=new e.$array.constructor($)).set(e.$array.subarray(e.$offset,e.$offset+e.$length));$copyArray(i,n,a+e.$length,r,t,e.constructor.elem);var l=new e.constructor(i);return l.$offset=a,l.$length=o,l.$capacity=$,l},$equal=function(e,n,r){if(r===$jsObjectPtr)return e===n;switch(r.kind){case $kindComplex64:case $kindComplex128:return e.$real===n.$real&&e.$imag===n.$imag;case $kindInt64:case $kindUint64:return e.$high===n.$high&&e.$low===n.$low;case $kindArray:if(e.length!==n.length)return!1;for(var t=0;t<e.length;t++)if(!$equal(e[t],n[t],r.elem))return!1;return!0;case $kindStruct:for(t=0;t<r.fields.length;t++){var i=r.fields[t];if(!$equal(e[i.prop],n[i.prop],i.typ))return!1}return!0;case $kindInterface:return $interfaceIsEqual(e,n);default:return e===n}},$interfaceIsEqual=function(e,n){return e===$ifaceNil||n===$ifaceNil?e===n:e.constructor===n.constructor&&(e.constructor===$jsObjectPtr?e.object===n.object:(e.constructor.comparable||$throwRuntimeError("comparing uncomparable type "+e.constructor.string),$equal(e.$val,n.$val,e.constructor)))},$min=Math.min,$mod=function(e,n){return e%n},$parseInt=parseInt,$parseFloat=function(e){return void 0!==e&&null!==e&&e.constructor===Number?e:parseFloat(e)},$froundBuf=new Float32Array(1),$fround=Math.fround||function(e){return $froundBuf[0]=e,$froundBuf[0]},$imul=Math.imul||function(e,n){var r=65535&e,t=65535&n;return r*t+((e>>>16&65535)*t+r*(n>>>16&65535)<<16>>>0)>>0},$floatKey=function(e){return e!=e?"NaN$"+ ++$idCounter:String(e)},$flatten64=function(e){return 4294967296*e.$high+e.$low},$shiftLeft64=function(e,n){return 0===n?e:n<32?new e.constructor(e.$high<<n|e.$low>>>32-n,e.$low<<n>>>0):n<64?new e.constructor(e.$low<<n-32,0):new e.constructor(0,0)},$shiftRightInt64=function(e,n){return 0===n?e:n<32?new e.constructor(e.$high>>n,(e.$low>>>n|e.$high<<32-n)>>>0):n<64?new e.constructor(e.$high>>31,e.$high>>n-32>>>0):e.$high<0?new e.constructor(-1,4294967295):new e.constructor(0,0)},$shiftRightUint64=function(e,n){return 0===n?e:n<32?new e.constructor(e.$high>>>n,(e.$low>>>n|e.$high<<32-n)>>>0):n<64?new e.constructor(0,e.$high>>>n-32):new e.constructor(0,0)},$mul64=function(e,n){var r=0,t=0;0!=(1&n.$low)&&(r=e.$high,t=e.$low);for(var i=1;i<32;i++)0!=(n.$low&1<<i)&&(r+=e.$high<<i|e.$low>>>32-i,t+=e.$low<<i>>>0);for(i=0;i<32;i++)0!=(n.$high&1<<i)&&(r+=e.$low<<i);return new e.constructor(r,t)},$div64=function(e,n,r){0===n.$high&&0===n.$low&&$throwRuntimeError("integer divide by zero");var t=1,i=1,a=e.$high,o=e.$low;a<0&&(t=-1,i=-1,a=-a,0!==o&&(a--,o=4294967296-o));var $=n.$high,c=n.$low;n.$high<0&&(t*=-1,$=-$,0!==c&&($--,c=4294967296-c));for(var u=0,l=0,s=0;$<2147483648&&(a>$||a===$&&o>c);)$=($<<1|c>>>31)>>>0,c=c<<1>>>0,s++;for(var f=0;f<=s;f++)u=u<<1|l>>>31,l=l<<1>>>0,(a>$||a===$&&o>=c)&&(a-=$,(o-=c)<0&&(a--,o+=4294967296),4294967296===++l&&(u++,l=0)),c=(c>>>1|$<<31)>>>0,$>>>=1;return r?new e.constructor(a*i,o*i):new e.constructor(u*t,l*t)},$divComplex=function(e,n){var r=e.$real===1/0||e.$real===-1/0||e.$imag===1/0||e.$imag===-1/0,t=n.$real===1/0||n.$real===-1/0||n.$imag===1/0||n.$imag===-1/0,i=!r&&(e.$real!=e.$real||e.$imag!=e.$imag),a=!t&&(n.$real!=n.$real||n.$imag!=n.$imag);if(i||a)return new e.constructor(NaN,NaN);if(r&&!t)return new e.constructor(1/0,1/0);if(!r&&t)return new e.constructor(0,0);if(0===n.$real&&0===n.$imag)return 0===e.$real&&0===e.$imag?new e.constructor(NaN,NaN):new e.constructor(1/0,1/0);if(Math.abs(n.$real)<=Math.abs(n.$imag)){var o=n.$real/n.$imag,$=n.$real*o+n.$imag;return new e.constructor((e.$real*o+e.$imag)/$,(e.$imag*o-e.$real)/$)}o=n.$imag/n.$real,$=n.$imag*o+n.$real;return new e.constructor((e.$imag*o+e.$real)/$,(e.$imag-e.$real*o)/$)},$kindBool=1,$kindInt=2,$kindInt8=3,$kindInt16=4,$kindInt32=5,$kindInt64=6,$kindUint=7,$kindUint8=8,$kindUint16=9,$kindUint32=10,$kindUint64=11,$kindUintptr=12,$kindFloat32=13,$kindFloat64=14,$kindComplex64=15,$kindComplex128=16,$kindArray=17,$kindChan=18,$kindFunc=19,$kindInterface=20,$kindMap=21,$kindPtr=22,$kindSlice=23,$kindString=24,$kindStruct=25,$kindUnsafePointer=26,$methodSynthesizers=[],$addMethodSynthesizer=function(e){null!==$methodSynthesizers?$methodSynthesizers.push(e):e()},$synthesizeMethods=function(){$methodSynthesizers.forEach(function(e){e()}),$methodSynthesizers=null},$ifaceKeyFor=function(e){if(e===$ifaceNil)return"nil";var n=e.constructor;return n.string+"$"+n.keyFor(e.$val)},$identity=function(e){return e},$typeIDCounter=0,$idKey=function(e){return void 0===e.$id&&($idCounter++,e.$id=$idCounter),String(e.$id)},$newType=function(e,n,r,t,i,a,o){var $;switch(n){case $kindBool:case $kindInt:case $kindInt8:case $kindInt16:case $kindInt32:case $kindUint:case $kindUint8:case $kindUint16:case $kindUint32:case $kindUintptr:case $kindUnsafePointer:($=function(e){this.$val=e}).wrapped=!0,$.keyFor=$identity;break;case $kindString:($=function(e){this.$val=e}).wrapped=!0,$.keyFor=function(e){return"$"+e};break;case $kindFloat32:case $kindFloat64:($=function(e){this.$val=e}).wrapped=!0,$.keyFor=function(e){return $floatKey(e)};break;case $kindInt64:($=function(e,n){this.$high=e+Math.floor(Math.ceil(n)/4294967296)>>0,this.$low=n>>>0,this.$val=this}).keyFor=function(e){return e.$high+"$"+e.$low};break;case $kindUint64:($=function(e,n){this.$high=e+Math.floor(Math.ceil(n)/4294967296)>>>0,this.$low=n>>>0,this.$val=this}).keyFor=function(e){return e.$high+"$"+e.$low};break;case $kindComplex64:($=function(e,n){this.$real=$fround(e),this.$imag=$fround(n),this.$val=this}).keyFor=function(e){return e.$real+"$"+e.$imag};break;case $kindComplex128:($=function(e,n){this.$real=e,this.$imag=n,this.$val=this}).keyFor=function(e){return e.$real+"$"+e.$imag};break;case $kindArray:($=function(e){this.$val=e}).wrapped=!0,$.ptr=$newType(4,$kindPtr,"*"+r,!1,"",!1,function(e){this.$get=function(){return e},this.$set=function(e){$.copy(this,e)},this.$val=e}),$.init=function(e,n){$.elem=e,$.len=n,$.comparable=e.comparable,$.keyFor=function(n){return Array.prototype.join.call($mapArray(n,function(n){return String(e.keyFor(n)).replace(/\\/g,"\\\\").replace(/\$/g,"\\$")}),"$")},$.copy=function(n,r){$copyArray(n,r,0,0,r.length,e)},$.ptr.init($),Object.defineProperty($.ptr.nil,"nilCheck",{get:$throwNilPointerError})};break;case $kindChan:($=function(e){this.$val=e}).wrapped=!0,$.keyFor=$idKey,$.init=function(e,n,r){$.elem=e,$.sendOnly=n,$.recvOnly=r};break;case $kindFunc:($=function(e){this.$val=e}).wrapped=!0,$.init=function(e,n,r){$.params=e,$.results=n,$.variadic=r,$.comparable=!1};break;case $kindInterface:($={implementedBy:{},missingMethodFor:{}}).keyFor=$ifaceKeyFor,$.init=function(e){$.methods=e,e.forEach(function(e){$ifaceNil[e.prop]=$throwNilPointerError})};break;case $kindMap:($=function(e){this.$val=e}).wrapped=!0,$.init=function(e,n){$.key=e,$.elem=n,$.comparable=!1};break;case $kindPtr:($=o||function(e,n,r){this.$get=e,this.$set=n,this.$target=r,this.$val=this}).keyFor=$idKey,$.init=function(e){$.elem=e,$.wrapped=e.kind===$kindArray,$.nil=new $($throwNilPointerError,$throwNilPointerError)};break;case $kindSlice:($=function(e){e.constructor!==$.nativeArray&&(e=new $.nativeArray(e)),this.$array=e,this.$offset=0,this.$length=e.length,this.$capacity=e.length,this.$val=this}).init=function(e){$.elem=e,$.comparable=!1,$.nativeArray=$nativeArray(e.kind),$.nil=new $([])};break;case $kindStruct:($=function(e){this.$val=e}).wrapped=!0,$.ptr=$newType(4,$kindPtr,"*"+r,!1,i,a,o),$.ptr.elem=$,$.ptr.prototype.$get=function(){return this},$.ptr.prototype.$set=function(e){$.copy(this,e)},$.init=function(e,n){$.pkgPath=e,$.fields=n,n.forEach(function(e){e.typ.comparable||($.comparable=!1)}),$.keyFor=function(e){var r=e.$val;return $mapArray(n,function(e){return String(e.typ.keyFor(r[e.prop])).replace(/\\/g,"\\\\").replace(/\$/g,"\\$")}).join("$")},$.copy=function(e,r){for(var t=0;t<n.length;t++){var i=n[t];switch(i.typ.kind){case $kindArray:case $kindStruct:i.typ.copy(e[i.prop],r[i.prop]);continue;default:e[i.prop]=r[i.prop];continue}}};var r={};n.forEach(function(e){r[e.prop]={get:$throwNilPointerError,set:$throwNilPointerError}}),$.ptr.nil=Object.create(o.prototype,r),$.ptr.nil.$val=$.ptr.nil,$addMethodSynthesizer(function(){var e=function(e,n,r){void 0===e.prototype[n.prop]&&(e.prototype[n.prop]=function(){var e=this.$val[r.prop];return r.typ===$jsObjectPtr&&(e=new $jsObjectPtr(e)),void 0===e.$val&&(e=new r.typ(e)),e[n.prop].apply(e,arguments)})};n.forEach(function(n){n.anonymous&&($methodSet(n.typ).forEach(function(r){e($,r,n),e($.ptr,r,n)}),$methodSet($ptrType(n.typ)).forEach(function(r){e($.ptr,r,n)}))})})};break;default:$panic(new $String("invalid kind: "+n))}switch(n){case $kindBool:case $kindMap:$.zero=function(){return!1};break;case $kindInt:case $kindInt8:case $kindInt16:case $kindInt32:case $kindUint:case $kindUint8:case $kindUint16:case $kindUint32:case $kindUintptr:case $kindUnsafePointer:case $kindFloat32:case $kindFloat64:$.zero=function(){return 0};break;case $kindString:$.zero=function(){return""};break;case $kindInt64:case $kindUint64:case $kindComplex64:case $kindComplex128:var c=new $(0,0);$.zero=function(){return c};break;case $kindPtr:case $kindSlice:$.zero=function(){return $.nil};break;case $kindChan:$.zero=function(){return $chanNil};break;case $kindFunc:$.zero=function(){return $throwNilPointerError};break;case $kindInterface:$.zero=function(){return $ifaceNil};break;case $kindArray:$.zero=function(){var e=$nativeArray($.elem.kind);if(e!==Array)return new e($.len);for(var n=new Array($.len),r=0;r<$.len;r++)n[r]=$.elem.zero();return n};break;case $kindStruct:$.zero=function(){return new $.ptr};break;default:$panic(new $String("invalid kind: "+n))}return $.id=$typeIDCounter,$typeIDCounter++,$.size=e,$.kind=n,$.string=r,$.named=t,$.pkg=i,$.exported=a,$.methods=[],$.methodSetCache=null,$.comparable=!0,$},$methodSet=function(e){if(null!==e.methodSetCache)return e.methodSetCache;var n={},r=e.kind===$kindPtr;if(r&&e.elem.kind===$kindInterface)return e.methodSetCache=[],[];for(var t=[{typ:r?e.elem:e,indirect:r}],i={};t.length>0;){var a=[],o=[];t.forEach(function(e){if(!i[e.typ.string])switch(i[e.typ.string]=!0,e.typ.named&&(o=o.concat(e.typ.methods),e.indirect&&(o=o.concat($ptrType(e.typ).methods))),e.typ.kind){case $kindStruct:e.typ.fields.forEach(function(n){if(n.anonymous){var r=n.typ,t=r.kind===$kindPtr;a.push({typ:t?r.elem:r,indirect:e.indirect||t})}});break;case $kindInterface:o=o.concat(e.typ.methods)}}),o.forEach(function(e){void 0===n[e.name]&&(n[e.name]=e)}),t=a}return e.methodSetCache=[],Object.keys(n).sort().forEach(function(r){e.methodSetCache.push(n[r])}),e.methodSetCache},$Bool=$newType(1,$kindBool,"bool",!0,"",!1,null),$Int=$newType(4,$kindInt,"int",!0,"",!1,null),$Int8=$newType(1,$kindInt8,"int8",!0,"",!1,null),$Int16=$newType(2,$kindInt16,"int16",!0,"",!1,null),$Int32=$newType(4,$kindInt32,"int32",!0,"",!1,null),$Int64=$newType(8,$kindInt64,"int64",!0,"",!1,null),$Uint=$newType(4,$kindUint,"uint",!0,"",!1,null),$Uint8=$newType(1,$kindUint8,"uint8",!0,"",!1,null),$Uint16=$newType(2,$kindUint16,"uint16",!0,"",!1,null),$Uint32=$newType(4,$kindUint32,"uint32",!0,"",!1,null),$Uint64=$newType(8,$kindUint64,"uint64",!0,"",!1,null),$Uintptr=$newType(4,$kindUintptr,"uintptr",!0,"",!1,null),$Float32=$newType(4,$kindFloat32,"float32",!0,"",!1,null),$Float64=$newType(8,$kindFloat64,"float64",!0,"",!1,null),$Complex64=$newType(8,$kindComplex64,"complex64",!0,"",!1,null),$Complex128=$newType(16,$kindComplex128,"complex128",!0,"",!1,null),$String=$newType(8,$kindString,"string",!0,"",!1,null),$UnsafePointer=$newType(4,$kindUnsafePointer,"unsafe.Pointer",!0,"",!1,null),$nativeArray=function(e){switch(e){case $kindInt:return Int32Array;case $kindInt8:return Int8Array;case $kindInt16:return Int16Array;case $kindInt32:return Int32Array;case $kindUint:return Uint32Array;case $kindUint8:return Uint8Array;case $kindUint16:return Uint16Array;case $kindUint32:case $kindUintptr:return Uint32Array;case $kindFloat32:return Float32Array;case $kindFloat64:return Float64Array;default:return Array}},$toNativeArray=function(e,n){var r=$nativeArray(e);return r===Array?n:new r(n)},$arrayTypes={},$arrayType=function(e,n){var r=e.id+"$"+n,t=$arrayTypes[r];return void 0===t&&(t=$newType(12,$kindArray,"["+n+"]"+e.string,!1,"",!1,null),$arrayTypes[r]=t,t.init(e,n)),t},$chanType=function(e,n,r){var t=(r?"<-":"")+"chan"+(n?"<- ":" ")+e.string,i=n?"SendChan":r?"RecvChan":"Chan",a=e[i];return void 0===a&&(a=$newType(4,$kindChan,t,!1,"",!1,null),e[i]=a,a.init(e,n,r)),a},$Chan=function(e,n){(n<0||n>2147483647)&&$throwRuntimeError("makechan: size out of range"),this.$elem=e,this.$capacity=n,this.$buffer=[],this.$sendQueue=[],this.$recvQueue=[],this.$closed=!1},$chanNil=new $Chan(null,0);$chanNil.$sendQueue=$chanNil.$recvQueue={length:0,push:function(){},shift:function(){},indexOf:function(){return-1}};var $funcTypes={},$funcType=function(e,n,r){var t=$mapArray(e,function(e){return e.id}).join(",")+"$"+$mapArray(n,function(e){return e.id}).join(",")+"$"+r,i=$funcTypes[t];if(void 0===i){var a=$mapArray(e,function(e){return e.string});r&&(a[a.length-1]="..."+a[a.length-1].substr(2));var o="func("+a.join(", ")+")";1===n.length?o+=" "+n[0].string:n.length>1&&(o+=" ("+$mapArray(n,function(e){return e.string}).join(", ")+")"),i=$newType(4,$kindFunc,o,!1,"",!1,null),$funcTypes[t]=i,i.init(e,n,r)}return i},$interfaceTypes={},$interfaceType=function(e){var n=$mapArray(e,function(e){return e.pkg+","+e.name+","+e.typ.id}).join("$"),r=$interfaceTypes[n];if(void 0===r){var t="interface {}";0!==e.length&&(t="interface { "+$mapArray(e,function(e){return(""!==e.pkg?e.pkg+".":"")+e.name+e.typ.string.substr(4)}).join("; ")+" }"),r=$newType(8,$kindInterface,t,!1,"",!1,null),$interfaceTypes[n]=r,r.init(e)}return r},$emptyInterface=$interfaceType([]),$ifaceNil={},$error=$newType(8,$kindInterface,"error",!0,"",!1,null);$error.init([{prop:"Error",name:"Error",pkg:"",typ:$funcType([],[$String],!1)}]);var $panicValue,$jsObjectPtr,$jsErrorPtr,$mapTypes={},$mapType=function(e,n){var r=e.id+"$"+n.id,t=$mapTypes[r];return void 0===t&&(t=$newType(4,$kindMap,"map["+e.string+"]"+n.string,!1,"",!1,null),$mapTypes[r]=t,t.init(e,n)),t},$makeMap=function(e,n){for(var r={},t=0;t<n.length;t++){var i=n[t];r[e(i.k)]=i}return r},$ptrType=function(e){var n=e.ptr;return void 0===n&&(n=$newType(4,$kindPtr,"*"+e.string,!1,"",e.exported,null),e.ptr=n,n.init(e)),n},$newDataPointer=function(e,n){return n.elem.kind===$kindStruct?e:new n(function(){return e},function(n){e=n})},$indexPtr=function(e,n,r){return e.$ptr=e.$ptr||{},e.$ptr[n]||(e.$ptr[n]=new r(function(){return e[n]},function(r){e[n]=r}))},$sliceType=function(e){var n=e.slice;return void 0===n&&(n=$newType(12,$kindSlice,"[]"+e.string,!1,"",!1,null),e.slice=n,n.init(e)),n},$makeSlice=function(e,n,r){r=r||n,(n<0||n>2147483647)&&$throwRuntimeError("makeslice: len out of range"),(r<0||r<n||r>2147483647)&&$throwRuntimeError("makeslice: cap out of range");var t=new e.nativeArray(r);if(e.nativeArray===Array)for(var i=0;i<r;i++)t[i]=e.elem.zero();var a=new e(t);return a.$length=n,a},$structTypes={},$structType=function(e,n){var r=$mapArray(n,function(e){return e.name+","+e.typ.id+","+e.tag}).join("$"),t=$structTypes[r];if(void 0===t){var i="struct { "+$mapArray(n,function(e){return e.name+" "+e.typ.string+(""!==e.tag?' "'+e.tag.replace(/\\/g,"\\\\").replace(/"/g,'\\"')+'"':"")}).join("; ")+" }";0===n.length&&(i="struct {}"),t=$newType(0,$kindStruct,i,!1,"",!1,function(){this.$val=this;for(var e=0;e<n.length;e++){var r=n[e],t=arguments[e];this[r.prop]=void 0!==t?t:r.typ.zero()}}),$structTypes[r]=t,t.init(e,n)}return t},$assertType=function(e,n,r){var t,i=n.kind===$kindInterface,a="";if(e===$ifaceNil)t=!1;else if(i){var o=e.constructor.string;if(void 0===(t=n.implementedBy[o])){t=!0;for(var $=$methodSet(e.constructor),c=n.methods,u=0;u<c.length;u++){for(var l=c[u],s=!1,f=0;f<$.length;f++){var d=$[f];if(d.name===l.name&&d.pkg===l.pkg&&d.typ===l.typ){s=!0;break}}if(!s){t=!1,n.missingMethodFor[o]=l.name;break}}n.implementedBy[o]=t}t||(a=n.missingMethodFor[o])}else t=e.constructor===n;if(!t){if(r)return[n.zero(),!1];$panic(new $packages.runtime.TypeAssertionError.ptr("",e===$ifaceNil?"":e.constructor.string,n.string,a))}return i||(e=e.$val),n===$jsObjectPtr&&(e=e.object),r?[e,!0]:e},$stackDepthOffset=0,$getStackDepth=function(){var e=new Error;if(void 0!==e.stack)return $stackDepthOffset+e.stack.split("\n").length},$panicStackDepth=null,$callDeferred=function(e,n,r){if(!r&&null!==e&&e.index>=$curGoroutine.deferStack.length)throw n;if(null!==n){var t=null;try{$curGoroutine.deferStack.push(e),$panic(new $jsErrorPtr(n))}catch(e){t=e}return $curGoroutine.deferStack.pop(),void $callDeferred(e,t)}if(!$curGoroutine.asleep){$stackDepthOffset--;var i=$panicStackDepth,a=$panicValue,o=$curGoroutine.panicStack.pop();void 0!==o&&($panicStackDepth=$getStackDepth(),$panicValue=o);try{for(;;){if(null===e&&void 0===(e=$curGoroutine.deferStack[$curGoroutine.deferStack.length-1])){if($panicStackDepth=null,o.Object instanceof Error)throw o.Object;var $;throw $=o.constructor===$String?o.$val:void 0!==o.Error?o.Error():void 0!==o.String?o.String():o,new Error($)}var c=e.pop();if(void 0===c){if($curGoroutine.deferStack.pop(),void 0!==o){e=null;continue}return}var u=c[0].apply(c[2],c[1]);if(u&&void 0!==u.$blk){if(e.push([u.$blk,[],u]),r)throw null;return}if(void 0!==o&&null===$panicStackDepth)throw null}}finally{void 0!==o&&(null!==$panicStackDepth&&$curGoroutine.panicStack.push(o),$panicStackDepth=i,$panicValue=a),$stackDepthOffset++}}},$panic=function(e){$curGoroutine.panicStack.push(e),$callDeferred(null,null,!0)},$recover=function(){return null===$panicStackDepth||void 0!==$panicStackDepth&&$panicStackDepth!==$getStackDepth()-2?$ifaceNil:($panicStackDepth=null,$panicValue)},$throw=function(e){throw e},$noGoroutine={asleep:!1,exit:!1,deferStack:[],panicStack:[]},$curGoroutine=$noGoroutine,$totalGoroutines=0,$awakeGoroutines=0,$checkForDeadlock=!0,$mainFinished=!1,$go=function(e,n){$totalGoroutines++,$awakeGoroutines++;var r=function(){try{$curGoroutine=r;var t=e.apply(void 0,n);if(t&&void 0!==t.$blk)return e=function(){return t.$blk()},void(n=[]);r.exit=!0}catch(e){if(!r.exit)throw e}finally{$curGoroutine=$noGoroutine,r.exit&&($totalGoroutines--,r.asleep=!0),r.asleep&&($awakeGoroutines--,!$mainFinished&&0===$awakeGoroutines&&$checkForDeadlock&&(console.error("fatal error: all goroutines are asleep - deadlock!"),void 0!==$global.process&&$global.process.exit(2)))}};r.asleep=!1,r.exit=!1,r.deferStack=[],r.panicStack=[],$schedule(r)},$scheduled=[],$runScheduled=function(){try{for(var e;void 0!==(e=$scheduled.shift());)e()}finally{$scheduled.length>0&&setTimeout($runScheduled,0)}},$schedule=function(e){e.asleep&&(e.asleep=!1,$awakeGoroutines++),$scheduled.push(e),$curGoroutine===$noGoroutine&&$runScheduled()},$setTimeout=function(e,n){return $awakeGoroutines++,setTimeout(function(){$awakeGoroutines--,e()},n)},$block=function(){$curGoroutine===$noGoroutine&&$throwRuntimeError("cannot block in JavaScript callback, fix by wrapping code in goroutine"),$curGoroutine.asleep=!0},$send=function(e,n){e.$closed&&$throwRuntimeError("send on closed channel");var r=e.$recvQueue.shift();if(void 0===r){if(!(e.$buffer.length<e.$capacity)){var t,i=$curGoroutine;return e.$sendQueue.push(function(e){return t=e,$schedule(i),n}),$block(),{$blk:function(){t&&$throwRuntimeError("send on closed channel")}}}e.$buffer.push(n)}else r([n,!0])},$recv=function(e){var n=e.$sendQueue.shift();void 0!==n&&e.$buffer.push(n(!1));var r=e.$buffer.shift();if(void 0!==r)return[r,!0];if(e.$closed)return[e.$elem.zero(),!1];var t=$curGoroutine,i={$blk:function(){return this.value}};return e.$recvQueue.push(function(e){i.value=e,$schedule(t)}),$block(),i},$close=function(e){for(e.$closed&&$throwRuntimeError("close of closed channel"),e.$closed=!0;;){var n=e.$sendQueue.shift();if(void 0===n)break;n(!0)}for(;;){var r=e.$recvQueue.shift();if(void 0===r)break;r([e.$elem.zero(),!1])}},$select=function(e){for(var n=[],r=-1,t=0;t<e.length;t++){var i,a=(i=e[t])[0];switch(i.length){case 0:r=t;break;case 1:(0!==a.$sendQueue.length||0!==a.$buffer.length||a.$closed)&&n.push(t);break;case 2:a.$closed&&$throwRuntimeError("send on closed channel"),(0!==a.$recvQueue.length||a.$buffer.length<a.$capacity)&&n.push(t)}}if(0!==n.length&&(r=n[Math.floor(Math.random()*n.length)]),-1!==r)switch((i=e[r]).length){case 0:return[r];case 1:return[r,$recv(i[0])];case 2:return $send(i[0],i[1]),[r]}var o=[],$=$curGoroutine,c={$blk:function(){return this.selection}},u=function(){for(var e=0;e<o.length;e++){var n=o[e],r=n[0],t=r.indexOf(n[1]);-1!==t&&r.splice(t,1)}};for(t=0;t<e.length;t++)!function(n){var r=e[n];switch(r.length){case 1:var t=function(e){c.selection=[n,e],u(),$schedule($)};o.push([r[0].$recvQueue,t]),r[0].$recvQueue.push(t);break;case 2:t=function(){return r[0].$closed&&$throwRuntimeError("send on closed channel"),c.selection=[n],u(),$schedule($),r[1]};o.push([r[0].$sendQueue,t]),r[0].$sendQueue.push(t)}}(t);return $block(),c},$needsExternalization=function(e){switch(e.kind){case $kindBool:case $kindInt:case $kindInt8:case $kindInt16:case $kindInt32:case $kindUint:case $kindUint8:case $kindUint16:case $kindUint32:case $kindUintptr:case $kindFloat32:case $kindFloat64:return!1;default:return e!==$jsObjectPtr}},$externalize=function(e,n){if(n===$jsObjectPtr)return e;switch(n.kind){case $kindBool:case $kindInt:case $kindInt8:case $kindInt16:case $kindInt32:case $kindUint:case $kindUint8:case $kindUint16:case $kindUint32:case $kindUintptr:case $kindFloat32:case $kindFloat64:return e;case $kindInt64:case $kindUint64:return $flatten64(e);case $kindArray:return $needsExternalization(n.elem)?$mapArray(e,function(e){return $externalize(e,n.elem)}):e;case $kindFunc:return $externalizeFunction(e,n,!1);case $kindInterface:return e===$ifaceNil?null:e.constructor===$jsObjectPtr?e.$val.object:$externalize(e.$val,e.constructor);case $kindMap:for(var r={},t=$keys(e),i=0;i<t.length;i++){var a=e[t[i]];r[$externalize(a.k,n.key)]=$externalize(a.v,n.elem)}return r;case $kindPtr:return e===n.nil?null:$externalize(e.$get(),n.elem);case $kindSlice:return $needsExternalization(n.elem)?$mapArray($sliceToArray(e),function(e){return $externalize(e,n.elem)}):$sliceToArray(e);case $kindString:if($isASCII(e))return e;var o,$="";for(i=0;i<e.length;i+=o[1]){var c=(o=$decodeRune(e,i))[0];if(c>65535){var u=Math.floor((c-65536)/1024)+55296,l=(c-65536)%1024+56320;$+=String.fromCharCode(u,l)}else $+=String.fromCharCode(c)}return $;case $kindStruct:var s=$packages.time;if(void 0!==s&&e.constructor===s.Time.ptr){var f=$div64(e.UnixNano(),new $Int64(0,1e6));return new Date($flatten64(f))}var d={},p=function(e,n){if(n===$jsObjectPtr)return e;switch(n.kind){case $kindPtr:return e===n.nil?d:p(e.$get(),n.elem);case $kindStruct:var r=n.fields[0];return p(e[r.prop],r.typ);case $kindInterface:return p(e.$val,e.constructor);default:return d}},h=p(e,n);if(h!==d)return h;h={};for(i=0;i<n.fields.length;i++){var k=n.fields[i];k.exported&&(h[k.name]=$externalize(e[k.prop],k.typ))}return h}$throwRuntimeError("cannot externalize "+n.string)},$externalizeFunction=function(e,n,r){return e===$throwNilPointerError?null:(void 0===e.$externalizeWrapper&&($checkForDeadlock=!1,e.$externalizeWrapper=function(){for(var t=[],i=0;i<n.params.length;i++){if(n.variadic&&i===n.params.length-1){for(var a=n.params[i].elem,o=[],$=i;$<arguments.length;$++)o.push($internalize(arguments[$],a));t.push(new n.params[i](o));break}t.push($internalize(arguments[i],n.params[i]))}var c=e.apply(r?this:void 0,t);switch(n.results.length){case 0:return;case 1:return $externalize(c,n.results[0]);default:for(i=0;i<n.results.length;i++)c[i]=$externalize(c[i],n.results[i]);return c}}),e.$externalizeWrapper)},$internalize=function(e,n,r){if(n===$jsObjectPtr)return e;if(n===$jsObjectPtr.elem&&$throwRuntimeError("cannot internalize js.Object, use *js.Object instead"),e&&void 0!==e.__internal_object__)return $assertType(e.__internal_object__,n,!1);var t=$packages.time;if(void 0!==t&&n===t.Time)return null!==e&&void 0!==e&&e.constructor===Date||$throwRuntimeError("cannot internalize time.Time from "+typeof e+", must be Date"),t.Unix(new $Int64(0,0),new $Int64(0,1e6*e.getTime()));switch(n.kind){case $kindBool:return!!e;case $kindInt:return parseInt(e);case $kindInt8:return parseInt(e)<<24>>24;case $kindInt16:return parseInt(e)<<16>>16;case $kindInt32:return parseInt(e)>>0;case $kindUint:return parseInt(e);case $kindUint8:return parseInt(e)<<24>>>24;case $kindUint16:return parseInt(e)<<16>>>16;case $kindUint32:case $kindUintptr:return parseInt(e)>>>0;case $kindInt64:case $kindUint64:return new n(0,e);case $kindFloat32:case $kindFloat64:return parseFloat(e);case $kindArray:return e.length!==n.len&&$throwRuntimeError("got array with wrong size from JavaScript native"),$mapArray(e,function(e){return $internalize(e,n.elem)});case $kindFunc:return function(){for(var t=[],i=0;i<n.params.length;i++){if(n.variadic&&i===n.params.length-1){for(var a=n.params[i].elem,o=arguments[i],$=0;$<o.$length;$++)t.push($externalize(o.$array[o.$offset+$],a));break}t.push($externalize(arguments[i],n.params[i]))}var c=e.apply(r,t);switch(n.results.length){case 0:return;case 1:return $internalize(c,n.results[0]);default:for(i=0;i<n.results.length;i++)c[i]=$internalize(c[i],n.results[i]);return c}};case $kindInterface:if(0!==n.methods.length&&$throwRuntimeError("cannot internalize "+n.string),null===e)return $ifaceNil;if(void 0===e)return new $jsObjectPtr(void 0);switch(e.constructor){case Int8Array:return new($sliceType($Int8))(e);case Int16Array:return new($sliceType($Int16))(e);case Int32Array:return new($sliceType($Int))(e);case Uint8Array:return new($sliceType($Uint8))(e);case Uint16Array:return new($sliceType($Uint16))(e);case Uint32Array:return new($sliceType($Uint))(e);case Float32Array:return new($sliceType($Float32))(e);case Float64Array:return new($sliceType($Float64))(e);case Array:return $internalize(e,$sliceType($emptyInterface));case Boolean:return new $Bool(!!e);case Date:return void 0===t?new $jsObjectPtr(e):new t.Time($internalize(e,t.Time));case Function:var i=$funcType([$sliceType($emptyInterface)],[$jsObjectPtr],!0);return new i($internalize(e,i));case Number:return new $Float64(parseFloat(e));case String:return new $String($internalize(e,$String));default:if($global.Node&&e instanceof $global.Node)return new $jsObjectPtr(e);var a=$mapType($String,$emptyInterface);return new a($internalize(e,a))}case $kindMap:for(var o={},$=$keys(e),c=0;c<$.length;c++){var u=$internalize($[c],n.key);o[n.key.keyFor(u)]={k:u,v:$internalize(e[$[c]],n.elem)}}return o;case $kindPtr:if(n.elem.kind===$kindStruct)return $internalize(e,n.elem);case $kindSlice:return new n($mapArray(e,function(e){return $internalize(e,n.elem)}));case $kindString:if(e=String(e),$isASCII(e))return e;var l="";for(c=0;c<e.length;){var s=e.charCodeAt(c);if(55296<=s&&s<=56319){var f=e.charCodeAt(c+1);l+=$encodeRune(1024*(s-55296)+f-56320+65536),c+=2}else l+=$encodeRune(s),c++}return l;case $kindStruct:var d={},p=function(n){if(n===$jsObjectPtr)return e;switch(n===$jsObjectPtr.elem&&$throwRuntimeError("cannot internalize js.Object, use *js.Object instead"),n.kind){case $kindPtr:return p(n.elem);case $kindStruct:var r=n.fields[0],t=p(r.typ);if(t!==d){var i=new n.ptr;return i[r.prop]=t,i}return d;default:return d}},h=p(n);if(h!==d)return h}$throwRuntimeError("cannot internalize "+n.string)},$isASCII=function(e){for(var n=0;n<e.length;n++)if(e.charCodeAt(n)>=128)return!1;return!0};
+var $goVersion = "go1.21.13";
+var $testBinary = "0";
+var g=Object.defineProperty;var i=(r,e)=>g(r,"name",{value:e,configurable:!0});Error.stackTraceLimit=1/0;var $NaN=NaN,$global,$module;if(typeof window!="undefined"?$global=window:typeof self!="undefined"?$global=self:typeof global!="undefined"?($global=global,$global.require=require):$global=this,$global===void 0||$global.Array===void 0)throw new Error("no global object found");if(typeof module!="undefined"&&($module=module),!$global.fs&&$global.require)try{var fs=$global.require("fs");typeof fs=="object"&&fs!==null&&Object.keys(fs).length!==0&&($global.fs=fs)}catch(r){}if(!$global.fs){var outputBuf="",decoder=new TextDecoder("utf-8");$global.fs={constants:{O_WRONLY:-1,O_RDWR:-1,O_CREAT:-1,O_TRUNC:-1,O_APPEND:-1,O_EXCL:-1},writeSync:i(function(e,n){if($global.gopherjsWriteSyncHook)return outputBuf+=decoder.decode(n),$global.gopherjsWriteSyncHook(e,outputBuf),outputBuf="",n.length;outputBuf+=decoder.decode(n);var t=outputBuf.lastIndexOf(`
+`);return t!=-1&&(console.log(outputBuf.substring(0,t)),outputBuf=outputBuf.substring(t+1)),n.length},"writeSync"),write:i(function(e,n,t,o,a,u){if(t!==0||o!==n.length||a!==null){u(enosys());return}var $=this.writeSync(e,n);u(null,$)},"write")}}var $linknames={},$packages={},$idCounter=0,$keys=i(r=>r?Object.keys(r):[],"$keys"),$flushConsole=i(()=>{},"$flushConsole"),$throwRuntimeError,$newPanicNilError,$throwNilPointerError=i(()=>{$throwRuntimeError("invalid memory address or nil pointer dereference")},"$throwNilPointerError"),$call=i((r,e,n)=>r.apply(e,n),"$call"),$makeFunc=i(r=>function(...e){return $externalize(r(this,new($sliceType($jsObjectPtr))($global.Array.prototype.slice.call(e,[]))),$emptyInterface)},"$makeFunc"),$unused=i(r=>{},"$unused"),$print=console.log;if($global.process!==void 0&&$global.require)try{var util=$global.require("util");$print=i(function(...r){$global.process.stderr.write(util.format.apply(this,r))},"$print")}catch(r){}var $println=console.log,$callstack=i((r,e)=>{const n=Error.stackTraceLimit;var t;try{Error.stackTraceLimit=r+e,t=new Error().stack}finally{Error.stackTraceLimit=n}if(!t)return[];t=t.trim();const o=t.indexOf(`
+`),a=o>=0?t.substring(0,o):t;return!a.includes("@")&&!a.startsWith("at ")&&r++,t.split(`
+`).slice(r)},"$callstack"),$parseCallFrame=i(r=>{const e=/^(.+?)(?::(\d+)(?::(\d+))?)?$/,n=i((a,u)=>{const $=e.exec(u);if($){const c=$[1]||"",f=$[2]||0,s=$[3]||0;return[a,c,f,s]}return[a,"",0,0]},"parsePos"),t=/^(?:(?:Object|typ\d*)\.)|(?:[a-zA-Z_$][a-zA-Z0-9_$]*\.(github\.com[\\|/]))/,o=i(a=>a.replace(t,"$1"),"stripReceiver");return $parseCallFrame=i(a=>{const u=a.indexOf("@");if(u>=0){const d=a.substring(0,u)||"<none>";return n(d,a.substring(u+1))}const $=a.indexOf("at ");$>=0&&(a=a.substring($+3));const c=a.lastIndexOf("(");if(c===-1)return n("<none>",a);var f=a.substring(0,a.indexOf("(")).trim();const s=f.indexOf("[as ");if(s>0){var l=f.indexOf("]");l===-1&&(l=f.length),f=f.substring(s+4,l).trim()}f=o(f);var l=a.indexOf(")",c);l===-1&&(l=a.length);var v=a.substring(c+1,l);return v==="<anonymous>"?[f,"<anonymous>",0,0]:n(f,v)},"$parseCallFrame"),$parseCallFrame(r)},"$parseCallFrame"),$callForAllPackages=i(r=>{for(var e=$keys($packages),n=0;n<e.length;n++){var t=$packages[e[n]][r];typeof t=="function"&&t()}},"$callForAllPackages"),$mapArray=i((r,e)=>{for(var n=new r.constructor(r.length),t=0;t<r.length;t++)n[t]=e(r[t]);return n},"$mapArray"),$mapIndex=i((r,e)=>typeof r.get=="function"?r.get(e):void 0,"$mapIndex"),$mapDelete=i((r,e)=>{typeof r.delete=="function"&&r.delete(e)},"$mapDelete"),$methodVal=i((r,e)=>{var n=r.$methodVals||{};Object.isExtensible(r)&&(r.$methodVals=n);var t=n[e];if(t!==void 0)return t;var o=r[e];return t=o.bind(r),n[e]=t,t},"$methodVal"),$methodExpr=i((r,e)=>{var n=r.prototype[e];return n.$expr===void 0&&(n.$expr=(...t)=>{$stackDepthOffset--;try{return r.wrapped&&(t[0]=new r(t[0])),Function.call.apply(n,t)}finally{$stackDepthOffset++}}),n.$expr},"$methodExpr"),$ifaceMethodExprs={},$ifaceMethodExpr=i(r=>{var e=$ifaceMethodExprs["$"+r];return e===void 0&&(e=$ifaceMethodExprs["$"+r]=(...n)=>{$stackDepthOffset--;try{return Function.call.apply(n[0][r],n)}finally{$stackDepthOffset++}}),e},"$ifaceMethodExpr"),$subslice=i((r,e,n,t)=>{if(n===void 0&&(n=r.$length),t===void 0&&(t=r.$capacity),(e<0||n<e||t<n||n>r.$capacity||t>r.$capacity)&&$throwRuntimeError("slice bounds out of range"),r===r.constructor.nil)return r;var o=new r.constructor(r.$array);return o.$offset=r.$offset+e,o.$length=n-e,o.$capacity=t-e,o},"$subslice"),$substring=i((r,e,n)=>((e<0||n<e||n>r.length)&&$throwRuntimeError("slice bounds out of range"),r.substring(e,n)),"$substring"),$sliceToNativeArray=i(r=>r.$array.constructor!==Array?r.$array.subarray(r.$offset,r.$offset+r.$length):r.$array.slice(r.$offset,r.$offset+r.$length),"$sliceToNativeArray"),$sliceToGoArray=i((r,e)=>{var n=e.elem;if(n!==void 0&&r.$length<n.len&&$throwRuntimeError("cannot convert slice with length "+r.$length+" to pointer to array with length "+n.len),r==r.constructor.nil)return e.nil;if(r.$array.constructor!==Array)return r.$array.subarray(r.$offset,r.$offset+n.len);if(r.$offset==0&&r.$length==r.$capacity&&r.$length==n.len)return r.$array;if(n.len==0)return new n([]);$throwRuntimeError("gopherjs: non-numeric slice to underlying array conversion is not supported for subslices")},"$sliceToGoArray"),$convertSliceType=i((r,e)=>r==r.constructor.nil?e.nil:$subslice(new e(r.$array),r.$offset,r.$offset+r.$length,r.$offset+r.$capacity),"$convertSliceType"),$decodeRune=i((r,e)=>{var n=r.charCodeAt(e);if(n<128)return[n,1];if(n!==n||n<192)return[65533,1];var t=r.charCodeAt(e+1);if(t!==t||t<128||192<=t)return[65533,1];if(n<224){var o=(n&31)<<6|t&63;return o<=127?[65533,1]:[o,2]}var a=r.charCodeAt(e+2);if(a!==a||a<128||192<=a)return[65533,1];if(n<240){var o=(n&15)<<12|(t&63)<<6|a&63;return o<=2047?[65533,1]:55296<=o&&o<=57343?[65533,1]:[o,3]}var u=r.charCodeAt(e+3);if(u!==u||u<128||192<=u)return[65533,1];if(n<248){var o=(n&7)<<18|(t&63)<<12|(a&63)<<6|u&63;return o<=65535||1114111<o?[65533,1]:[o,4]}return[65533,1]},"$decodeRune"),$encodeRune=i(r=>((r<0||r>1114111||55296<=r&&r<=57343)&&(r=65533),r<=127?String.fromCharCode(r):r<=2047?String.fromCharCode(192|r>>6,128|r&63):r<=65535?String.fromCharCode(224|r>>12,128|r>>6&63,128|r&63):String.fromCharCode(240|r>>18,128|r>>12&63,128|r>>6&63,128|r&63)),"$encodeRune"),$stringToBytes=i(r=>{for(var e=new Uint8Array(r.length),n=0;n<r.length;n++)e[n]=r.charCodeAt(n);return e},"$stringToBytes"),$bytesToString=i(r=>{if(r.$length===0)return"";for(var e="",n=0;n<r.$length;n+=1e4)e+=String.fromCharCode.apply(void 0,r.$array.subarray(r.$offset+n,r.$offset+Math.min(r.$length,n+1e4)));return e},"$bytesToString"),$stringToRunes=i(r=>{for(var e=new Int32Array(r.length),n,t=0,o=0;o<r.length;o+=n[1],t++)n=$decodeRune(r,o),e[t]=n[0];return e.subarray(0,t)},"$stringToRunes"),$runesToString=i(r=>{if(r.$length===0)return"";for(var e="",n=0;n<r.$length;n++)e+=$encodeRune(r.$array[r.$offset+n]);return e},"$runesToString"),$copyString=i((r,e)=>{for(var n=Math.min(e.length,r.$length),t=0;t<n;t++)r.$array[r.$offset+t]=e.charCodeAt(t);return n},"$copyString"),$copySlice=i((r,e)=>{var n=Math.min(e.$length,r.$length);return $copyArray(r.$array,e.$array,r.$offset,e.$offset,n,r.constructor.elem),n},"$copySlice"),$copyArray=i((r,e,n,t,o,a)=>{if(!(o===0||r===e&&n===t)){if(e.subarray){r.set(e.subarray(t,t+o),n);return}switch(a.kind){case $kindArray:case $kindStruct:if(r===e&&n>t){for(var u=o-1;u>=0;u--)a.copy(r[n+u],e[t+u]);return}for(var u=0;u<o;u++)a.copy(r[n+u],e[t+u]);return}if(r===e&&n>t){for(var u=o-1;u>=0;u--)r[n+u]=e[t+u];return}for(var u=0;u<o;u++)r[n+u]=e[t+u]}},"$copyArray"),$clone=i((r,e)=>{var n=e.zero();return e.copy(n,r),n},"$clone"),$pointerOfStructConversion=i((r,e)=>{if(r===(r.constructor&&r.constructor.nil))return e.nil;r.$proxies===void 0&&(r.$proxies={},r.$proxies[r.constructor.id]=r);var n=r.$proxies[e.id];if(n===void 0){for(var t={},o=0;o<e.elem.fields.length;o++)(a=>{t[a]={get(){return r[a]},set(u){r[a]=u}}})(e.elem.fields[o].prop);n=Object.create(e.prototype,t),n.$val=n,r.$proxies[e.id]=n,n.$proxies=r.$proxies}return n},"$pointerOfStructConversion"),$append=i(function(r){return $internalAppend(r,arguments,1,arguments.length-1)},"$append"),$appendSlice=i((r,e)=>{if(e.constructor===String){var n=$stringToBytes(e);return $internalAppend(r,n,0,n.length)}return $internalAppend(r,e.$array,e.$offset,e.$length)},"$appendSlice"),$internalAppend=i((r,e,n,t)=>{if(t===0)return r;let o=r.$length+t,a=$growSlice(r,o),u=a.$array;return $copyArray(u,e,a.$offset+a.$length,n,t,a.constructor.elem),a.$length=o,a},"$internalAppend");const $calculateNewCapacity=i((r,e)=>Math.max(r,e<1024?e*2:Math.floor(e*5/4)),"$calculateNewCapacity");var $growSlice=i((r,e)=>{let n=r.$array,t=r.$offset;const o=r.$length;let a=r.$capacity;if(e>a){a=$calculateNewCapacity(e,a);let $;if(n.constructor===Array){$=n.slice(t,t+o),$.length=a;const c=r.constructor.elem.zero;for(let f=r.$length;f<a;f++)$[f]=c()}else $=new n.constructor(a),$.set(n.subarray(t,t+o));n=$,t=0}let u=new r.constructor(n);return u.$offset=t,u.$length=o,u.$capacity=a,u},"$growSlice"),$equal=i((r,e,n)=>{if(n===$jsObjectPtr)return r===e;switch(n.kind){case $kindComplex64:case $kindComplex128:return r.$real===e.$real&&r.$imag===e.$imag;case $kindInt64:case $kindUint64:return r.$high===e.$high&&r.$low===e.$low;case $kindArray:if(r.length!==e.length)return!1;for(var t=0;t<r.length;t++)if(!$equal(r[t],e[t],n.elem))return!1;return!0;case $kindStruct:for(var t=0;t<n.fields.length;t++){var o=n.fields[t];if(!$equal(r[o.prop],e[o.prop],o.typ))return!1}return!0;case $kindInterface:return $interfaceIsEqual(r,e);default:return r===e}},"$equal"),$interfaceIsEqual=i((r,e)=>r===$ifaceNil||e===$ifaceNil?r===e:r.constructor!==e.constructor?!1:r.constructor===$jsObjectPtr?r.object===e.object:(r.constructor.comparable||$throwRuntimeError("comparing uncomparable type "+r.constructor.string),$equal(r.$val,e.$val,r.constructor)),"$interfaceIsEqual"),$unsafeMethodToFunction=i((r,e,n)=>n?(t,...o)=>{var a=$ptrType(r);if(t.constructor!=a)switch(r.kind){case $kindStruct:t=$pointerOfStructConversion(t,a);break;case $kindArray:t=new a(t);break;default:t=new a(t.$get,t.$set,t.$target,t.$index)}return t[e](...o)}:(t,...o)=>{var a=$ptrType(r);if(t.constructor!=a)switch(r.kind){case $kindStruct:t=$clone(t,r);break;case $kindSlice:t=$convertSliceType(t,r);break;case $kindComplex64:case $kindComplex128:t=new r(t.$real,t.$imag);break;default:t=new r(t)}return t[e](...o)},"$unsafeMethodToFunction"),$id=i(r=>r,"$id"),$instanceOf=i((r,e)=>r instanceof e,"$instanceOf"),$typeOf=i(r=>typeof r,"$typeOf"),$unsafeString=i((r,e)=>{var n=$sliceType($Uint8);return $bytesToString($unsafeSlice(r,e,n,"String"))},"$unsafeString"),$unsafeStringData=i(r=>{if(r.length===0)return $ptrType($Uint8).nil;var e=$sliceType($Uint8),n=new e($stringToBytes(r));return $unsafeSliceData(n,e)},"$unsafeStringData"),$unsafeSlice=i((r,e,n,t="Slice")=>{e<0&&$throwRuntimeError("unsafe."+t+": len out of range");var o=$ptrType(n.elem);if(r===o.nil||r.$target===void 0)return e>0&&$throwRuntimeError("unsafe."+t+": ptr is nil and len is not zero"),n.nil;if(e===0){var a=new n(r.$target);return a.$offset=r.$index!==void 0?r.$index:0,a.$length=0,a.$capacity=0,a}r.$index===void 0&&$throwRuntimeError("unsafe."+t+": pointer does not address a slice or array element (missing index)"),r.$target.buffer&&r.$target.BYTES_PER_ELEMENT&&r.$target.constructor!==$nativeArray(n.elem.kind)&&$throwRuntimeError("unsafe."+t+": pointer does not match slice element storage layout"),r.$index+e>r.$target.length&&$throwRuntimeError("unsafe."+t+": len out of range");var a=new n(r.$target);return a.$offset=r.$index,a.$length=e,a.$capacity=e,a},"$unsafeSlice"),$unsafeSliceData=i((r,e)=>{var n=$ptrType(e.elem);return r===e.nil?n.nil:$indexPtr(r.$array,r.$offset,n)},"$unsafeSliceData"),$clearSlice=i(r=>{const e=r.$length;if(e===0)return;const n=r.$array,t=r.$offset,o=r.constructor.elem.zero;for(let a=0;a<e;a++)n[t+a]=o()},"$clearSlice"),$clearMap=i(r=>{typeof r.clear=="function"&&r.clear()},"$clearMap");
+var m=Object.defineProperty;var o=(r,a)=>m(r,"name",{value:a,configurable:!0});var $min=Math.min,$max=Math.max,$less64=o((r,a)=>r.$high<a.$high||r.$high===a.$high&&r.$low<a.$low,"$less64"),$min64=o((r,...a)=>a.reduce((e,i)=>$less64(i,e)?i:e,r),"$min64"),$max64=o((r,...a)=>a.reduce((e,i)=>$less64(e,i)?i:e,r),"$max64"),$minStr=o((r,...a)=>a.reduce((e,i)=>i<e?i:e,r),"$minStr"),$maxStr=o((r,...a)=>a.reduce((e,i)=>e<i?i:e,r),"$maxStr"),$mod=o((r,a)=>r%a,"$mod"),$parseInt=parseInt,$parseFloat=o(r=>r!=null&&r.constructor===Number?r:parseFloat(r),"$parseFloat"),$froundBuf=new Float32Array(1),$fround=Math.fround||(r=>($froundBuf[0]=r,$froundBuf[0])),$imul=Math.imul||((r,a)=>{var e=r>>>16&65535,i=r&65535,l=a>>>16&65535,n=a&65535;return i*n+(e*n+i*l<<16>>>0)>>0}),$floatKey=o(r=>r!==r?($idCounter++,"NaN$"+$idCounter):String(r),"$floatKey"),$flatten64=o(r=>r.$high*4294967296+r.$low,"$flatten64"),$shiftLeft64=o((r,a)=>a===0?r:a<32?new r.constructor(r.$high<<a|r.$low>>>32-a,r.$low<<a>>>0):a<64?new r.constructor(r.$low<<a-32,0):new r.constructor(0,0),"$shiftLeft64"),$shiftRightInt64=o((r,a)=>a===0?r:a<32?new r.constructor(r.$high>>a,(r.$low>>>a|r.$high<<32-a)>>>0):a<64?new r.constructor(r.$high>>31,r.$high>>a-32>>>0):r.$high<0?new r.constructor(-1,4294967295):new r.constructor(0,0),"$shiftRightInt64"),$shiftRightUint64=o((r,a)=>a===0?r:a<32?new r.constructor(r.$high>>>a,(r.$low>>>a|r.$high<<32-a)>>>0):a<64?new r.constructor(0,r.$high>>>a-32):new r.constructor(0,0),"$shiftRightUint64"),$mul64=o((r,a)=>{var e=r.$high>>>16,i=r.$high&65535,l=r.$low>>>16,n=r.$low&65535,f=a.$high>>>16,$=a.$high&65535,t=a.$low>>>16,u=a.$low&65535,v=0,h=0,g=0,F=0;F+=n*u,g+=F>>>16,F&=65535,g+=l*u,h+=g>>>16,g&=65535,g+=n*t,h+=g>>>16,g&=65535,h+=i*u,v+=h>>>16,h&=65535,h+=l*t,v+=h>>>16,h&=65535,h+=n*$,v+=h>>>16,h&=65535,v+=e*u+i*t+l*$+n*f,v&=65535;var c=(v<<16|h)>>>0,w=(g<<16|F)>>>0,s=new r.constructor(c,w);return s},"$mul64"),$div64=o((r,a,e)=>{a.$high===0&&a.$low===0&&$throwRuntimeError("integer divide by zero");var i=1,l=1,n=r.$high,f=r.$low;n<0&&(i=-1,l=-1,n=-n,f!==0&&(n--,f=4294967296-f));var $=a.$high,t=a.$low;a.$high<0&&(i*=-1,$=-$,t!==0&&($--,t=4294967296-t));for(var u=0,v=0,h=0;$<2147483648&&(n>$||n===$&&f>t);)$=($<<1|t>>>31)>>>0,t=t<<1>>>0,h++;for(var g=0;g<=h;g++)u=u<<1|v>>>31,v=v<<1>>>0,(n>$||n===$&&f>=t)&&(n=n-$,f=f-t,f<0&&(n--,f+=4294967296),v++,v===4294967296&&(u++,v=0)),t=(t>>>1|$<<31)>>>0,$=$>>>1;return e?new r.constructor(n*l,f*l):new r.constructor(u*i,v*i)},"$div64"),$divComplex=o((r,a)=>{var e=r.$real===1/0||r.$real===-1/0||r.$imag===1/0||r.$imag===-1/0,i=a.$real===1/0||a.$real===-1/0||a.$imag===1/0||a.$imag===-1/0,l=!e&&(r.$real!==r.$real||r.$imag!==r.$imag),n=!i&&(a.$real!==a.$real||a.$imag!==a.$imag);if(l||n)return new r.constructor(NaN,NaN);if(e&&!i)return new r.constructor(1/0,1/0);if(!e&&i)return new r.constructor(0,0);if(a.$real===0&&a.$imag===0)return r.$real===0&&r.$imag===0?new r.constructor(NaN,NaN):new r.constructor(1/0,1/0);var f=Math.abs(a.$real),$=Math.abs(a.$imag);if(f<=$){var t=a.$real/a.$imag,u=a.$real*t+a.$imag;return new r.constructor((r.$real*t+r.$imag)/u,(r.$imag*t-r.$real)/u)}var t=a.$imag/a.$real,u=a.$imag*t+a.$real;return new r.constructor((r.$imag*t+r.$real)/u,(r.$imag-r.$real*t)/u)},"$divComplex");
+var g=Object.defineProperty;var s=(e,t)=>g(e,"name",{value:t,configurable:!0});var $kindBool=1,$kindInt=2,$kindInt8=3,$kindInt16=4,$kindInt32=5,$kindInt64=6,$kindUint=7,$kindUint8=8,$kindUint16=9,$kindUint32=10,$kindUint64=11,$kindUintptr=12,$kindFloat32=13,$kindFloat64=14,$kindComplex64=15,$kindComplex128=16,$kindArray=17,$kindChan=18,$kindFunc=19,$kindInterface=20,$kindMap=21,$kindPtr=22,$kindSlice=23,$kindString=24,$kindStruct=25,$kindUnsafePointer=26,$methodSynthesizers=[],$addMethodSynthesizer=s(e=>{if($methodSynthesizers===null){e();return}$methodSynthesizers.push(e)},"$addMethodSynthesizer"),$synthesizeMethods=s(()=>{$methodSynthesizers.forEach(e=>{e()}),$methodSynthesizers=null},"$synthesizeMethods"),$ifaceKeyFor=s(e=>{if(e===$ifaceNil)return"nil";var t=e.constructor;return t.string+"$"+t.keyFor(e.$val)},"$ifaceKeyFor"),$identity=s(e=>e,"$identity"),$typeIDCounter=0,$idKey=s(e=>(e.$id===void 0&&($idCounter++,e.$id=$idCounter),String(e.$id)),"$idKey"),$arrayPtrCtor=s(()=>function(e){this.$get=()=>e,this.$set=function(t){typ.copy(this,t)},this.$val=e},"$arrayPtrCtor"),$newType=s((e,t,a,i,o,$,f)=>{var r;switch(t){case $kindBool:case $kindInt:case $kindInt8:case $kindInt16:case $kindInt32:case $kindUint:case $kindUint8:case $kindUint16:case $kindUint32:case $kindUintptr:case $kindUnsafePointer:r=s(function(n){this.$val=n},"typ"),r.wrapped=!0,r.keyFor=$identity;break;case $kindString:r=s(function(n){this.$val=n},"typ"),r.wrapped=!0,r.keyFor=n=>"$"+n;break;case $kindFloat32:case $kindFloat64:r=s(function(n){this.$val=n},"typ"),r.wrapped=!0,r.keyFor=n=>$floatKey(n);break;case $kindInt64:r=s(function(n,u){this.$high=n+Math.floor(Math.ceil(u)/4294967296)>>0,this.$low=u>>>0,this.$val=this},"typ"),r.keyFor=n=>n.$high+"$"+n.$low;break;case $kindUint64:r=s(function(n,u){this.$high=n+Math.floor(Math.ceil(u)/4294967296)>>>0,this.$low=u>>>0,this.$val=this},"typ"),r.keyFor=n=>n.$high+"$"+n.$low;break;case $kindComplex64:r=s(function(n,u){this.$real=$fround(n),this.$imag=$fround(u),this.$val=this},"typ"),r.keyFor=n=>n.$real+"$"+n.$imag;break;case $kindComplex128:r=s(function(n,u){this.$real=n,this.$imag=u,this.$val=this},"typ"),r.keyFor=n=>n.$real+"$"+n.$imag;break;case $kindArray:r=s(function(n){this.$val=n},"typ"),r.wrapped=!0,r.ptr=$newType(4,$kindPtr,"*"+a,!1,"",!1,$arrayPtrCtor()),r.init=(n,u)=>{r.elem=n,r.len=u,r.comparable=n.comparable,r.keyFor=c=>Array.prototype.join.call($mapArray(c,l=>String(n.keyFor(l)).replace(/\\/g,"\\\\").replace(/\$/g,"\\$")),"$"),r.copy=(c,l)=>{l.length===void 0?(l.$length<c.length&&$throwRuntimeError("cannot convert slice with length "+l.$length+" to array or pointer to array with length "+c.length),$copyArray(c,l.$array,0,l.$offset,c.length,n)):$copyArray(c,l,0,0,l.length,n)},r.ptr.init(r),Object.defineProperty(r.ptr.nil,"nilCheck",{get:$throwNilPointerError})};break;case $kindChan:r=s(function(n){this.$val=n},"typ"),r.wrapped=!0,r.keyFor=$idKey,r.init=(n,u,c)=>{r.elem=n,r.sendOnly=u,r.recvOnly=c};break;case $kindFunc:r=s(function(n){this.$val=n},"typ"),r.wrapped=!0,r.init=(n,u,c)=>{r.params=n,r.results=u,r.variadic=c,r.comparable=!1};break;case $kindInterface:r={implementedBy:{},missingMethodFor:{}},r.keyFor=$ifaceKeyFor,r.init=n=>{r.methods=n,n.forEach(u=>{$ifaceNil[u.prop]=$throwNilPointerError})};break;case $kindMap:r=s(function(n){this.$val=n},"typ"),r.wrapped=!0,r.init=(n,u)=>{r.key=n,r.elem=u,r.comparable=!1};break;case $kindPtr:r=f||function(n,u,c,l){this.$get=n,this.$set=u,this.$target=c,l!==void 0&&(this.$index=l),this.$val=this},r.keyFor=$idKey,r.init=n=>{r.elem=n,r.wrapped=n.kind===$kindArray,r.nil=new r($throwNilPointerError,$throwNilPointerError)};break;case $kindSlice:r=s(function(n){n.constructor!==r.nativeArray&&(n=new r.nativeArray(n)),this.$array=n,this.$offset=0,this.$length=n.length,this.$capacity=n.length,this.$val=this},"typ"),r.init=n=>{r.elem=n,r.comparable=!1,r.nativeArray=$nativeArray(n.kind),r.nil=new r([]),Object.freeze(r.nil)};break;case $kindStruct:r=s(function(n){this.$val=n},"typ"),r.wrapped=!0,r.ptr=$newType(4,$kindPtr,"*"+a,!1,o,$,f),r.ptr.elem=r,r.ptr.prototype.$get=function(){return this},r.ptr.prototype.$set=function(n){r.copy(this,n)},r.init=(n,u)=>{r.pkgPath=n,r.fields=u,u.forEach(l=>{l.typ.comparable||(r.comparable=!1)}),r.keyFor=l=>{var p=l.$val;return $mapArray(u,h=>String(h.typ.keyFor(p[h.prop])).replace(/\\/g,"\\\\").replace(/\$/g,"\\$")).join("$")},r.copy=(l,p)=>{for(var h=0;h<u.length;h++){var d=u[h];switch(d.typ.kind){case $kindArray:case $kindStruct:d.typ.copy(l[d.prop],p[d.prop]);continue;default:l[d.prop]=p[d.prop];continue}}};var c={};u.forEach(l=>{c[l.prop]={get:$throwNilPointerError,set:$throwNilPointerError}}),r.ptr.nil=Object.create(f.prototype,c),r.ptr.nil.$val=r.ptr.nil,$addMethodSynthesizer(()=>{var l=s((p,h,d)=>{p.prototype[h.prop]===void 0&&(p.prototype[h.prop]=function(...y){var k=this.$val[d.prop];return d.typ===$jsObjectPtr&&(k=new $jsObjectPtr(k)),k.$val===void 0&&(k=new d.typ(k)),k[h.prop](...y)})},"synthesizeMethod");u.forEach(p=>{p.embedded&&($methodSet(p.typ).forEach(h=>{l(r,h,p),l(r.ptr,h,p)}),$methodSet($ptrType(p.typ)).forEach(h=>{l(r.ptr,h,p)}))})})};break;default:$panic(new $String("invalid kind: "+t))}switch(t){case $kindBool:case $kindMap:r.zero=()=>!1;break;case $kindInt:case $kindInt8:case $kindInt16:case $kindInt32:case $kindUint:case $kindUint8:case $kindUint16:case $kindUint32:case $kindUintptr:case $kindUnsafePointer:case $kindFloat32:case $kindFloat64:r.zero=()=>0;break;case $kindString:r.zero=()=>"";break;case $kindInt64:case $kindUint64:case $kindComplex64:case $kindComplex128:var v=new r(0,0);r.zero=()=>v;break;case $kindPtr:case $kindSlice:r.zero=()=>r.nil;break;case $kindChan:r.zero=()=>$chanNil;break;case $kindFunc:r.zero=()=>$throwNilPointerError;break;case $kindInterface:r.zero=()=>$ifaceNil;break;case $kindArray:r.zero=()=>{var n=$nativeArray(r.elem.kind);if(n!==Array)return new n(r.len);for(var u=new Array(r.len),c=0;c<r.len;c++)u[c]=r.elem.zero();return u};break;case $kindStruct:r.zero=()=>new r.ptr;break;default:$panic(new $String("invalid kind: "+t))}return r.id=$typeIDCounter,$typeIDCounter++,r.size=e,r.kind=t,r.string=a,r.named=i,r.pkg=o,r.exported=$,r.methods=[],r.methodSetCache=null,r.comparable=!0,r},"$newType"),$methodSet=s(e=>{if(e.methodSetCache!==null)return e.methodSetCache;var t={},a=e.kind===$kindPtr;if(a&&e.elem.kind===$kindInterface)return e.methodSetCache=[],[];for(var i=[{typ:a?e.elem:e,indirect:a,shadow:void 0}],o={};i.length>0;){var $=[],f={};i.forEach(r=>{if(o[r.typ.id])return;o[r.typ.id]=!0;const v=s((c,l)=>{if(f[c]!==null){if(r.shadow&&r.shadow[c])return;f[c]===void 0?f[c]=l:f[c]!==l&&(f[c]=null)}},"promotePair"),n=s(c=>{c.forEach(l=>v(l.name,l))},"promote");switch(r.typ.named&&(n(r.typ.methods),r.indirect&&n($ptrType(r.typ).methods)),r.typ.kind){case $kindStruct:var u={};Object.assign(u,r.shadow),r.typ.fields.forEach(c=>{u[c.name]=!0,v(c.name,null)}),r.typ.fields.forEach(c=>{if(c.embedded){var l=c.typ,p=l.kind===$kindPtr;$.push({typ:p?l.elem:l,indirect:r.indirect||p,shadow:u})}});break;case $kindInterface:n(r.typ.methods);break}});for(const[r,v]of Object.entries(f))v!==null&&t[r]===void 0&&(t[r]=v);i=$}return e.methodSetCache=[],Object.keys(t).sort().forEach(r=>{e.methodSetCache.push(t[r])}),e.methodSetCache},"$methodSet"),$Bool=$newType(1,$kindBool,"bool",!0,"",!1,null),$Int=$newType(4,$kindInt,"int",!0,"",!1,null),$Int8=$newType(1,$kindInt8,"int8",!0,"",!1,null),$Int16=$newType(2,$kindInt16,"int16",!0,"",!1,null),$Int32=$newType(4,$kindInt32,"int32",!0,"",!1,null),$Int64=$newType(8,$kindInt64,"int64",!0,"",!1,null),$Uint=$newType(4,$kindUint,"uint",!0,"",!1,null),$Uint8=$newType(1,$kindUint8,"uint8",!0,"",!1,null),$Uint16=$newType(2,$kindUint16,"uint16",!0,"",!1,null),$Uint32=$newType(4,$kindUint32,"uint32",!0,"",!1,null),$Uint64=$newType(8,$kindUint64,"uint64",!0,"",!1,null),$Uintptr=$newType(4,$kindUintptr,"uintptr",!0,"",!1,null),$Float32=$newType(4,$kindFloat32,"float32",!0,"",!1,null),$Float64=$newType(8,$kindFloat64,"float64",!0,"",!1,null),$Complex64=$newType(8,$kindComplex64,"complex64",!0,"",!1,null),$Complex128=$newType(16,$kindComplex128,"complex128",!0,"",!1,null),$String=$newType(8,$kindString,"string",!0,"",!1,null),$UnsafePointer=$newType(4,$kindUnsafePointer,"unsafe.Pointer",!0,"unsafe",!1,null),$nativeArray=s(e=>{switch(e){case $kindInt:return Int32Array;case $kindInt8:return Int8Array;case $kindInt16:return Int16Array;case $kindInt32:return Int32Array;case $kindUint:return Uint32Array;case $kindUint8:return Uint8Array;case $kindUint16:return Uint16Array;case $kindUint32:return Uint32Array;case $kindUintptr:return Uint32Array;case $kindFloat32:return Float32Array;case $kindFloat64:return Float64Array;default:return Array}},"$nativeArray"),$toNativeArray=s((e,t)=>{var a=$nativeArray(e);return a===Array?t:new a(t)},"$toNativeArray"),$arrayTypes={},$arrayType=s((e,t)=>{var a=e.id+"$"+t,i=$arrayTypes[a];return i===void 0&&(i=$newType(e.size*t,$kindArray,"["+t+"]"+e.string,!1,"",!1,null),$arrayTypes[a]=i,i.init(e,t)),i},"$arrayType"),$chanType=s((e,t,a)=>{var i=(a?"<-":"")+"chan"+(t?"<- ":" ");!t&&!a&&e.string[0]=="<"?i+="("+e.string+")":i+=e.string;var o=t?"SendChan":a?"RecvChan":"Chan",$=e[o];return $===void 0&&($=$newType(4,$kindChan,i,!1,"",!1,null),e[o]=$,$.init(e,t,a)),$},"$chanType"),$Chan=s(function(e,t){(t<0||t>2147483647)&&$throwRuntimeError("makechan: size out of range"),this.$elem=e,this.$capacity=t,this.$buffer=[],this.$sendQueue=[],this.$recvQueue=[],this.$closed=!1},"$Chan"),$chanNil=new $Chan(null,0);$chanNil.$sendQueue=$chanNil.$recvQueue={length:0,push(){},shift(){},indexOf(){return-1}};var $funcTypes={},$funcType=s((e,t,a)=>{var i=$mapArray(e,r=>r.id).join(",")+"$"+$mapArray(t,r=>r.id).join(",")+"$"+a,o=$funcTypes[i];if(o===void 0){var $=$mapArray(e,r=>r.string);a&&($[$.length-1]="..."+$[$.length-1].substring(2));var f="func("+$.join(", ")+")";t.length===1?f+=" "+t[0].string:t.length>1&&(f+=" ("+$mapArray(t,r=>r.string).join(", ")+")"),o=$newType(4,$kindFunc,f,!1,"",!1,null),$funcTypes[i]=o,o.init(e,t,a)}return o},"$funcType"),$interfaceTypes={},$interfaceType=s(e=>{var t=$mapArray(e,o=>o.pkg+","+o.name+","+o.typ.id).join("$"),a=$interfaceTypes[t];if(a===void 0){var i="interface {}";e.length!==0&&(i="interface { "+$mapArray(e,o=>(o.pkg!==""?o.pkg+".":"")+o.name+o.typ.string.substring(4)).join("; ")+" }"),a=$newType(8,$kindInterface,i,!1,"",!1,null),$interfaceTypes[t]=a,a.init(e)}return a},"$interfaceType"),$emptyInterface=$interfaceType([]),$ifaceNil={},$error=$newType(8,$kindInterface,"error",!0,"",!1,null);$error.init([{prop:"Error",name:"Error",pkg:"",typ:$funcType([],[$String],!1)}]);var $mapTypes={},$mapType=s((e,t)=>{var a=e.id+"$"+t.id,i=$mapTypes[a];return i===void 0&&(i=$newType(4,$kindMap,"map["+e.string+"]"+t.string,!1,"",!1,null),$mapTypes[a]=i,i.init(e,t)),i},"$mapType"),$makeMap=s((e,t)=>{for(var a=new Map,i=0;i<t.length;i++){var o=t[i];a.set(e(o.k),o)}return a},"$makeMap"),$ptrType=s(e=>{var t=e.ptr;return t===void 0&&(t=$newType(4,$kindPtr,"*"+e.string,!1,"",e.exported,null),e.ptr=t,t.init(e)),t},"$ptrType"),$newDataPointer=s((e,t)=>t.elem.kind===$kindStruct?e:new t(()=>e,a=>{e=a}),"$newDataPointer"),$indexPtrGet=s(function(){return this.$target[this.$index]},"$indexPtrGet"),$indexPtrSet=s(function(e){this.$target[this.$index]=e},"$indexPtrSet"),$indexPtr=s((e,t,a)=>{var i=s(()=>{if(a.elem.kind===$kindStruct||a.elem.kind===$kindArray){var r=e[t];return r===void 0&&(r=e[t]=a.elem.zero()),r.$val=r,r.$target=e,r.$index=t,r.$get=$indexPtrGet,r.$set=v=>{a.elem.copy(e[t],v)},r}return new a($indexPtrGet,$indexPtrSet,e,t)},"makeIndexPtr");if(e.buffer){var o=e.buffer.$ptr=e.buffer.$ptr||{},$=o[e.name]=o[e.name]||{},f=e.BYTES_PER_ELEMENT*t+e.byteOffset;return $[f]||($[f]=i())}else return e.$ptr=e.$ptr||{},e.$ptr[t]||(e.$ptr[t]=i())},"$indexPtr"),$sliceType=s(e=>{var t=e.slice;return t===void 0&&(t=$newType(12,$kindSlice,"[]"+e.string,!1,"",!1,null),e.slice=t,t.init(e)),t},"$sliceType"),$makeSlice=s((e,t,a=t)=>{(t<0||t>2147483647)&&$throwRuntimeError("makeslice: len out of range"),(a<0||a<t||a>2147483647)&&$throwRuntimeError("makeslice: cap out of range");var i=new e.nativeArray(a);if(e.nativeArray===Array)for(var o=0;o<a;o++)i[o]=e.elem.zero();var $=new e(i);return $.$length=t,$},"$makeSlice"),$structTypes={},$structType=s((e,t)=>{var a=$mapArray(t,$=>$.name+","+$.typ.id+","+$.tag).join("$"),i=$structTypes[a];if(i===void 0){var o="struct { "+$mapArray(t,$=>{var f=$.typ.string+($.tag!==""?' "'+$.tag.replace(/\\/g,"\\\\").replace(/"/g,'\\"')+'"':"");return $.embedded?f:$.name+" "+f}).join("; ")+" }";t.length===0&&(o="struct {}"),i=$newType(0,$kindStruct,o,!1,"",!1,function(...$){this.$val=this;for(var f=0;f<t.length;f++){var r=t[f];if(r.name!="_"){var v=$[f];this[r.prop]=v!==void 0?v:r.typ.zero()}}}),$structTypes[a]=i,i.init(e,t)}return i},"$structType"),$assertType=s((e,t,a)=>{var i=t.kind===$kindInterface,o,$="";if(e===$ifaceNil)o=!1;else if(!i)o=e.constructor===t;else{var f=e.constructor.id;if(o=t.implementedBy[f],o===void 0){o=!0;for(var r=$methodSet(e.constructor),v=t.methods,n=0;n<v.length;n++){for(var u=v[n],c=!1,l=0;l<r.length;l++){var p=r[l];if(p.name===u.name&&p.pkg===u.pkg&&p.typ===u.typ){c=!0;break}}if(!c){o=!1,t.missingMethodFor[f]=u.name;break}}t.implementedBy[f]=o}o||($=t.missingMethodFor[f])}if(!o){if(a)return[t.zero(),!1];$panic(new $packages.runtime.TypeAssertionError.ptr($packages.runtime._type.ptr.nil,e===$ifaceNil?$packages.runtime._type.ptr.nil:new $packages.runtime._type.ptr(e.constructor.string),new $packages.runtime._type.ptr(t.string),$))}return i||(e=e.$val),t===$jsObjectPtr&&(e=e.object),a?[e,!0]:e},"$assertType");
+var p=Object.defineProperty;var i=(e,t)=>p(e,"name",{value:t,configurable:!0});var $stackDepthOffset=0,$getStackDepth=i(()=>{var e=new Error;if(e.stack!==void 0)return $stackDepthOffset+e.stack.split(`
+`).length},"$getStackDepth"),$panicStackDepth=null,$panicValue,$callDeferred=i((e,t,r)=>{if(!r&&e!==null&&$curGoroutine.deferStack.indexOf(e)==-1)throw t;if(t!==null){var n=null;try{$panic(new $jsErrorPtr(t))}catch(c){n=c}$callDeferred(e,n);return}if(!$curGoroutine.asleep){$stackDepthOffset--;var a=$panicStackDepth,l=$panicValue,u=$curGoroutine.panicStack.pop();u!==void 0&&($panicStackDepth=$getStackDepth(),$panicValue=u);try{for(;;){if(e===null&&(e=$curGoroutine.deferStack[$curGoroutine.deferStack.length-1],e===void 0)){if($panicStackDepth=null,u.Object instanceof Error)throw u.Object;var o;throw u.constructor===$String?o=u.$val:u.Error!==void 0?o=u.Error():u.String!==void 0?o=u.String():o=u,new Error(o)}var s=e.pop();if(s===void 0){if($curGoroutine.deferStack.pop(),u!==void 0){e=null;continue}return}var v=s[0].apply(s[2],s[1]);if(v&&v.$blk!==void 0){if(e.push([v.$blk,[],v]),r)throw null;return}if(u!==void 0&&$panicStackDepth===null){if(r)throw null;return}}}catch(c){if(r)throw c;$callDeferred(e,c,r)}finally{u!==void 0&&($panicStackDepth!==null&&$curGoroutine.panicStack.push(u),$panicStackDepth=a,$panicValue=l),$stackDepthOffset++}}},"$callDeferred"),$panicnil="0",$panic=i(e=>{e===$ifaceNil&&$panicnil!=="1"&&(e=$newPanicNilError()),$curGoroutine.panicStack.push(e),$callDeferred(null,null,!0)},"$panic"),$recover=i(()=>$panicStackDepth===null||$panicStackDepth!==void 0&&$panicStackDepth!==$getStackDepth()-2?$ifaceNil:($panicStackDepth=null,$panicValue),"$recover"),$throw=i(e=>{throw e},"$throw"),$noGoroutine={asleep:!1,exit:!1,deferStack:[],panicStack:[]},$curGoroutine=$noGoroutine,$totalGoroutines=0,$awakeGoroutines=0,$checkForDeadlock=!0,$exportedFunctions=0,$mainFinished=!1,$go=i((e,t)=>{$totalGoroutines++,$awakeGoroutines++;var r=i(()=>{try{$curGoroutine=r;var n=e(...t);if(n&&n.$blk!==void 0){e=i(()=>n.$blk(),"fun"),t=[];return}r.exit=!0}catch(a){if(!r.exit)throw a}finally{$curGoroutine=$noGoroutine,r.exit&&($totalGoroutines--,r.asleep=!0),r.asleep&&($awakeGoroutines--,!$mainFinished&&$awakeGoroutines===0&&$checkForDeadlock&&$exportedFunctions===0&&(console.error("fatal error: all goroutines are asleep - deadlock!"),$global.process!==void 0&&$global.process.exit(2)))}},"$goroutine");r.asleep=!1,r.exit=!1,r.deferStack=[],r.panicStack=[],$schedule(r)},"$go"),$scheduled=[],$runScheduled=i(()=>{var e=setTimeout($runScheduled);try{for(var t=Date.now(),r;(r=$scheduled.shift())!==void 0;){r();var n=Date.now()-t;if(n>4||n<0)break}}finally{$scheduled.length==0&&clearTimeout(e)}},"$runScheduled"),$schedule=i(e=>{e.asleep&&(e.asleep=!1,$awakeGoroutines++),$scheduled.push(e),$curGoroutine===$noGoroutine&&$runScheduled()},"$schedule"),$setTimeout=i((e,t)=>($awakeGoroutines++,setTimeout(()=>{$awakeGoroutines--,e()},t)),"$setTimeout"),$block=i(()=>{$curGoroutine===$noGoroutine&&$throwRuntimeError("cannot block in JavaScript callback, fix by wrapping code in goroutine"),$curGoroutine.asleep=!0},"$block"),$restore=i((e,t)=>e!==void 0&&e.$blk!==void 0?e:t,"$restore"),$send=i((e,t)=>{e.$closed&&$throwRuntimeError("send on closed channel");var r=e.$recvQueue.shift();if(r!==void 0){r([t,!0]);return}if(e.$buffer.length<e.$capacity){e.$buffer.push(t);return}var n=$curGoroutine,a;return e.$sendQueue.push(l=>(a=l,$schedule(n),t)),$block(),{$blk(){a&&$throwRuntimeError("send on closed channel")}}},"$send"),$recv=i(e=>{var t=e.$sendQueue.shift();t!==void 0&&e.$buffer.push(t(!1));var r=e.$buffer.shift();if(r!==void 0)return[r,!0];if(e.$closed)return[e.$elem.zero(),!1];var n=$curGoroutine,a={$blk(){return this.value}},l=i(u=>{a.value=u,$schedule(n)},"queueEntry");return e.$recvQueue.push(l),$block(),a},"$recv"),$close=i(e=>{for(e.$closed&&$throwRuntimeError("close of closed channel"),e.$closed=!0;;){var t=e.$sendQueue.shift();if(t===void 0)break;t(!0)}for(;;){var r=e.$recvQueue.shift();if(r===void 0)break;r([e.$elem.zero(),!1])}},"$close"),$select=i(e=>{for(var t=[],r=-1,n=0;n<e.length;n++){var a=e[n],l=a[0];switch(a.length){case 0:r=n;break;case 1:(l.$sendQueue.length!==0||l.$buffer.length!==0||l.$closed)&&t.push(n);break;case 2:l.$closed&&$throwRuntimeError("send on closed channel"),(l.$recvQueue.length!==0||l.$buffer.length<l.$capacity)&&t.push(n);break}}if(t.length!==0&&(r=t[Math.floor(Math.random()*t.length)]),r!==-1){var a=e[r];switch(a.length){case 0:return[r];case 1:return[r,$recv(a[0])];case 2:return $send(a[0],a[1]),[r]}}for(var u=[],o=$curGoroutine,s={$blk(){return this.selection}},v=i(()=>{for(var c=0;c<u.length;c++){var d=u[c],f=d[0],$=f.indexOf(d[1]);$!==-1&&f.splice($,1)}},"removeFromQueues"),n=0;n<e.length;n++)(d=>{var f=e[d];switch(f.length){case 1:var $=i(h=>{s.selection=[d,h],v(),$schedule(o)},"queueEntry");u.push([f[0].$recvQueue,$]),f[0].$recvQueue.push($);break;case 2:var $=i(()=>(f[0].$closed&&$throwRuntimeError("send on closed channel"),s.selection=[d],v(),$schedule(o),f[1]),"queueEntry");u.push([f[0].$sendQueue,$]),f[0].$sendQueue.push($);break}})(n);return $block(),s},"$select");
+var R=Object.defineProperty;var k=(r,e)=>R(r,"name",{value:e,configurable:!0});var $jsObjectPtr,$jsErrorPtr,$needsExternalization=k(r=>{switch(r.kind){case $kindBool:case $kindInt:case $kindInt8:case $kindInt16:case $kindInt32:case $kindUint:case $kindUint8:case $kindUint16:case $kindUint32:case $kindUintptr:case $kindFloat32:case $kindFloat64:return!1;default:return r!==$jsObjectPtr}},"$needsExternalization"),$externalize=k((r,e,i)=>{if(e===$jsObjectPtr)return r;switch(e.kind){case $kindBool:case $kindInt:case $kindInt8:case $kindInt16:case $kindInt32:case $kindUint:case $kindUint8:case $kindUint16:case $kindUint32:case $kindUintptr:case $kindFloat32:case $kindFloat64:return r;case $kindInt64:case $kindUint64:return $flatten64(r);case $kindArray:return $needsExternalization(e.elem)?$mapArray(r,$=>$externalize($,e.elem,i)):r;case $kindFunc:return $externalizeFunction(r,e,!1,i);case $kindInterface:return r===$ifaceNil?null:r.constructor===$jsObjectPtr?r.$val.object:$externalize(r.$val,r.constructor,i);case $kindMap:if(r.keys===void 0)return null;for(var a={},t=Array.from(r.keys()),n=0;n<t.length;n++){var h=r.get(t[n]);a[$externalize(h.k,e.key,i)]=$externalize(h.v,e.elem,i)}return a;case $kindPtr:return r===e.nil?null:$externalize(r.$get(),e.elem,i);case $kindSlice:return r===r.constructor.nil?null:$needsExternalization(e.elem)?$mapArray($sliceToNativeArray(r),$=>$externalize($,e.elem,i)):$sliceToNativeArray(r);case $kindString:if($isASCII(r))return r;for(var f="",d,n=0;n<r.length;n+=d[1]){d=$decodeRune(r,n);var l=d[0];if(l>65535){var F=Math.floor((l-65536)/1024)+55296,x=(l-65536)%1024+56320;f+=String.fromCharCode(F,x);continue}f+=String.fromCharCode(l)}return f;case $kindStruct:var u=$packages.time;if(u!==void 0&&r.constructor===u.Time.ptr){var I=$div64(r.UnixNano(),new $Int64(0,1e6));return new Date($flatten64(I))}var m={},p=k(($,g)=>{if(g===$jsObjectPtr)return $;switch(g.kind){case $kindPtr:return $===g.nil?m:p($.$get(),g.elem);case $kindStruct:if(g.fields.length===0)return m;var y=g.fields[0];return p($[y.prop],y.typ);case $kindInterface:return p($.$val,$.constructor);default:return m}},"searchJsObject"),o=p(r,e);if(o!==m)return o;if(i!==void 0)return i(r);o={};for(var n=0;n<e.fields.length;n++){var w=e.fields[n];w.exported&&(o[w.name]=$externalize(r[w.prop],w.typ,i))}return o}$throwRuntimeError("cannot externalize "+e.string)},"$externalize"),$externalizeFunction=k((r,e,i,a)=>r===$throwNilPointerError?null:(r.$externalizeWrapper===void 0&&($checkForDeadlock=!1,r.$externalizeWrapper=function(){for(var t=[],n=0;n<e.params.length;n++){if(e.variadic&&n===e.params.length-1){for(var h=e.params[n].elem,f=[],d=n;d<arguments.length;d++)f.push($internalize(arguments[d],h,a));t.push(new e.params[n](f));break}t.push($internalize(arguments[n],e.params[n],a))}var l=r.apply(i?this:void 0,t);switch(e.results.length){case 0:return;case 1:return $externalize($copyIfRequired(l,e.results[0]),e.results[0],a);default:for(var n=0;n<e.results.length;n++)l[n]=$externalize($copyIfRequired(l[n],e.results[n]),e.results[n],a);return l}}),r.$externalizeWrapper),"$externalizeFunction"),$internalize=k((r,e,i,a,t)=>{if(e===$jsObjectPtr)return r;if(e===$jsObjectPtr.elem&&$throwRuntimeError("cannot internalize js.Object, use *js.Object instead"),r&&r.__internal_object__!==void 0)return $assertType(r.__internal_object__,e,!1);var n=$packages.time;if(n!==void 0&&e===n.Time)return r!=null&&r.constructor===Date||$throwRuntimeError("cannot internalize time.Time from "+typeof r+", must be Date"),n.Unix(new $Int64(0,0),new $Int64(0,r.getTime()*1e6));if(a===void 0&&(a=new Map),a.has(e)||a.set(e,new Map),a.get(e).has(r))return a.get(e).get(r);switch(e.kind){case $kindBool:return!!r;case $kindInt:return parseInt(r);case $kindInt8:return parseInt(r)<<24>>24;case $kindInt16:return parseInt(r)<<16>>16;case $kindInt32:return parseInt(r)>>0;case $kindUint:return parseInt(r);case $kindUint8:return parseInt(r)<<24>>>24;case $kindUint16:return parseInt(r)<<16>>>16;case $kindUint32:case $kindUintptr:return parseInt(r)>>>0;case $kindInt64:case $kindUint64:return new e(0,r);case $kindFloat32:case $kindFloat64:return parseFloat(r);case $kindArray:return r==null&&$throwRuntimeError("cannot internalize "+r+" as a "+e.string),r.length!==e.len&&$throwRuntimeError("got array with wrong size from JavaScript native"),$mapArray(r,c=>$internalize(c,e.elem,t));case $kindFunc:return function(){for(var c=[],s=0;s<e.params.length;s++){if(e.variadic&&s===e.params.length-1){for(var A=e.params[s].elem,U=arguments[s],S=0;S<U.$length;S++)c.push($externalize(U.$array[U.$offset+S],A,t));break}c.push($externalize(arguments[s],e.params[s],t))}var T=r.apply(i,c);switch(e.results.length){case 0:return;case 1:return $internalize(T,e.results[0],t);default:for(var s=0;s<e.results.length;s++)T[s]=$internalize(T[s],e.results[s],t);return T}};case $kindInterface:if(e.methods.length!==0&&$throwRuntimeError("cannot internalize "+e.string),r===null)return $ifaceNil;if(r===void 0)return new $jsObjectPtr(void 0);switch(r.constructor){case Int8Array:return new($sliceType($Int8))(r);case Int16Array:return new($sliceType($Int16))(r);case Int32Array:return new($sliceType($Int))(r);case Uint8Array:return new($sliceType($Uint8))(r);case Uint16Array:return new($sliceType($Uint16))(r);case Uint32Array:return new($sliceType($Uint))(r);case Float32Array:return new($sliceType($Float32))(r);case Float64Array:return new($sliceType($Float64))(r);case Array:return $internalize(r,$sliceType($emptyInterface),t);case Boolean:return new $Bool(!!r);case Date:return n===void 0?new $jsObjectPtr(r):new n.Time($internalize(r,n.Time,t));case(()=>{}).constructor:var h=$funcType([$sliceType($emptyInterface)],[$jsObjectPtr],!0);return new h($internalize(r,h,t));case Number:return new $Float64(parseFloat(r));case String:return new $String($internalize(r,$String,t));default:if($global.Node&&r instanceof $global.Node)return new $jsObjectPtr(r);var f=$mapType($String,$emptyInterface);return new f($internalize(r,f,i,a,t))}case $kindMap:var d=new Map;a.get(e).set(r,d);for(var l=$keys(r),u=0;u<l.length;u++){var F=$internalize(l[u],e.key,i,a,t);d.set(e.key.keyFor(F),{k:F,v:$internalize(r[l[u]],e.elem,i,a,t)})}return d;case $kindPtr:if(e.elem.kind===$kindStruct)return $internalize(r,e.elem,t);case $kindSlice:return r==null?e.zero():new e($mapArray(r,c=>$internalize(c,e.elem,t)));case $kindString:if(r=String(r),$isASCII(r))return r;for(var x="",u=0;u<r.length;){var I=r.charCodeAt(u);if(55296<=I&&I<=56319){var m=r.charCodeAt(u+1),p=(I-55296)*1024+m-56320+65536;x+=$encodeRune(p),u+=2;continue}x+=$encodeRune(I),u++}return x;case $kindStruct:var o={},w=k(c=>{if(c===$jsObjectPtr)return r;switch(c===$jsObjectPtr.elem&&$throwRuntimeError("cannot internalize js.Object, use *js.Object instead"),c.kind){case $kindPtr:return w(c.elem);case $kindStruct:if(c.fields.length===0)return o;var s=c.fields[0],A=w(s.typ);if(A!==o){var U=new c.ptr;return U[s.prop]=A,U}return o;default:return o}},"searchJsObject"),$=w(e);if($!==o)return $;for(var g=new e.ptr,u=0;u<e.fields.length;u++){var y=e.fields[u];if(y.exported){var z=r[y.name];g[y.prop]=$internalize(z,y.typ,i,a,t)}}return g}$throwRuntimeError("cannot internalize "+e.string)},"$internalize"),$copyIfRequired=k((r,e)=>{if(r&&r.constructor&&r.constructor.copy)return new r.constructor($clone(r.$val,r.constructor));if(e.copy){var i=e.zero();return e.copy(i,r),i}return r},"$copyIfRequired"),$isASCII=k(r=>{for(var e=0;e<r.length;e++)if(r.charCodeAt(e)>=128)return!1;return!0},"$isASCII");
 
-$packages["github.com/gopherjs/gopherjs/js"]=(function(){var $pkg={},$init,A,B,L,N,Q,K;A=$pkg.Object=$newType(0,$kindStruct,"js.Object",true,"github.com/gopherjs/gopherjs/js",true,function(object_){this.$val=this;if(arguments.length===0){this.object=null;return;}this.object=object_;});B=$pkg.Error=$newType(0,$kindStruct,"js.Error",true,"github.com/gopherjs/gopherjs/js",true,function(Object_){this.$val=this;if(arguments.length===0){this.Object=null;return;}this.Object=Object_;});L=$sliceType($emptyInterface);N=$ptrType(A);Q=$ptrType(B);A.ptr.prototype.Get=function(a){var a,b;b=this;return b.object[$externalize(a,$String)];};A.prototype.Get=function(a){return this.$val.Get(a);};A.ptr.prototype.Set=function(a,b){var a,b,c;c=this;c.object[$externalize(a,$String)]=$externalize(b,$emptyInterface);};A.prototype.Set=function(a,b){return this.$val.Set(a,b);};A.ptr.prototype.Delete=function(a){var a,b;b=this;delete b.object[$externalize(a,$String)];};A.prototype.Delete=function(a){return this.$val.Delete(a);};A.ptr.prototype.Length=function(){var a;a=this;return $parseInt(a.object.length);};A.prototype.Length=function(){return this.$val.Length();};A.ptr.prototype.Index=function(a){var a,b;b=this;return b.object[a];};A.prototype.Index=function(a){return this.$val.Index(a);};A.ptr.prototype.SetIndex=function(a,b){var a,b,c;c=this;c.object[a]=$externalize(b,$emptyInterface);};A.prototype.SetIndex=function(a,b){return this.$val.SetIndex(a,b);};A.ptr.prototype.Call=function(a,b){var a,b,c,d;c=this;return(d=c.object,d[$externalize(a,$String)].apply(d,$externalize(b,L)));};A.prototype.Call=function(a,b){return this.$val.Call(a,b);};A.ptr.prototype.Invoke=function(a){var a,b;b=this;return b.object.apply(undefined,$externalize(a,L));};A.prototype.Invoke=function(a){return this.$val.Invoke(a);};A.ptr.prototype.New=function(a){var a,b;b=this;return new($global.Function.prototype.bind.apply(b.object,[undefined].concat($externalize(a,L))));};A.prototype.New=function(a){return this.$val.New(a);};A.ptr.prototype.Bool=function(){var a;a=this;return!!(a.object);};A.prototype.Bool=function(){return this.$val.Bool();};A.ptr.prototype.String=function(){var a;a=this;return $internalize(a.object,$String);};A.prototype.String=function(){return this.$val.String();};A.ptr.prototype.Int=function(){var a;a=this;return $parseInt(a.object)>>0;};A.prototype.Int=function(){return this.$val.Int();};A.ptr.prototype.Int64=function(){var a;a=this;return $internalize(a.object,$Int64);};A.prototype.Int64=function(){return this.$val.Int64();};A.ptr.prototype.Uint64=function(){var a;a=this;return $internalize(a.object,$Uint64);};A.prototype.Uint64=function(){return this.$val.Uint64();};A.ptr.prototype.Float=function(){var a;a=this;return $parseFloat(a.object);};A.prototype.Float=function(){return this.$val.Float();};A.ptr.prototype.Interface=function(){var a;a=this;return $internalize(a.object,$emptyInterface);};A.prototype.Interface=function(){return this.$val.Interface();};A.ptr.prototype.Unsafe=function(){var a;a=this;return a.object;};A.prototype.Unsafe=function(){return this.$val.Unsafe();};B.ptr.prototype.Error=function(){var a;a=this;return"JavaScript error: "+$internalize(a.Object.message,$String);};B.prototype.Error=function(){return this.$val.Error();};B.ptr.prototype.Stack=function(){var a;a=this;return $internalize(a.Object.stack,$String);};B.prototype.Stack=function(){return this.$val.Stack();};K=function(){var a;a=new B.ptr(null);$unused(a);};N.methods=[{prop:"Get",name:"Get",pkg:"",typ:$funcType([$String],[N],false)},{prop:"Set",name:"Set",pkg:"",typ:$funcType([$String,$emptyInterface],[],false)},{prop:"Delete",name:"Delete",pkg:"",typ:$funcType([$String],[],false)},{prop:"Length",name:"Length",pkg:"",typ:$funcType([],[$Int],false)},{prop:"Index",name:"Index",pkg:"",typ:$funcType([$Int],[N],false)},{prop:"SetIndex",name:"SetIndex",pkg:"",typ:$funcType([$Int,$emptyInterface],[],false)},{prop:"Call",name:"Call",pkg:"",typ:$funcType([$String,L],[N],true)},{prop:"Invoke",name:"Invoke",pkg:"",typ:$funcType([L],[N],true)},{prop:"New",name:"New",pkg:"",typ:$funcType([L],[N],true)},{prop:"Bool",name:"Bool",pkg:"",typ:$funcType([],[$Bool],false)},{prop:"String",name:"String",pkg:"",typ:$funcType([],[$String],false)},{prop:"Int",name:"Int",pkg:"",typ:$funcType([],[$Int],false)},{prop:"Int64",name:"Int64",pkg:"",typ:$funcType([],[$Int64],false)},{prop:"Uint64",name:"Uint64",pkg:"",typ:$funcType([],[$Uint64],false)},{prop:"Float",name:"Float",pkg:"",typ:$funcType([],[$Float64],false)},{prop:"Interface",name:"Interface",pkg:"",typ:$funcType([],[$emptyInterface],false)},{prop:"Unsafe",name:"Unsafe",pkg:"",typ:$funcType([],[$Uintptr],false)}];Q.methods=[{prop:"Error",name:"Error",pkg:"",typ:$funcType([],[$String],false)},{prop:"Stack",name:"Stack",pkg:"",typ:$funcType([],[$String],false)}];A.init("github.com/gopherjs/gopherjs/js",[{prop:"object",name:"object",anonymous:false,exported:false,typ:N,tag:""}]);B.init("",[{prop:"Object",name:"Object",anonymous:true,exported:true,typ:N,tag:""}]);$init=function(){$pkg.$init=function(){};var $f,$c=false,$s=0,$r;if(this!==undefined&&this.$blk!==undefined){$f=this;$c=true;$s=$f.$s;$r=$f.$r;}s:while(true){switch($s){case 0:K();}return;}if($f===undefined){$f={$blk:$init};}$f.$s=$s;$f.$r=$r;return $f;};$pkg.$init=$init;return $pkg;})();
-$packages["runtime/internal/sys"]=(function(){var $pkg={},$init;$init=function(){$pkg.$init=function(){};var $f,$c=false,$s=0,$r;if(this!==undefined&&this.$blk!==undefined){$f=this;$c=true;$s=$f.$s;$r=$f.$r;}s:while(true){switch($s){case 0:}return;}if($f===undefined){$f={$blk:$init};}$f.$s=$s;$f.$r=$r;return $f;};$pkg.$init=$init;return $pkg;})();
-$packages["runtime"]=(function(){var $pkg={},$init,B,A,AP,AQ,BF,E,AJ;B=$packages["github.com/gopherjs/gopherjs/js"];A=$packages["runtime/internal/sys"];AP=$pkg.TypeAssertionError=$newType(0,$kindStruct,"runtime.TypeAssertionError",true,"runtime",true,function(interfaceString_,concreteString_,assertedString_,missingMethod_){this.$val=this;if(arguments.length===0){this.interfaceString="";this.concreteString="";this.assertedString="";this.missingMethod="";return;}this.interfaceString=interfaceString_;this.concreteString=concreteString_;this.assertedString=assertedString_;this.missingMethod=missingMethod_;});AQ=$pkg.errorString=$newType(8,$kindString,"runtime.errorString",true,"runtime",false,null);BF=$ptrType(AP);E=function(){var a,b;a=$packages[$externalize("github.com/gopherjs/gopherjs/js",$String)];$jsObjectPtr=a.Object.ptr;$jsErrorPtr=a.Error.ptr;$throwRuntimeError=AJ;b=$ifaceNil;b=new AP.ptr("","","","");$unused(b);};AJ=function(a){var a;$panic(new AQ((a)));};AP.ptr.prototype.RuntimeError=function(){};AP.prototype.RuntimeError=function(){return this.$val.RuntimeError();};AP.ptr.prototype.Error=function(){var a,b;a=this;b=a.interfaceString;if(b===""){b="interface";}if(a.concreteString===""){return"interface conversion: "+b+" is nil, not "+a.assertedString;}if(a.missingMethod===""){return"interface conversion: "+b+" is "+a.concreteString+", not "+a.assertedString;}return"interface conversion: "+a.concreteString+" is not "+a.assertedString+": missing method "+a.missingMethod;};AP.prototype.Error=function(){return this.$val.Error();};AQ.prototype.RuntimeError=function(){var a;a=this.$val;};$ptrType(AQ).prototype.RuntimeError=function(){return new AQ(this.$get()).RuntimeError();};AQ.prototype.Error=function(){var a;a=this.$val;return"runtime error: "+(a);};$ptrType(AQ).prototype.Error=function(){return new AQ(this.$get()).Error();};BF.methods=[{prop:"RuntimeError",name:"RuntimeError",pkg:"",typ:$funcType([],[],false)},{prop:"Error",name:"Error",pkg:"",typ:$funcType([],[$String],false)}];AQ.methods=[{prop:"RuntimeError",name:"RuntimeError",pkg:"",typ:$funcType([],[],false)},{prop:"Error",name:"Error",pkg:"",typ:$funcType([],[$String],false)}];AP.init("runtime",[{prop:"interfaceString",name:"interfaceString",anonymous:false,exported:false,typ:$String,tag:""},{prop:"concreteString",name:"concreteString",anonymous:false,exported:false,typ:$String,tag:""},{prop:"assertedString",name:"assertedString",anonymous:false,exported:false,typ:$String,tag:""},{prop:"missingMethod",name:"missingMethod",anonymous:false,exported:false,typ:$String,tag:""}]);$init=function(){$pkg.$init=function(){};var $f,$c=false,$s=0,$r;if(this!==undefined&&this.$blk!==undefined){$f=this;$c=true;$s=$f.$s;$r=$f.$r;}s:while(true){switch($s){case 0:$r=B.$init();$s=1;case 1:if($c){$c=false;$r=$r.$blk();}if($r&&$r.$blk!==undefined){break s;}$r=A.$init();$s=2;case 2:if($c){$c=false;$r=$r.$blk();}if($r&&$r.$blk!==undefined){break s;}E();}return;}if($f===undefined){$f={$blk:$init};}$f.$s=$s;$f.$r=$r;return $f;};$pkg.$init=$init;return $pkg;})();
-$packages["errors"]=(function(){var $pkg={},$init,B,C,A;B=$pkg.errorString=$newType(0,$kindStruct,"errors.errorString",true,"errors",false,function(s_){this.$val=this;if(arguments.length===0){this.s="";return;}this.s=s_;});C=$ptrType(B);A=function(a){var a;return new B.ptr(a);};$pkg.New=A;B.ptr.prototype.Error=function(){var a;a=this;return a.s;};B.prototype.Error=function(){return this.$val.Error();};C.methods=[{prop:"Error",name:"Error",pkg:"",typ:$funcType([],[$String],false)}];B.init("errors",[{prop:"s",name:"s",anonymous:false,exported:false,typ:$String,tag:""}]);$init=function(){$pkg.$init=function(){};var $f,$c=false,$s=0,$r;if(this!==undefined&&this.$blk!==undefined){$f=this;$c=true;$s=$f.$s;$r=$f.$r;}s:while(true){switch($s){case 0:}return;}if($f===undefined){$f={$blk:$init};}$f.$s=$s;$f.$r=$r;return $f;};$pkg.$init=$init;return $pkg;})();
-$packages["math"]=(function(){var $pkg={},$init,A,FS,FT,FU,FV,B,AX,AY;A=$packages["github.com/gopherjs/gopherjs/js"];FS=$arrayType($Uint32,2);FT=$arrayType($Float32,2);FU=$arrayType($Float64,1);FV=$structType("math",[{prop:"uint32array",name:"uint32array",anonymous:false,exported:false,typ:FS,tag:""},{prop:"float32array",name:"float32array",anonymous:false,exported:false,typ:FT,tag:""},{prop:"float64array",name:"float64array",anonymous:false,exported:false,typ:FU,tag:""}]);AY=function(){var av;av=new($global.ArrayBuffer)(8);AX.uint32array=new($global.Uint32Array)(av);AX.float32array=new($global.Float32Array)(av);AX.float64array=new($global.Float64Array)(av);};$init=function(){$pkg.$init=function(){};var $f,$c=false,$s=0,$r;if(this!==undefined&&this.$blk!==undefined){$f=this;$c=true;$s=$f.$s;$r=$f.$r;}s:while(true){switch($s){case 0:$r=A.$init();$s=1;case 1:if($c){$c=false;$r=$r.$blk();}if($r&&$r.$blk!==undefined){break s;}AX=new FV.ptr(FS.zero(),FT.zero(),FU.zero());B=$global.Math;AY();}return;}if($f===undefined){$f={$blk:$init};}$f.$s=$s;$f.$r=$r;return $f;};$pkg.$init=$init;return $pkg;})();
-$packages["unicode/utf8"]=(function(){var $pkg={},$init;$init=function(){$pkg.$init=function(){};var $f,$c=false,$s=0,$r;if(this!==undefined&&this.$blk!==undefined){$f=this;$c=true;$s=$f.$s;$r=$f.$r;}s:while(true){switch($s){case 0:}return;}if($f===undefined){$f={$blk:$init};}$f.$s=$s;$f.$r=$r;return $f;};$pkg.$init=$init;return $pkg;})();
-$packages["strconv"]=(function(){var $pkg={},$init,B,A,C;B=$packages["errors"];A=$packages["math"];C=$packages["unicode/utf8"];$init=function(){$pkg.$init=function(){};var $f,$c=false,$s=0,$r;if(this!==undefined&&this.$blk!==undefined){$f=this;$c=true;$s=$f.$s;$r=$f.$r;}s:while(true){switch($s){case 0:$r=B.$init();$s=1;case 1:if($c){$c=false;$r=$r.$blk();}if($r&&$r.$blk!==undefined){break s;}$r=A.$init();$s=2;case 2:if($c){$c=false;$r=$r.$blk();}if($r&&$r.$blk!==undefined){break s;}$r=C.$init();$s=3;case 3:if($c){$c=false;$r=$r.$blk();}if($r&&$r.$blk!==undefined){break s;}$pkg.ErrRange=B.New("value out of range");$pkg.ErrSyntax=B.New("invalid syntax");}return;}if($f===undefined){$f={$blk:$init};}$f.$s=$s;$f.$r=$r;return $f;};$pkg.$init=$init;return $pkg;})();
-$packages["github.com/danielfireman/temp-to-go/vendor/github.com/cnguy/gopherjs-frappe-charts/utils"]=(function(){var $pkg={},$init,A,E,B,C;A=$packages["strconv"];E=$sliceType($emptyInterface);B=function(a){var a;if(!a){return 0;}return 1;};$pkg.Btoi=B;C=function(a){var a,b,c,d,e,f;b=E.nil;b=$makeSlice(E,a.$length);c=a;d=0;while(true){if(!(d<c.$length)){break;}e=d;f=((d<0||d>=c.$length)?($throwRuntimeError("index out of range"),undefined):c.$array[c.$offset+d]);((e<0||e>=b.$length)?($throwRuntimeError("index out of range"),undefined):b.$array[b.$offset+e]=new $Float64(f));d++;}return b;};$pkg.FloatSliceToInterface=C;$init=function(){$pkg.$init=function(){};var $f,$c=false,$s=0,$r;if(this!==undefined&&this.$blk!==undefined){$f=this;$c=true;$s=$f.$s;$r=$f.$r;}s:while(true){switch($s){case 0:$r=A.$init();$s=1;case 1:if($c){$c=false;$r=$r.$blk();}if($r&&$r.$blk!==undefined){break s;}}return;}if($f===undefined){$f={$blk:$init};}$f.$s=$s;$f.$r=$r;return $f;};$pkg.$init=$init;return $pkg;})();
-$packages["github.com/danielfireman/temp-to-go/vendor/github.com/cnguy/gopherjs-frappe-charts"]=(function(){var $pkg={},$init,A,B,H,K,N,P,X,Z,AA,AC,AD,AE,AF,AG,AH,AK,AL,AM,AN,AR,AS,BA,BB,BC,BE,I,J,O,Y;A=$packages["github.com/danielfireman/temp-to-go/vendor/github.com/cnguy/gopherjs-frappe-charts/utils"];B=$packages["github.com/gopherjs/gopherjs/js"];H=$pkg.ChartData=$newType(0,$kindStruct,"charts.ChartData",true,"github.com/danielfireman/temp-to-go/vendor/github.com/cnguy/gopherjs-frappe-charts",true,function(Object_,Labels_,Datasets_,SpecificValues_){this.$val=this;if(arguments.length===0){this.Object=null;this.Labels=AD.nil;this.Datasets=AL.nil;this.SpecificValues=AN.nil;return;}this.Object=Object_;this.Labels=Labels_;this.Datasets=Datasets_;this.SpecificValues=SpecificValues_;});K=$pkg.Dataset=$newType(0,$kindStruct,"charts.Dataset",true,"github.com/danielfireman/temp-to-go/vendor/github.com/cnguy/gopherjs-frappe-charts",true,function(Object_,Title_,Values_){this.$val=this;if(arguments.length===0){this.Object=null;this.Title="";this.Values=AH.nil;return;}this.Object=Object_;this.Title=Title_;this.Values=Values_;});N=$pkg.LineChartArgs=$newType(0,$kindStruct,"charts.LineChartArgs",true,"github.com/danielfireman/temp-to-go/vendor/github.com/cnguy/gopherjs-frappe-charts",true,function(Object_,Parent_,Title_,Data_,Type_,Height_,Colors_,XAxisMode_,YAxisMode_,IsSeries_,ShowDots_,Heatline_,RegionFill_,FormatTooltipX_,FormatTooltipY_){this.$val=this;if(arguments.length===0){this.Object=null;this.Parent="";this.Title="";this.Data=AC.nil;this.Type="";this.Height=0;this.Colors=AD.nil;this.XAxisMode="";this.YAxisMode="";this.IsSeries=0;this.ShowDots=0;this.Heatline=0;this.RegionFill=0;this.FormatTooltipX=$throwNilPointerError;this.FormatTooltipY=$throwNilPointerError;return;}this.Object=Object_;this.Parent=Parent_;this.Title=Title_;this.Data=Data_;this.Type=Type_;this.Height=Height_;this.Colors=Colors_;this.XAxisMode=XAxisMode_;this.YAxisMode=YAxisMode_;this.IsSeries=IsSeries_;this.ShowDots=ShowDots_;this.Heatline=Heatline_;this.RegionFill=RegionFill_;this.FormatTooltipX=FormatTooltipX_;this.FormatTooltipY=FormatTooltipY_;});P=$pkg.LineChart=$newType(0,$kindStruct,"charts.LineChart",true,"github.com/danielfireman/temp-to-go/vendor/github.com/cnguy/gopherjs-frappe-charts",true,function(Object_){this.$val=this;if(arguments.length===0){this.Object=null;return;}this.Object=Object_;});X=$pkg.SpecificValue=$newType(0,$kindStruct,"charts.SpecificValue",true,"github.com/danielfireman/temp-to-go/vendor/github.com/cnguy/gopherjs-frappe-charts",true,function(Object_,Title_,LineType_,Value_){this.$val=this;if(arguments.length===0){this.Object=null;this.Title="";this.LineType="";this.Value=0;return;}this.Object=Object_;this.Title=Title_;this.LineType=LineType_;this.Value=Value_;});Z=$pkg.UpdateValuesArgs=$newType(0,$kindStruct,"charts.UpdateValuesArgs",true,"github.com/danielfireman/temp-to-go/vendor/github.com/cnguy/gopherjs-frappe-charts",true,function(Values_,Labels_){this.$val=this;if(arguments.length===0){this.Values=AG.nil;this.Labels=AD.nil;return;}this.Values=Values_;this.Labels=Labels_;});AA=$pkg.UpdateValueSet=$newType(0,$kindStruct,"charts.UpdateValueSet",true,"github.com/danielfireman/temp-to-go/vendor/github.com/cnguy/gopherjs-frappe-charts",true,function(Object_,Values_){this.$val=this;if(arguments.length===0){this.Object=null;this.Values=AH.nil;return;}this.Object=Object_;this.Values=Values_;});AC=$ptrType(H);AD=$sliceType($String);AE=$funcType([$String],[$String],false);AF=$ptrType(AA);AG=$sliceType(AF);AH=$sliceType($emptyInterface);AK=$ptrType(K);AL=$sliceType(AK);AM=$ptrType(X);AN=$sliceType(AM);AR=$ptrType(N);AS=$ptrType(AR);BA=$ptrType(B.Object);BB=$ptrType(Z);BC=$sliceType($Float64);BE=$ptrType(P);I=function(){return new H.ptr(new($global.Object)(),AD.nil,AL.nil,AN.nil);};$pkg.NewChartData=I;J=function(a,b){var a,b,c;c=AK.nil;c=new K.ptr(new($global.Object)(),"",AH.nil);c.Object.title=$externalize(a,$String);c.Object.values=$externalize(A.FloatSliceToInterface(b),AH);c=c;return c;};$pkg.NewDataset=J;O=function(a,b){var a,b,c;c=new N.ptr(new($global.Object)(),"","",AC.nil,"",0,AD.nil,"","",0,0,0,0,$throwNilPointerError,$throwNilPointerError);c.Object.parent=$externalize(a,$String);c.Object.type=$externalize("line",$String);c.Object.data=$externalize(b,AC);return c;};$pkg.NewLineChart=O;N.ptr.prototype.WithTitle=function(a){var a,b;b=this;b.Object.title=$externalize(a,$String);return b;};N.prototype.WithTitle=function(a){return this.$val.WithTitle(a);};N.ptr.prototype.WithHeight=function(a){var a,b;b=this;b.Object.height=a;return b;};N.prototype.WithHeight=function(a){return this.$val.WithHeight(a);};N.ptr.prototype.WithColors=function(a){var a,b;b=this;b.Object.colors=$externalize(a,AD);return b;};N.prototype.WithColors=function(a){return this.$val.WithColors(a);};N.ptr.prototype.SetXAxisMode=function(a){var a,b;b=this;b.Object.x_axis_mode=$externalize(a,$String);return b;};N.prototype.SetXAxisMode=function(a){return this.$val.SetXAxisMode(a);};N.ptr.prototype.SetYAxisMode=function(a){var a,b;b=this;b.Object.y_axis_mode=$externalize(a,$String);return b;};N.prototype.SetYAxisMode=function(a){return this.$val.SetYAxisMode(a);};N.ptr.prototype.SetIsSeries=function(a){var a,b;b=this;b.SetBton("is_series",a);return b;};N.prototype.SetIsSeries=function(a){return this.$val.SetIsSeries(a);};N.ptr.prototype.SetShowDots=function(a){var a,b;b=this;b.SetBton("show_dots",a);return b;};N.prototype.SetShowDots=function(a){return this.$val.SetShowDots(a);};N.ptr.prototype.SetHeatline=function(a){var a,b;b=this;b.SetBton("heatline",a);return b;};N.prototype.SetHeatline=function(a){return this.$val.SetHeatline(a);};N.ptr.prototype.SetRegionFill=function(a){var a,b;b=this;b.SetBton("region_fill",a);return b;};N.prototype.SetRegionFill=function(a){return this.$val.SetRegionFill(a);};N.ptr.prototype.SetBton=function(a,b){var a,b,c;c=this;c.Object[$externalize(a,$String)]=A.Btoi(b);};N.prototype.SetBton=function(a,b){return this.$val.SetBton(a,b);};N.ptr.prototype.WithFormatTooltipX=function(a){var a,b;b=this;b.Object.format_tooltip_x=$externalize(a,AE);return b;};N.prototype.WithFormatTooltipX=function(a){return this.$val.WithFormatTooltipX(a);};N.ptr.prototype.WithFormatTooltipY=function(a){var a,b;b=this;b.Object.format_tooltip_y=$externalize(a,AE);return b;};N.prototype.WithFormatTooltipY=function(a){return this.$val.WithFormatTooltipY(a);};P.ptr.prototype.UpdateValues=function(a){var a,b;b=this;b.Object.update_values($externalize(a.Values,AG),$externalize(a.Labels,AD));};P.prototype.UpdateValues=function(a){return this.$val.UpdateValues(a);};P.ptr.prototype.AppendDataPoint=function(a,b){var a,b,c;c=this;c.Object.add_data_point($externalize(A.FloatSliceToInterface(a),AH),$externalize(b,$String));};P.prototype.AppendDataPoint=function(a,b){return this.$val.AppendDataPoint(a,b);};P.ptr.prototype.AddDataPoint=function(a,b,c){var a,b,c,d;d=this;d.Object.add_data_point($externalize(A.FloatSliceToInterface(a),AH),$externalize(b,$String),c);};P.prototype.AddDataPoint=function(a,b,c){return this.$val.AddDataPoint(a,b,c);};P.ptr.prototype.PopDataPoint=function(){var a;a=this;a.Object.remove_data_point();};P.prototype.PopDataPoint=function(){return this.$val.PopDataPoint();};P.ptr.prototype.RemoveDataPoint=function(a){var a,b;b=this;b.Object.remove_data_point(a);};P.prototype.RemoveDataPoint=function(a){return this.$val.RemoveDataPoint(a);};P.ptr.prototype.ShowAverages=function(){var a;a=this;a.Object.show_averages();};P.prototype.ShowAverages=function(){return this.$val.ShowAverages();};P.ptr.prototype.HideAverages=function(){var a;a=this;a.Object.hide_averages();};P.prototype.HideAverages=function(){return this.$val.HideAverages();};N.ptr.prototype.Render=function(){var a,b,c;a=this;if(($parseInt(a.Object.height)>>0)===0){a.Object.height=150;}c=new P.ptr(new($global.Chart)($externalize((b||(b=new AS(function(){return a;},function($v){a=$v;}))),AS)));return c;};N.prototype.Render=function(){return this.$val.Render();};Y=function(a,b,c){var a,b,c,d;d=new X.ptr(new($global.Object)(),"","",0);d.Object.title=$externalize(a,$String);d.Object.line_type=$externalize(b,$String);d.Object.value=c;return d;};$pkg.NewSpecificValue=Y;AR.methods=[{prop:"WithTitle",name:"WithTitle",pkg:"",typ:$funcType([$String],[AR],false)},{prop:"WithHeight",name:"WithHeight",pkg:"",typ:$funcType([$Int],[AR],false)},{prop:"WithColors",name:"WithColors",pkg:"",typ:$funcType([AD],[AR],false)},{prop:"SetXAxisMode",name:"SetXAxisMode",pkg:"",typ:$funcType([$String],[AR],false)},{prop:"SetYAxisMode",name:"SetYAxisMode",pkg:"",typ:$funcType([$String],[AR],false)},{prop:"SetIsSeries",name:"SetIsSeries",pkg:"",typ:$funcType([$Bool],[AR],false)},{prop:"SetShowDots",name:"SetShowDots",pkg:"",typ:$funcType([$Bool],[AR],false)},{prop:"SetHeatline",name:"SetHeatline",pkg:"",typ:$funcType([$Bool],[AR],false)},{prop:"SetRegionFill",name:"SetRegionFill",pkg:"",typ:$funcType([$Bool],[AR],false)},{prop:"SetBton",name:"SetBton",pkg:"",typ:$funcType([$String,$Bool],[],false)},{prop:"WithFormatTooltipX",name:"WithFormatTooltipX",pkg:"",typ:$funcType([AE],[AR],false)},{prop:"WithFormatTooltipY",name:"WithFormatTooltipY",pkg:"",typ:$funcType([AE],[AR],false)},{prop:"Render",name:"Render",pkg:"",typ:$funcType([],[BE],false)}];BE.methods=[{prop:"UpdateValues",name:"UpdateValues",pkg:"",typ:$funcType([BB],[],false)},{prop:"AppendDataPoint",name:"AppendDataPoint",pkg:"",typ:$funcType([BC,$String],[],false)},{prop:"AddDataPoint",name:"AddDataPoint",pkg:"",typ:$funcType([BC,$String,$Int],[],false)},{prop:"PopDataPoint",name:"PopDataPoint",pkg:"",typ:$funcType([],[],false)},{prop:"RemoveDataPoint",name:"RemoveDataPoint",pkg:"",typ:$funcType([$Int],[],false)},{prop:"ShowAverages",name:"ShowAverages",pkg:"",typ:$funcType([],[],false)},{prop:"HideAverages",name:"HideAverages",pkg:"",typ:$funcType([],[],false)}];H.init("",[{prop:"Object",name:"Object",anonymous:true,exported:true,typ:BA,tag:""},{prop:"Labels",name:"Labels",anonymous:false,exported:true,typ:AD,tag:"js:\"labels\""},{prop:"Datasets",name:"Datasets",anonymous:false,exported:true,typ:AL,tag:"js:\"datasets\""},{prop:"SpecificValues",name:"SpecificValues",anonymous:false,exported:true,typ:AN,tag:"js:\"specific_values\""}]);K.init("",[{prop:"Object",name:"Object",anonymous:true,exported:true,typ:BA,tag:""},{prop:"Title",name:"Title",anonymous:false,exported:true,typ:$String,tag:"js:\"title\""},{prop:"Values",name:"Values",anonymous:false,exported:true,typ:AH,tag:"js:\"values\""}]);N.init("",[{prop:"Object",name:"Object",anonymous:true,exported:true,typ:BA,tag:""},{prop:"Parent",name:"Parent",anonymous:false,exported:true,typ:$String,tag:"js:\"parent\""},{prop:"Title",name:"Title",anonymous:false,exported:true,typ:$String,tag:"js:\"title\""},{prop:"Data",name:"Data",anonymous:false,exported:true,typ:AC,tag:"js:\"data\""},{prop:"Type",name:"Type",anonymous:false,exported:true,typ:$String,tag:"js:\"type\""},{prop:"Height",name:"Height",anonymous:false,exported:true,typ:$Int,tag:"js:\"height\""},{prop:"Colors",name:"Colors",anonymous:false,exported:true,typ:AD,tag:"js:\"colors\""},{prop:"XAxisMode",name:"XAxisMode",anonymous:false,exported:true,typ:$String,tag:"js:\"x_axis_mode\""},{prop:"YAxisMode",name:"YAxisMode",anonymous:false,exported:true,typ:$String,tag:"js:\"y_axis_mode\""},{prop:"IsSeries",name:"IsSeries",anonymous:false,exported:true,typ:$Int,tag:"js:\"is_series\""},{prop:"ShowDots",name:"ShowDots",anonymous:false,exported:true,typ:$Int,tag:"js:\"show_dots\""},{prop:"Heatline",name:"Heatline",anonymous:false,exported:true,typ:$Int,tag:"js:\"heatline\""},{prop:"RegionFill",name:"RegionFill",anonymous:false,exported:true,typ:$Int,tag:"js:\"region_fill\""},{prop:"FormatTooltipX",name:"FormatTooltipX",anonymous:false,exported:true,typ:AE,tag:"js:\"format_tooltip_x\""},{prop:"FormatTooltipY",name:"FormatTooltipY",anonymous:false,exported:true,typ:AE,tag:"js:\"format_tooltip_y\""}]);P.init("",[{prop:"Object",name:"Object",anonymous:true,exported:true,typ:BA,tag:""}]);X.init("",[{prop:"Object",name:"Object",anonymous:true,exported:true,typ:BA,tag:""},{prop:"Title",name:"Title",anonymous:false,exported:true,typ:$String,tag:"js:\"title\""},{prop:"LineType",name:"LineType",anonymous:false,exported:true,typ:$String,tag:"js:\"line_type\""},{prop:"Value",name:"Value",anonymous:false,exported:true,typ:$Float64,tag:"js:\"value\""}]);Z.init("",[{prop:"Values",name:"Values",anonymous:false,exported:true,typ:AG,tag:""},{prop:"Labels",name:"Labels",anonymous:false,exported:true,typ:AD,tag:""}]);AA.init("",[{prop:"Object",name:"Object",anonymous:true,exported:true,typ:BA,tag:""},{prop:"Values",name:"Values",anonymous:false,exported:true,typ:AH,tag:"js:\"values\""}]);$init=function(){$pkg.$init=function(){};var $f,$c=false,$s=0,$r;if(this!==undefined&&this.$blk!==undefined){$f=this;$c=true;$s=$f.$s;$r=$f.$r;}s:while(true){switch($s){case 0:$r=A.$init();$s=1;case 1:if($c){$c=false;$r=$r.$blk();}if($r&&$r.$blk!==undefined){break s;}$r=B.$init();$s=2;case 2:if($c){$c=false;$r=$r.$blk();}if($r&&$r.$blk!==undefined){break s;}}return;}if($f===undefined){$f={$blk:$init};}$f.$s=$s;$f.$r=$r;return $f;};$pkg.$init=$init;return $pkg;})();
-$packages["github.com/danielfireman/temp-to-go/vendor/honnef.co/go/js/util"]=(function(){var $pkg={},$init,A,G,L,M;A=$packages["github.com/gopherjs/gopherjs/js"];G=$pkg.EventTarget=$newType(0,$kindStruct,"util.EventTarget",true,"github.com/danielfireman/temp-to-go/vendor/honnef.co/go/js/util",true,function(Object_){this.$val=this;if(arguments.length===0){this.Object=null;return;}this.Object=Object_;});L=$ptrType(A.Object);M=$funcType([L],[],false);G.ptr.prototype.AddEventListener=function(a,b,c){var a,b,c,d;d=this;d.Object.addEventListener($externalize(a,$String),$externalize(c,M),$externalize(b,$Bool));};G.prototype.AddEventListener=function(a,b,c){return this.$val.AddEventListener(a,b,c);};G.ptr.prototype.RemoveEventListener=function(a,b,c){var a,b,c,d;d=this;d.Object.removeEventListener($externalize(a,$String),$externalize(c,M),$externalize(b,$Bool));};G.prototype.RemoveEventListener=function(a,b,c){return this.$val.RemoveEventListener(a,b,c);};G.methods=[{prop:"AddEventListener",name:"AddEventListener",pkg:"",typ:$funcType([$String,$Bool,M],[],false)},{prop:"RemoveEventListener",name:"RemoveEventListener",pkg:"",typ:$funcType([$String,$Bool,M],[],false)}];G.init("",[{prop:"Object",name:"Object",anonymous:true,exported:true,typ:L,tag:""}]);$init=function(){$pkg.$init=function(){};var $f,$c=false,$s=0,$r;if(this!==undefined&&this.$blk!==undefined){$f=this;$c=true;$s=$f.$s;$r=$f.$r;}s:while(true){switch($s){case 0:$r=A.$init();$s=1;case 1:if($c){$c=false;$r=$r.$blk();}if($r&&$r.$blk!==undefined){break s;}}return;}if($f===undefined){$f={$blk:$init};}$f.$s=$s;$f.$r=$r;return $f;};$pkg.$init=$init;return $pkg;})();
-$packages["github.com/danielfireman/temp-to-go/vendor/honnef.co/go/js/xhr"]=(function(){var $pkg={},$init,A,C,B,D,E,I,J,K,L,F;A=$packages["errors"];C=$packages["github.com/danielfireman/temp-to-go/vendor/honnef.co/go/js/util"];B=$packages["github.com/gopherjs/gopherjs/js"];D=$pkg.Request=$newType(0,$kindStruct,"xhr.Request",true,"github.com/danielfireman/temp-to-go/vendor/honnef.co/go/js/xhr",true,function(Object_,EventTarget_,ReadyState_,Response_,ResponseText_,ResponseType_,ResponseXML_,Status_,StatusText_,Timeout_,WithCredentials_,ch_){this.$val=this;if(arguments.length===0){this.Object=null;this.EventTarget=new C.EventTarget.ptr(null);this.ReadyState=0;this.Response=null;this.ResponseText="";this.ResponseType="";this.ResponseXML=null;this.Status=0;this.StatusText="";this.Timeout=0;this.WithCredentials=false;this.ch=$chanNil;return;}this.Object=Object_;this.EventTarget=EventTarget_;this.ReadyState=ReadyState_;this.Response=Response_;this.ResponseText=ResponseText_;this.ResponseType=ResponseType_;this.ResponseXML=ResponseXML_;this.Status=Status_;this.StatusText=StatusText_;this.Timeout=Timeout_;this.WithCredentials=WithCredentials_;this.ch=ch_;});E=$pkg.Upload=$newType(0,$kindStruct,"xhr.Upload",true,"github.com/danielfireman/temp-to-go/vendor/honnef.co/go/js/xhr",true,function(Object_,EventTarget_){this.$val=this;if(arguments.length===0){this.Object=null;this.EventTarget=new C.EventTarget.ptr(null);return;}this.Object=Object_;this.EventTarget=EventTarget_;});I=$ptrType(E);J=$ptrType(D);K=$ptrType(B.Object);L=$chanType($error,false,false);D.ptr.prototype.Upload=function(){var a,b;a=this;b=a.Object.upload;return new E.ptr(b,new C.EventTarget.ptr(b));};D.prototype.Upload=function(){return this.$val.Upload();};F=function(a,b){var a,b,c,d;c=new($global.XMLHttpRequest)();d=new D.ptr(c,new C.EventTarget.ptr(c),0,null,"","",null,0,"",0,false,$chanNil);d.Object.open($externalize(a,$String),$externalize(b,$String),$externalize(true,$Bool));return d;};$pkg.NewRequest=F;D.ptr.prototype.ResponseHeaders=function(){var a;a=this;return $internalize(a.Object.getAllResponseHeaders(),$String);};D.prototype.ResponseHeaders=function(){return this.$val.ResponseHeaders();};D.ptr.prototype.ResponseHeader=function(a){var a,b,c;b=this;c=b.Object.getResponseHeader($externalize(a,$String));if(c===null){return"";}return $internalize(c,$String);};D.prototype.ResponseHeader=function(a){return this.$val.ResponseHeader(a);};D.ptr.prototype.Abort=function(){var a,b,$r;var $f,$c=false;if(this!==undefined&&this.$blk!==undefined){$f=this;$c=true;a=$f.a;b=$f.b;$r=$f.$r;}a=this;if(a.ch===$chanNil){return;}a.Object.abort();b=$select([[a.ch,$pkg.ErrAborted],[]]);if(b[0]===0){}else if(b[0]===1){}if($f===undefined){$f={$blk:D.ptr.prototype.Abort};}$f.a=a;$f.b=b;$f.$r=$r;return $f;};D.prototype.Abort=function(){return this.$val.Abort();};D.ptr.prototype.OverrideMimeType=function(a){var a,b;b=this;b.Object.overrideMimeType($externalize(a,$String));};D.prototype.OverrideMimeType=function(a){return this.$val.OverrideMimeType(a);};D.ptr.prototype.Send=function(a){var a,b,c,d,$s,$r;$s=0;var $f,$c=false;if(this!==undefined&&this.$blk!==undefined){$f=this;$c=true;a=$f.a;b=$f.b;c=$f.c;d=$f.d;$s=$f.$s;$r=$f.$r;}s:while(true){switch($s){case 0:b=[b];b[0]=this;if(!(b[0].ch===$chanNil)){$panic(new $String("must not use a Request for multiple requests"));}b[0].ch=new $Chan($error,1);$clone(b[0].EventTarget,C.EventTarget).AddEventListener("load",false,(function(b){return function(c){var c;$go((function(b){return function $b(){var $s,$r;$s=0;var $f,$c=false;if(this!==undefined&&this.$blk!==undefined){$f=this;$c=true;$s=$f.$s;$r=$f.$r;}s:while(true){switch($s){case 0:$r=$send(b[0].ch,$ifaceNil);$s=1;case 1:if($c){$c=false;$r=$r.$blk();}if($r&&$r.$blk!==undefined){break s;}$s=-1;return;}return;}if($f===undefined){$f={$blk:$b};}$f.$s=$s;$f.$r=$r;return $f;};})(b),[]);};})(b));$clone(b[0].EventTarget,C.EventTarget).AddEventListener("error",false,(function(b){return function(c){var c;$go((function(b){return function $b(){var $s,$r;$s=0;var $f,$c=false;if(this!==undefined&&this.$blk!==undefined){$f=this;$c=true;$s=$f.$s;$r=$f.$r;}s:while(true){switch($s){case 0:$r=$send(b[0].ch,$pkg.ErrFailure);$s=1;case 1:if($c){$c=false;$r=$r.$blk();}if($r&&$r.$blk!==undefined){break s;}$s=-1;return;}return;}if($f===undefined){$f={$blk:$b};}$f.$s=$s;$f.$r=$r;return $f;};})(b),[]);};})(b));$clone(b[0].EventTarget,C.EventTarget).AddEventListener("timeout",false,(function(b){return function(c){var c;$go((function(b){return function $b(){var $s,$r;$s=0;var $f,$c=false;if(this!==undefined&&this.$blk!==undefined){$f=this;$c=true;$s=$f.$s;$r=$f.$r;}s:while(true){switch($s){case 0:$r=$send(b[0].ch,$pkg.ErrTimeout);$s=1;case 1:if($c){$c=false;$r=$r.$blk();}if($r&&$r.$blk!==undefined){break s;}$s=-1;return;}return;}if($f===undefined){$f={$blk:$b};}$f.$s=$s;$f.$r=$r;return $f;};})(b),[]);};})(b));b[0].Object.send($externalize(a,$emptyInterface));c=$recv(b[0].ch);$s=1;case 1:if($c){$c=false;c=c.$blk();}if(c&&c.$blk!==undefined){break s;}d=c[0];$s=-1;return d;}return;}if($f===undefined){$f={$blk:D.ptr.prototype.Send};}$f.a=a;$f.b=b;$f.c=c;$f.d=d;$f.$s=$s;$f.$r=$r;return $f;};D.prototype.Send=function(a){return this.$val.Send(a);};D.ptr.prototype.SetRequestHeader=function(a,b){var a,b,c;c=this;c.Object.setRequestHeader($externalize(a,$String),$externalize(b,$String));};D.prototype.SetRequestHeader=function(a,b){return this.$val.SetRequestHeader(a,b);};J.methods=[{prop:"Upload",name:"Upload",pkg:"",typ:$funcType([],[I],false)},{prop:"ResponseHeaders",name:"ResponseHeaders",pkg:"",typ:$funcType([],[$String],false)},{prop:"ResponseHeader",name:"ResponseHeader",pkg:"",typ:$funcType([$String],[$String],false)},{prop:"Abort",name:"Abort",pkg:"",typ:$funcType([],[],false)},{prop:"OverrideMimeType",name:"OverrideMimeType",pkg:"",typ:$funcType([$String],[],false)},{prop:"Send",name:"Send",pkg:"",typ:$funcType([$emptyInterface],[$error],false)},{prop:"SetRequestHeader",name:"SetRequestHeader",pkg:"",typ:$funcType([$String,$String],[],false)}];D.init("github.com/danielfireman/temp-to-go/vendor/honnef.co/go/js/xhr",[{prop:"Object",name:"Object",anonymous:true,exported:true,typ:K,tag:""},{prop:"EventTarget",name:"EventTarget",anonymous:true,exported:true,typ:C.EventTarget,tag:""},{prop:"ReadyState",name:"ReadyState",anonymous:false,exported:true,typ:$Int,tag:"js:\"readyState\""},{prop:"Response",name:"Response",anonymous:false,exported:true,typ:K,tag:"js:\"response\""},{prop:"ResponseText",name:"ResponseText",anonymous:false,exported:true,typ:$String,tag:"js:\"responseText\""},{prop:"ResponseType",name:"ResponseType",anonymous:false,exported:true,typ:$String,tag:"js:\"responseType\""},{prop:"ResponseXML",name:"ResponseXML",anonymous:false,exported:true,typ:K,tag:"js:\"responseXML\""},{prop:"Status",name:"Status",anonymous:false,exported:true,typ:$Int,tag:"js:\"status\""},{prop:"StatusText",name:"StatusText",anonymous:false,exported:true,typ:$String,tag:"js:\"statusText\""},{prop:"Timeout",name:"Timeout",anonymous:false,exported:true,typ:$Int,tag:"js:\"timeout\""},{prop:"WithCredentials",name:"WithCredentials",anonymous:false,exported:true,typ:$Bool,tag:"js:\"withCredentials\""},{prop:"ch",name:"ch",anonymous:false,exported:false,typ:L,tag:""}]);E.init("",[{prop:"Object",name:"Object",anonymous:true,exported:true,typ:K,tag:""},{prop:"EventTarget",name:"EventTarget",anonymous:true,exported:true,typ:C.EventTarget,tag:""}]);$init=function(){$pkg.$init=function(){};var $f,$c=false,$s=0,$r;if(this!==undefined&&this.$blk!==undefined){$f=this;$c=true;$s=$f.$s;$r=$f.$r;}s:while(true){switch($s){case 0:$r=A.$init();$s=1;case 1:if($c){$c=false;$r=$r.$blk();}if($r&&$r.$blk!==undefined){break s;}$r=C.$init();$s=2;case 2:if($c){$c=false;$r=$r.$blk();}if($r&&$r.$blk!==undefined){break s;}$r=B.$init();$s=3;case 3:if($c){$c=false;$r=$r.$blk();}if($r&&$r.$blk!==undefined){break s;}$pkg.ErrAborted=A.New("request aborted");$pkg.ErrTimeout=A.New("request timed out");$pkg.ErrFailure=A.New("send failed");}return;}if($f===undefined){$f={$blk:$init};}$f.$s=$s;$f.$r=$r;return $f;};$pkg.$init=$init;return $pkg;})();
-$packages["main"]=(function(){var $pkg={},$init,A,C,B,D,F,G,H,I,J,K,L,E;A=$packages["github.com/danielfireman/temp-to-go/vendor/github.com/cnguy/gopherjs-frappe-charts"];C=$packages["github.com/danielfireman/temp-to-go/vendor/honnef.co/go/js/xhr"];B=$packages["github.com/gopherjs/gopherjs/js"];D=$pkg.tempResponse=$newType(0,$kindStruct,"main.tempResponse",true,"main",false,function(Object_,Hour_,Temp_){this.$val=this;if(arguments.length===0){this.Object=null;this.Hour=F.nil;this.Temp=G.nil;return;}this.Object=Object_;this.Hour=Hour_;this.Temp=Temp_;});F=$sliceType($String);G=$sliceType($Float64);H=$ptrType(A.SpecificValue);I=$sliceType(H);J=$ptrType(A.Dataset);K=$sliceType(J);L=$ptrType(B.Object);E=function(){var a,b,c,d,e,f,g,h,i,j,k,$s,$r;$s=0;var $f,$c=false;if(this!==undefined&&this.$blk!==undefined){$f=this;$c=true;a=$f.a;b=$f.b;c=$f.c;d=$f.d;e=$f.e;f=$f.f;g=$f.g;h=$f.h;i=$f.i;j=$f.j;k=$f.k;$s=$f.$s;$r=$f.$r;}s:while(true){switch($s){case 0:a=$internalize($global.jstz.determine().name(),$String);b=C.NewRequest("GET","/restricted/weather");b.Object.timeout=5000;b.SetRequestHeader("Content-Type","application/json");b.SetRequestHeader("TZ",a);b.Object.responseType=$externalize("json",$String);c=b.Send($ifaceNil);$s=1;case 1:if($c){$c=false;c=c.$blk();}if(c&&c.$blk!==undefined){break s;}d=c;if(!($interfaceIsEqual(d,$ifaceNil))){console.log(d);$s=-1;return;}e=new D.ptr(b.Object.response,F.nil,G.nil);f=C.NewRequest("GET","/restricted/indoortemp");f.Object.timeout=5000;f.SetRequestHeader("Content-Type","application/json");f.SetRequestHeader("TZ",a);f.Object.responseType=$externalize("json",$String);g=f.Send($ifaceNil);$s=2;case 2:if($c){$c=false;g=g.$blk();}if(g&&g.$blk!==undefined){break s;}h=g;if(!($interfaceIsEqual(h,$ifaceNil))){console.log(h);$s=-1;return;}i=new D.ptr(f.Object.response,F.nil,G.nil);j=A.NewChartData();j.Object.labels=$externalize($internalize(e.Object.hour,F),F);j.Object.specific_values=$externalize(new I([A.NewSpecificValue("","solid",0)]),I);j.Object.datasets=$externalize(new K([A.NewDataset("Outdoor Temperatur (Celsius)",$internalize(e.Object.temp,G)),A.NewDataset("Indoor Temperature (Celsius)",$internalize(i.Object.temp,G))]),K);k=A.NewLineChart("#chart",j);k.Object.region_fill=1;k.Render();$s=-1;return;}return;}if($f===undefined){$f={$blk:E};}$f.a=a;$f.b=b;$f.c=c;$f.d=d;$f.e=e;$f.f=f;$f.g=g;$f.h=h;$f.i=i;$f.j=j;$f.k=k;$f.$s=$s;$f.$r=$r;return $f;};D.init("",[{prop:"Object",name:"Object",anonymous:true,exported:true,typ:L,tag:""},{prop:"Hour",name:"Hour",anonymous:false,exported:true,typ:F,tag:"js:\"hour\""},{prop:"Temp",name:"Temp",anonymous:false,exported:true,typ:G,tag:"js:\"temp\""}]);$init=function(){$pkg.$init=function(){};var $f,$c=false,$s=0,$r;if(this!==undefined&&this.$blk!==undefined){$f=this;$c=true;$s=$f.$s;$r=$f.$r;}s:while(true){switch($s){case 0:$r=A.$init();$s=1;case 1:if($c){$c=false;$r=$r.$blk();}if($r&&$r.$blk!==undefined){break s;}$r=C.$init();$s=2;case 2:if($c){$c=false;$r=$r.$blk();}if($r&&$r.$blk!==undefined){break s;}$r=B.$init();$s=3;case 3:if($c){$c=false;$r=$r.$blk();}if($r&&$r.$blk!==undefined){break s;}if($pkg===$mainPkg){$s=4;continue;}$s=5;continue;case 4:$r=E();$s=6;case 6:if($c){$c=false;$r=$r.$blk();}if($r&&$r.$blk!==undefined){break s;}$mainFinished=true;case 5:}return;}if($f===undefined){$f={$blk:$init};}$f.$s=$s;$f.$r=$r;return $f;};$pkg.$init=$init;return $pkg;})();
+$packages["github.com/gopherjs/gopherjs/js"]=(function(){var $pkg={},$init,A,B,W,AQ,BF,O;A=$newType(0,$kindStruct,"js.Object",true,"github.com/gopherjs/gopherjs/js",true,function(object_){this.$val=this;if(arguments.length===0){this.object=null;return;}this.object=object_;});B=$newType(0,$kindStruct,"js.Error",true,"github.com/gopherjs/gopherjs/js",true,function(Object_){this.$val=this;if(arguments.length===0){this.Object=null;return;}this.Object=Object_;});$pkg.Object=A;$pkg.Error=B;$pkg.$finishSetup=function(){W=$sliceType($emptyInterface);AQ=$ptrType(A);BF=$ptrType(B);$ptrType(A).prototype.Get=function P(a){var a,b;b=this;return b.object[$externalize(a,$String)];};$ptrType(A).prototype.Set=function Q(a,b){var a,b,c;c=this;c.object[$externalize(a,$String)]=$externalize(b,$emptyInterface);};$ptrType(A).prototype.Delete=function R(a){var a,b;b=this;delete b.object[$externalize(a,$String)];};$ptrType(A).prototype.Length=function S(){var a;a=this;return $parseInt(a.object.length);};$ptrType(A).prototype.Index=function T(a){var a,b;b=this;return b.object[a];};$ptrType(A).prototype.SetIndex=function U(a,b){var a,b,c;c=this;c.object[a]=$externalize(b,$emptyInterface);};$ptrType(A).prototype.Call=function V(a,b){var a,b,c,d;c=this;return(d=c.object,d[$externalize(a,$String)].apply(d,$externalize(b,W)));};$ptrType(A).prototype.Invoke=function X(a){var a,b;b=this;return b.object.apply(undefined,$externalize(a,W));};$ptrType(A).prototype.New=function Y(a){var a,b;b=this;return new($global.Function.prototype.bind.apply(b.object,[undefined].concat($externalize(a,W))));};$ptrType(A).prototype.Bool=function Z(){var a;a=this;return!!(a.object);};$ptrType(A).prototype.String=function AA(){var a;a=this;return $internalize(a.object,$String);};$ptrType(A).prototype.Int=function AB(){var a;a=this;return $parseInt(a.object)>>0;};$ptrType(A).prototype.Int64=function AC(){var a;a=this;return $internalize(a.object,$Int64);};$ptrType(A).prototype.Uint64=function AD(){var a;a=this;return $internalize(a.object,$Uint64);};$ptrType(A).prototype.Float=function AE(){var a;a=this;return $parseFloat(a.object);};$ptrType(A).prototype.Interface=function AF(){var a;a=this;return $internalize(a.object,$emptyInterface);};$ptrType(A).prototype.Unsafe=function AG(){var a;a=this;return a.object;};$ptrType(B).prototype.Error=function AH(){var a;a=this;return"JavaScript error: "+$internalize(a.Object.message,$String);};$ptrType(B).prototype.Stack=function AI(){var a;a=this;return $internalize(a.Object.stack,$String);};O=function BE(){var a;a=new B.ptr(null);$unused(a);};AQ.methods=[{prop:"Get",name:"Get",pkg:"",typ:$funcType([$String],[AQ],false)},{prop:"Set",name:"Set",pkg:"",typ:$funcType([$String,$emptyInterface],[],false)},{prop:"Delete",name:"Delete",pkg:"",typ:$funcType([$String],[],false)},{prop:"Length",name:"Length",pkg:"",typ:$funcType([],[$Int],false)},{prop:"Index",name:"Index",pkg:"",typ:$funcType([$Int],[AQ],false)},{prop:"SetIndex",name:"SetIndex",pkg:"",typ:$funcType([$Int,$emptyInterface],[],false)},{prop:"Call",name:"Call",pkg:"",typ:$funcType([$String,W],[AQ],true)},{prop:"Invoke",name:"Invoke",pkg:"",typ:$funcType([W],[AQ],true)},{prop:"New",name:"New",pkg:"",typ:$funcType([W],[AQ],true)},{prop:"Bool",name:"Bool",pkg:"",typ:$funcType([],[$Bool],false)},{prop:"String",name:"String",pkg:"",typ:$funcType([],[$String],false)},{prop:"Int",name:"Int",pkg:"",typ:$funcType([],[$Int],false)},{prop:"Int64",name:"Int64",pkg:"",typ:$funcType([],[$Int64],false)},{prop:"Uint64",name:"Uint64",pkg:"",typ:$funcType([],[$Uint64],false)},{prop:"Float",name:"Float",pkg:"",typ:$funcType([],[$Float64],false)},{prop:"Interface",name:"Interface",pkg:"",typ:$funcType([],[$emptyInterface],false)},{prop:"Unsafe",name:"Unsafe",pkg:"",typ:$funcType([],[$Uintptr],false)}];BF.methods=[{prop:"Error",name:"Error",pkg:"",typ:$funcType([],[$String],false)},{prop:"Stack",name:"Stack",pkg:"",typ:$funcType([],[$String],false)}];A.init("github.com/gopherjs/gopherjs/js",[{prop:"object",name:"object",embedded:false,exported:false,typ:AQ,tag:""}]);B.init("",[{prop:"Object",name:"Object",embedded:true,exported:true,typ:AQ,tag:""}]);};$init=function(){$pkg.$init=function(){};var $f,$c=false,$s=0,$r;if(this!==undefined&&this.$blk!==undefined){$f=this;$c=true;$s=$f.$s;$r=$f.$r;}s:while(true){switch($s){case 0:O();}return;}if($f===undefined){$f={$blk:$init};}$f.$s=$s;$f.$r=$r;return $f;};$pkg.$init=$init;return $pkg;})();
+$packages["runtime"]=(function(){var $pkg={},$init,A,C,D,E,AT,BL,BP,BQ,DO,AM,F,G,AU,AY,BA;A=$packages["github.com/gopherjs/gopherjs/js"];C=$newType(0,$kindStruct,"runtime._type",true,"runtime",false,function(str_){this.$val=this;if(arguments.length===0){this.str="";return;}this.str=str_;});D=$newType(0,$kindStruct,"runtime.TypeAssertionError",true,"runtime",true,function(_interface_,concrete_,asserted_,missingMethod_){this.$val=this;if(arguments.length===0){this._interface=BL.nil;this.concrete=BL.nil;this.asserted=BL.nil;this.missingMethod="";return;}this._interface=_interface_;this.concrete=concrete_;this.asserted=asserted_;this.missingMethod=missingMethod_;});E=$newType(0,$kindStruct,"runtime.PanicNilError",true,"runtime",true,function(_$0_){this.$val=this;if(arguments.length===0){this._$0=BQ.zero();return;}this._$0=_$0_;});AT=$newType(8,$kindString,"runtime.errorString",true,"runtime",false,null);$pkg._type=C;$pkg.TypeAssertionError=D;$pkg.PanicNilError=E;$pkg.errorString=AT;$pkg.$finishSetup=function(){BL=$ptrType(C);BP=$ptrType(E);BQ=$arrayType(BP,0);DO=$ptrType(D);$ptrType(C).prototype.string=function BH(){var a;a=this;return a.str;};$ptrType(C).prototype.pkgpath=function BI(){var a;a=this;return"";};$ptrType(D).prototype.RuntimeError=function BJ(){};$ptrType(D).prototype.Error=function BK(){var a,b,c,d,e;a=this;b="interface";if(!(a._interface===BL.nil)){b=a._interface.string();}c=a.asserted.string();if(a.concrete===BL.nil){return"interface conversion: "+b+" is nil, not "+c;}d=a.concrete.string();if(a.missingMethod===""){e="interface conversion: "+b+" is "+d+", not "+c;if(d===c){if(!(a.concrete.pkgpath()===a.asserted.pkgpath())){e=e+(" (types from different packages)");}else{e=e+(" (types from different scopes)");}}return e;}return"interface conversion: "+d+" is not "+c+": missing method "+a.missingMethod;};$ptrType(E).prototype.Error=function BM(){return"panic called with nil argument";};$ptrType(E).prototype.RuntimeError=function BN(){};F=function BO(){return new E.ptr(BQ.zero());};G=function BR(){var a,b;a=$packages[$externalize("github.com/gopherjs/gopherjs/js",$String)];$jsObjectPtr=a.Object.ptr;$jsErrorPtr=a.Error.ptr;$throwRuntimeError=AU;$newPanicNilError=F;AM=$internalize($goVersion,$String);BA(AY("GODEBUG"));b=$ifaceNil;b=new D.ptr(BL.nil,BL.nil,BL.nil,"");$unused(b);};AT.prototype.RuntimeError=function DC(){var a;a=this.$val;};$ptrType(AT).prototype.RuntimeError=function(...$args){return new AT(this.$get()).RuntimeError(...$args);};AT.prototype.Error=function DD(){var a;a=this.$val;return"runtime error: "+(a);};$ptrType(AT).prototype.Error=function(...$args){return new AT(this.$get()).Error(...$args);};AU=function DE(a){var a;$panic(new AT((a)));};AY=function DH(a){var a,b,c,d;b=$global.process;if(b===undefined){return"";}c=b.env;if(c===undefined){return"";}d=c[$externalize(a,$String)];if(d===undefined){return"";}return $internalize(d,$String);};BA=function DJ(a){var a,b,c,d;b="0";if(!(a==="")){c=new($global.RegExp)($externalize("(?:^|,)panicnil=(\\d+)(?:,|$)",$String));d=c.exec($externalize(a,$String));if(!(d===null)&&!(d===undefined)&&$parseInt(d.length)>=2){b=$internalize(d[1],$String);}}$panicnil=$externalize(b,$String);};BL.methods=[{prop:"string",name:"string",pkg:"runtime",typ:$funcType([],[$String],false)},{prop:"pkgpath",name:"pkgpath",pkg:"runtime",typ:$funcType([],[$String],false)}];DO.methods=[{prop:"RuntimeError",name:"RuntimeError",pkg:"",typ:$funcType([],[],false)},{prop:"Error",name:"Error",pkg:"",typ:$funcType([],[$String],false)}];BP.methods=[{prop:"Error",name:"Error",pkg:"",typ:$funcType([],[$String],false)},{prop:"RuntimeError",name:"RuntimeError",pkg:"",typ:$funcType([],[],false)}];AT.methods=[{prop:"RuntimeError",name:"RuntimeError",pkg:"",typ:$funcType([],[],false)},{prop:"Error",name:"Error",pkg:"",typ:$funcType([],[$String],false)}];C.init("runtime",[{prop:"str",name:"str",embedded:false,exported:false,typ:$String,tag:""}]);D.init("runtime",[{prop:"_interface",name:"_interface",embedded:false,exported:false,typ:BL,tag:""},{prop:"concrete",name:"concrete",embedded:false,exported:false,typ:BL,tag:""},{prop:"asserted",name:"asserted",embedded:false,exported:false,typ:BL,tag:""},{prop:"missingMethod",name:"missingMethod",embedded:false,exported:false,typ:$String,tag:""}]);E.init("runtime",[{prop:"_$0",name:"_",embedded:false,exported:false,typ:BQ,tag:""}]);};$init=function(){$pkg.$init=function(){};var $f,$c=false,$s=0,$r;if(this!==undefined&&this.$blk!==undefined){$f=this;$c=true;$s=$f.$s;$r=$f.$r;}s:while(true){switch($s){case 0:$r=A.$init();$s=1;case 1:if($c){$c=false;$r=$r.$blk();}if($r&&$r.$blk!==undefined){break s;}AM="";G();}return;}if($f===undefined){$f={$blk:$init};}$f.$s=$s;$f.$r=$r;return $f;};$pkg.$init=$init;return $pkg;})();
+$packages["github.com/cnguy/gopherjs-frappe-charts/utils"]=(function(){var $pkg={},$init,D,A;$pkg.$finishSetup=function(){D=$sliceType($emptyInterface);A=function C(a){var a,b,c,d,e,f;b=D.nil;b=$makeSlice(D,a.$length);c=a;d=0;while(true){if(!(d<c.$length)){break;}e=d;f=((d<0||d>=c.$length)?($throwRuntimeError("index out of range"),undefined):c.$array[c.$offset+d]);((e<0||e>=b.$length)?($throwRuntimeError("index out of range"),undefined):b.$array[b.$offset+e]=new $Float64(f));d++;}b=b;return b;};$pkg.FloatSliceToInterface=A;};$init=function(){$pkg.$init=function(){};var $f,$c=false,$s=0,$r;if(this!==undefined&&this.$blk!==undefined){$f=this;$c=true;$s=$f.$s;$r=$f.$r;}s:while(true){switch($s){case 0:}return;}if($f===undefined){$f={$blk:$init};}$f.$s=$s;$f.$r=$r;return $f;};$pkg.$init=$init;return $pkg;})();
+$packages["github.com/cnguy/gopherjs-frappe-charts"]=(function(){var $pkg={},$init,A,B,C,E,G,H,J,N,P,R,S,U,V,W,X,Y,Z,D,F,I,K;A=$packages["github.com/cnguy/gopherjs-frappe-charts/utils"];B=$packages["github.com/gopherjs/gopherjs/js"];C=$newType(0,$kindStruct,"charts.SpecificValue",true,"github.com/cnguy/gopherjs-frappe-charts",true,function(Object_,Title_,LineType_,Value_){this.$val=this;if(arguments.length===0){this.Object=null;this.Title="";this.LineType="";this.Value=0;return;}this.Object=Object_;this.Title=Title_;this.LineType=LineType_;this.Value=Value_;});E=$newType(0,$kindStruct,"charts.LineChartArgs",true,"github.com/cnguy/gopherjs-frappe-charts",true,function(Object_,Parent_,Title_,Data_,Type_,Height_,RegionFill_){this.$val=this;if(arguments.length===0){this.Object=null;this.Parent="";this.Title="";this.Data=N.nil;this.Type="";this.Height=0;this.RegionFill=0;return;}this.Object=Object_;this.Parent=Parent_;this.Title=Title_;this.Data=Data_;this.Type=Type_;this.Height=Height_;this.RegionFill=RegionFill_;});G=$newType(0,$kindStruct,"charts.LineChart",true,"github.com/cnguy/gopherjs-frappe-charts",true,function(Object_){this.$val=this;if(arguments.length===0){this.Object=null;return;}this.Object=Object_;});H=$newType(0,$kindStruct,"charts.Dataset",true,"github.com/cnguy/gopherjs-frappe-charts",true,function(Object_,Title_,Values_){this.$val=this;if(arguments.length===0){this.Object=null;this.Title="";this.Values=S.nil;return;}this.Object=Object_;this.Title=Title_;this.Values=Values_;});J=$newType(0,$kindStruct,"charts.ChartData",true,"github.com/cnguy/gopherjs-frappe-charts",true,function(Object_,Labels_,Datasets_,SpecificValues_){this.$val=this;if(arguments.length===0){this.Object=null;this.Labels=U.nil;this.Datasets=V.nil;this.SpecificValues=X.nil;return;}this.Object=Object_;this.Labels=Labels_;this.Datasets=Datasets_;this.SpecificValues=SpecificValues_;});$pkg.SpecificValue=C;$pkg.LineChartArgs=E;$pkg.LineChart=G;$pkg.Dataset=H;$pkg.ChartData=J;$pkg.$finishSetup=function(){N=$ptrType(J);P=$ptrType(E);R=$ptrType(H);S=$sliceType($emptyInterface);U=$sliceType($String);V=$sliceType(R);W=$ptrType(C);X=$sliceType(W);Y=$ptrType(B.Object);Z=$ptrType(G);D=function L(a,b,c){var a,b,c,d;d=new C.ptr(new($global.Object)(),"","",0);d.Object.title=$externalize(a,$String);d.Object.line_type=$externalize(b,$String);d.Object.value=c;return d;};$pkg.NewSpecificValue=D;F=function M(a,b){var a,b,c;c=new E.ptr(new($global.Object)(),"","",N.nil,"",0,0);c.Object.parent=$externalize(a,$String);c.Object.type=$externalize("line",$String);c.Object.data=$externalize(b,N);return c;};$pkg.NewLineChart=F;$ptrType(E).prototype.Render=function O(){var a;a=this;if(($parseInt(a.Object.height)>>0)===0){a.Object.height=150;}return new G.ptr(new($global.Chart)($externalize(a,P)));};I=function Q(a,b){var a,b,c;c=R.nil;c=new H.ptr(new($global.Object)(),"",S.nil);c.Object.title=$externalize(a,$String);c.Object.values=$externalize(A.FloatSliceToInterface(b),S);c=c;return c;};$pkg.NewDataset=I;K=function T(){return new J.ptr(new($global.Object)(),U.nil,V.nil,X.nil);};$pkg.NewChartData=K;P.methods=[{prop:"Render",name:"Render",pkg:"",typ:$funcType([],[Z],false)}];C.init("",[{prop:"Object",name:"Object",embedded:true,exported:true,typ:Y,tag:""},{prop:"Title",name:"Title",embedded:false,exported:true,typ:$String,tag:"js:\"title\""},{prop:"LineType",name:"LineType",embedded:false,exported:true,typ:$String,tag:"js:\"line_type\""},{prop:"Value",name:"Value",embedded:false,exported:true,typ:$Float64,tag:"js:\"value\""}]);E.init("",[{prop:"Object",name:"Object",embedded:true,exported:true,typ:Y,tag:""},{prop:"Parent",name:"Parent",embedded:false,exported:true,typ:$String,tag:"js:\"parent\""},{prop:"Title",name:"Title",embedded:false,exported:true,typ:$String,tag:"js:\"title\""},{prop:"Data",name:"Data",embedded:false,exported:true,typ:N,tag:"js:\"data\""},{prop:"Type",name:"Type",embedded:false,exported:true,typ:$String,tag:"js:\"type\""},{prop:"Height",name:"Height",embedded:false,exported:true,typ:$Int,tag:"js:\"height\""},{prop:"RegionFill",name:"RegionFill",embedded:false,exported:true,typ:$Int,tag:"js:\"region_fill\""}]);G.init("",[{prop:"Object",name:"Object",embedded:true,exported:true,typ:Y,tag:""}]);H.init("",[{prop:"Object",name:"Object",embedded:true,exported:true,typ:Y,tag:""},{prop:"Title",name:"Title",embedded:false,exported:true,typ:$String,tag:"js:\"title\""},{prop:"Values",name:"Values",embedded:false,exported:true,typ:S,tag:"js:\"values\""}]);J.init("",[{prop:"Object",name:"Object",embedded:true,exported:true,typ:Y,tag:""},{prop:"Labels",name:"Labels",embedded:false,exported:true,typ:U,tag:"js:\"labels\""},{prop:"Datasets",name:"Datasets",embedded:false,exported:true,typ:V,tag:"js:\"datasets\""},{prop:"SpecificValues",name:"SpecificValues",embedded:false,exported:true,typ:X,tag:"js:\"specific_values\""}]);};$init=function(){$pkg.$init=function(){};var $f,$c=false,$s=0,$r;if(this!==undefined&&this.$blk!==undefined){$f=this;$c=true;$s=$f.$s;$r=$f.$r;}s:while(true){switch($s){case 0:$r=A.$init();$s=1;case 1:if($c){$c=false;$r=$r.$blk();}if($r&&$r.$blk!==undefined){break s;}$r=B.$init();$s=2;case 2:if($c){$c=false;$r=$r.$blk();}if($r&&$r.$blk!==undefined){break s;}}return;}if($f===undefined){$f={$blk:$init};}$f.$s=$s;$f.$r=$r;return $f;};$pkg.$init=$init;return $pkg;})();
+$packages["internal/goarch"]=(function(){var $pkg={},$init;$pkg.$finishSetup=function(){};$init=function(){$pkg.$init=function(){};var $f,$c=false,$s=0,$r;if(this!==undefined&&this.$blk!==undefined){$f=this;$c=true;$s=$f.$s;$r=$f.$r;}s:while(true){switch($s){case 0:}return;}if($f===undefined){$f={$blk:$init};}$f.$s=$s;$f.$r=$r;return $f;};$pkg.$init=$init;return $pkg;})();
+$packages["internal/abi"]=(function(){var $pkg={},$init,A,B,C,D,E,G,H,I,J,K,L,M,N,O,P,Q,Y,Z,AA,AC,AE,AG,BD,BO,BQ,BS,BU,BW,BY,CD,CE,CX,CY,CZ,DA,EB,FO,FP,FQ,FR,FS,F,W,X,AB,AD,AF,AI;A=$packages["github.com/gopherjs/gopherjs/js"];B=$packages["internal/goarch"];C=$newType(0,$kindStruct,"abi.Type",true,"internal/abi",true,function(Size__,PtrBytes_,Hash_,TFlag_,Align__,FieldAlign__,Kind__,Equal_,GCData_,Str_,PtrToThis_){this.$val=this;if(arguments.length===0){this.Size_=0;this.PtrBytes=0;this.Hash=0;this.TFlag=0;this.Align_=0;this.FieldAlign_=0;this.Kind_=0;this.Equal=$throwNilPointerError;this.GCData=CX.nil;this.Str=AC.nil;this.PtrToThis=AE.nil;return;}this.Size_=Size__;this.PtrBytes=PtrBytes_;this.Hash=Hash_;this.TFlag=TFlag_;this.Align_=Align__;this.FieldAlign_=FieldAlign__;this.Kind_=Kind__;this.Equal=Equal_;this.GCData=GCData_;this.Str=Str_;this.PtrToThis=PtrToThis_;});D=$newType(4,$kindUint,"abi.Kind",true,"internal/abi",true,null);E=$newType(1,$kindUint8,"abi.TFlag",true,"internal/abi",true,null);G=$newType(0,$kindStruct,"abi.Method",true,"internal/abi",true,function(Name_,Mtyp_,Ifn_,Tfn_){this.$val=this;if(arguments.length===0){this.Name=AC.nil;this.Mtyp=AE.nil;this.Ifn=0;this.Tfn=0;return;}this.Name=Name_;this.Mtyp=Mtyp_;this.Ifn=Ifn_;this.Tfn=Tfn_;});H=$newType(0,$kindStruct,"abi.Imethod",true,"internal/abi",true,function(Name_,Typ_){this.$val=this;if(arguments.length===0){this.Name=AC.nil;this.Typ=AE.nil;return;}this.Name=Name_;this.Typ=Typ_;});I=$newType(0,$kindStruct,"abi.ArrayType",true,"internal/abi",true,function(Type_,Elem_,Slice_,Len_){this.$val=this;if(arguments.length===0){this.Type=new C.ptr(0,0,0,0,0,0,0,$throwNilPointerError,CX.nil,AC.nil,AE.nil);this.Elem=BO.nil;this.Slice=BO.nil;this.Len=0;return;}this.Type=Type_;this.Elem=Elem_;this.Slice=Slice_;this.Len=Len_;});J=$newType(4,$kindInt,"abi.ChanDir",true,"internal/abi",true,null);K=$newType(0,$kindStruct,"abi.ChanType",true,"internal/abi",true,function(Type_,Elem_,Dir_){this.$val=this;if(arguments.length===0){this.Type=new C.ptr(0,0,0,0,0,0,0,$throwNilPointerError,CX.nil,AC.nil,AE.nil);this.Elem=BO.nil;this.Dir=0;return;}this.Type=Type_;this.Elem=Elem_;this.Dir=Dir_;});L=$newType(0,$kindStruct,"abi.InterfaceType",true,"internal/abi",true,function(Type_,PkgPath_,Methods_){this.$val=this;if(arguments.length===0){this.Type=new C.ptr(0,0,0,0,0,0,0,$throwNilPointerError,CX.nil,AC.nil,AE.nil);this.PkgPath=new AA.ptr("","",false,false,"");this.Methods=CZ.nil;return;}this.Type=Type_;this.PkgPath=PkgPath_;this.Methods=Methods_;});M=$newType(0,$kindStruct,"abi.MapType",true,"internal/abi",true,function(Type_,Key_,Elem_,Bucket_,Hasher_,KeySize_,ValueSize_,BucketSize_,Flags_){this.$val=this;if(arguments.length===0){this.Type=new C.ptr(0,0,0,0,0,0,0,$throwNilPointerError,CX.nil,AC.nil,AE.nil);this.Key=BO.nil;this.Elem=BO.nil;this.Bucket=BO.nil;this.Hasher=$throwNilPointerError;this.KeySize=0;this.ValueSize=0;this.BucketSize=0;this.Flags=0;return;}this.Type=Type_;this.Key=Key_;this.Elem=Elem_;this.Bucket=Bucket_;this.Hasher=Hasher_;this.KeySize=KeySize_;this.ValueSize=ValueSize_;this.BucketSize=BucketSize_;this.Flags=Flags_;});N=$newType(0,$kindStruct,"abi.SliceType",true,"internal/abi",true,function(Type_,Elem_){this.$val=this;if(arguments.length===0){this.Type=new C.ptr(0,0,0,0,0,0,0,$throwNilPointerError,CX.nil,AC.nil,AE.nil);this.Elem=BO.nil;return;}this.Type=Type_;this.Elem=Elem_;});O=$newType(0,$kindStruct,"abi.PtrType",true,"internal/abi",true,function(Type_,Elem_){this.$val=this;if(arguments.length===0){this.Type=new C.ptr(0,0,0,0,0,0,0,$throwNilPointerError,CX.nil,AC.nil,AE.nil);this.Elem=BO.nil;return;}this.Type=Type_;this.Elem=Elem_;});P=$newType(0,$kindStruct,"abi.StructField",true,"internal/abi",true,function(Name_,Typ_,Offset_){this.$val=this;if(arguments.length===0){this.Name=new AA.ptr("","",false,false,"");this.Typ=BO.nil;this.Offset=0;return;}this.Name=Name_;this.Typ=Typ_;this.Offset=Offset_;});Q=$newType(0,$kindStruct,"abi.StructType",true,"internal/abi",true,function(Type_,PkgPath_,Fields_){this.$val=this;if(arguments.length===0){this.Type=new C.ptr(0,0,0,0,0,0,0,$throwNilPointerError,CX.nil,AC.nil,AE.nil);this.PkgPath=new AA.ptr("","",false,false,"");this.Fields=DA.nil;return;}this.Type=Type_;this.PkgPath=PkgPath_;this.Fields=Fields_;});Y=$newType(0,$kindStruct,"abi.UncommonType",true,"internal/abi",true,function(PkgPath_,Mcount_,Xcount_,Methods__){this.$val=this;if(arguments.length===0){this.PkgPath=AC.nil;this.Mcount=0;this.Xcount=0;this.Methods_=CE.nil;return;}this.PkgPath=PkgPath_;this.Mcount=Mcount_;this.Xcount=Xcount_;this.Methods_=Methods__;});Z=$newType(0,$kindStruct,"abi.FuncType",true,"internal/abi",true,function(Type_,InCount_,OutCount_,In__,Out__){this.$val=this;if(arguments.length===0){this.Type=new C.ptr(0,0,0,0,0,0,0,$throwNilPointerError,CX.nil,AC.nil,AE.nil);this.InCount=0;this.OutCount=0;this.In_=CY.nil;this.Out_=CY.nil;return;}this.Type=Type_;this.InCount=InCount_;this.OutCount=OutCount_;this.In_=In__;this.Out_=Out__;});AA=$newType(0,$kindStruct,"abi.Name",true,"internal/abi",true,function(name_,tag_,exported_,embedded_,pkgPath_){this.$val=this;if(arguments.length===0){this.name="";this.tag="";this.exported=false;this.embedded=false;this.pkgPath="";return;}this.name=name_;this.tag=tag_;this.exported=exported_;this.embedded=embedded_;this.pkgPath=pkgPath_;});AC=$newType(4,$kindPtr,"abi.NameOff",true,"internal/abi",true,null);AE=$newType(4,$kindPtr,"abi.TypeOff",true,"internal/abi",true,null);AG=$newType(4,$kindUnsafePointer,"abi.TextOff",true,"internal/abi",true,null);$pkg.Type=C;$pkg.Kind=D;$pkg.TFlag=E;$pkg.Method=G;$pkg.Imethod=H;$pkg.ArrayType=I;$pkg.ChanDir=J;$pkg.ChanType=K;$pkg.InterfaceType=L;$pkg.MapType=M;$pkg.SliceType=N;$pkg.PtrType=O;$pkg.StructField=P;$pkg.StructType=Q;$pkg.UncommonType=Y;$pkg.FuncType=Z;$pkg.Name=AA;$pkg.NameOff=AC;$pkg.TypeOff=AE;$pkg.TextOff=AG;$pkg.$finishSetup=function(){BD=$sliceType($String);BO=$ptrType(C);BQ=$ptrType(Q);BS=$ptrType(M);BU=$ptrType(I);BW=$ptrType(Z);BY=$ptrType(L);CD=$ptrType(Y);CE=$sliceType(G);CX=$ptrType($Uint8);CY=$sliceType(BO);CZ=$sliceType(H);DA=$sliceType(P);EB=$structType("internal/abi",[{prop:"str",name:"str",embedded:false,exported:false,typ:$String,tag:""}]);FO=$ptrType(A.Object);FP=$funcType([$UnsafePointer,$UnsafePointer],[$Bool],false);FQ=$funcType([$UnsafePointer,$Uintptr],[$Uintptr],false);FR=$ptrType(P);FS=$ptrType(AA);D.prototype.String=function BE(){var a;a=this.$val;if(((a>>0))<F.$length){return((a<0||a>=F.$length)?($throwRuntimeError("index out of range"),undefined):F.$array[F.$offset+a]);}return(0>=F.$length?($throwRuntimeError("index out of range"),undefined):F.$array[F.$offset+0]);};$ptrType(D).prototype.String=function(...$args){return new D(this.$get()).String(...$args);};$ptrType(C).prototype.Kind=function BF(){var a;a=this;return((((a.Kind_&31)>>>0)>>>0));};$ptrType(C).prototype.HasName=function BG(){var a;a=this;return!((((a.TFlag&4)>>>0)===0));};$ptrType(C).prototype.Pointers=function BH(){var a;a=this;return!((a.PtrBytes===0));};$ptrType(C).prototype.IfaceIndir=function BI(){var a;a=this;return((a.Kind_&32)>>>0)===0;};$ptrType(C).prototype.IsDirectIface=function BJ(){var a;a=this;return!((((a.Kind_&32)>>>0)===0));};$ptrType(C).prototype.Len=function BK(){var a;a=this;if(a.Kind()===17){return(((a.kindType).Len>>0));}return 0;};$ptrType(C).prototype.Common=function BL(){var a;a=this;return a;};$ptrType(C).prototype.ChanDir=function BM(){var a,b;a=this;if(a.Kind()===18){b=(a.kindType);return b.Dir;}return 0;};$ptrType(C).prototype.Elem=function BN(){var a,b,c,d,e,f,g;a=this;b=a.Kind();if(b===(17)){c=(a.kindType);return c.Elem;}else if(b===(18)){d=(a.kindType);return d.Elem;}else if(b===(21)){e=(a.kindType);return e.Elem;}else if(b===(22)){f=(a.kindType);return f.Elem;}else if(b===(23)){g=(a.kindType);return g.Elem;}return BO.nil;};$ptrType(C).prototype.StructType=function BP(){var a;a=this;if(!((a.Kind()===25))){return BQ.nil;}return(a.kindType);};$ptrType(C).prototype.MapType=function BR(){var a;a=this;if(!((a.Kind()===21))){return BS.nil;}return(a.kindType);};$ptrType(C).prototype.ArrayType=function BT(){var a;a=this;if(!((a.Kind()===17))){return BU.nil;}return(a.kindType);};$ptrType(C).prototype.FuncType=function BV(){var a;a=this;if(!((a.Kind()===19))){return BW.nil;}return(a.kindType);};$ptrType(C).prototype.InterfaceType=function BX(){var a;a=this;if(!((a.Kind()===20))){return BY.nil;}return(a.kindType);};$ptrType(C).prototype.Size=function BZ(){var a;a=this;return a.Size_;};$ptrType(C).prototype.Align=function CA(){var a;a=this;return((a.Align_>>0));};$ptrType(C).prototype.FieldAlign=function CB(){var a;a=this;return((a.FieldAlign_>>0));};$ptrType(C).prototype.ExportedMethods=function CC(){var a,b;a=this;b=a.Uncommon();if(b===CD.nil){return CE.nil;}return b.ExportedMethods();};$ptrType(C).prototype.NumMethod=function CF(){var a,b;a=this;if(a.Kind()===20){b=(a.kindType);return b.NumMethod();}return a.ExportedMethods().$length;};$ptrType(L).prototype.NumMethod=function CG(){var a;a=this;return a.Methods.$length;};$ptrType(M).prototype.IndirectKey=function CH(){var a;a=this;return!((((a.Flags&1)>>>0)===0));};$ptrType(M).prototype.IndirectElem=function CI(){var a;a=this;return!((((a.Flags&2)>>>0)===0));};$ptrType(M).prototype.ReflexiveKey=function CJ(){var a;a=this;return!((((a.Flags&4)>>>0)===0));};$ptrType(M).prototype.NeedKeyUpdate=function CK(){var a;a=this;return!((((a.Flags&8)>>>0)===0));};$ptrType(M).prototype.HashMightPanic=function CL(){var a;a=this;return!((((a.Flags&16)>>>0)===0));};$ptrType(C).prototype.Key=function CM(){var a;a=this;if(a.Kind()===21){return(a.kindType).Key;}return BO.nil;};$ptrType(Z).prototype.In=function CN(a){var a,b,c;b=this;return(c=b.InSlice(),((a<0||a>=c.$length)?($throwRuntimeError("index out of range"),undefined):c.$array[c.$offset+a]));};$ptrType(Z).prototype.NumIn=function CO(){var a;a=this;return((a.InCount>>0));};$ptrType(Z).prototype.NumOut=function CP(){var a;a=this;return((((a.OutCount&32767)>>>0)>>0));};$ptrType(Z).prototype.Out=function CQ(a){var a,b,c;b=this;return(c=b.OutSlice(),((a<0||a>=c.$length)?($throwRuntimeError("index out of range"),undefined):c.$array[c.$offset+a]));};$ptrType(Z).prototype.IsVariadic=function CR(){var a;a=this;return!((((a.OutCount&32768)>>>0)===0));};$ptrType(P).prototype.Embedded=function CS(){var a;a=this;return $clone(a.Name,AA).IsEmbedded();};W=function CW(a){var a,aa,ab,ac,ad,ae,af,ag,ah,ai,aj,ak,al,am,an,ao,b,c,d,e,f,g,h,i,j,k,l,m,n,o,p,q,r,s,t,u,v,w,x,y,z;b=a.abiType;if(!(b===undefined)){return((b));}c=new C.ptr(((($parseInt(a.size)>>0)>>>0)),0,0,0,0,0,((($parseInt(a.kind)>>0)<<24>>>24)),$throwNilPointerError,CX.nil,AD($clone(AB(AI(a.string),"",!!(a.exported),false),AA)),AE.nil);c.jsType=a;a.abiType=c;d=$methodSet(a);if(!(($parseInt(d.length)===0))||!!(a.named)){c.TFlag=(c.TFlag|(1))>>>0;if(!!(a.named)){c.TFlag=(c.TFlag|(4))>>>0;}e=CE.nil;f=0;while(true){if(!(f<$parseInt(d.length))){break;}g=d[f];h=AI(g.pkg)==="";if(!h){f=f+(1)>>0;continue;}e=$append(e,new G.ptr(AD($clone(AB(AI(g.name),"",h,false),AA)),AF(W(g.typ)),0,0));f=f+(1)>>0;}i=((e.$length<<16>>>16));j=0;while(true){if(!(j<$parseInt(d.length))){break;}k=d[j];l=AI(k.pkg)==="";if(l){j=j+(1)>>0;continue;}e=$append(e,new G.ptr(AD($clone(AB(AI(k.name),"",l,false),AA)),AF(W(k.typ)),0,0));j=j+(1)>>0;}m=new Y.ptr(AD($clone(AB(AI(a.pkg),"",false,false),AA)),(($parseInt(d.length)<<16>>>16)),i,e);c.uncommonType=m;}n=c.Kind();if(n===(17)){X(c,new I.ptr(new C.ptr(0,0,0,0,0,0,0,$throwNilPointerError,CX.nil,AC.nil,AE.nil),W(a.elem),BO.nil,((($parseInt(a.len)>>0)>>>0))));}else if(n===(18)){o=3;if(!!(a.sendOnly)){o=2;}if(!!(a.recvOnly)){o=1;}c.PtrBytes=4;X(c,new K.ptr(new C.ptr(0,0,0,0,0,0,0,$throwNilPointerError,CX.nil,AC.nil,AE.nil),W(a.elem),o));}else if(n===(19)){p=a.params;q=$makeSlice(CY,$parseInt(p.length));r=q;s=0;while(true){if(!(s<r.$length)){break;}t=s;((t<0||t>=q.$length)?($throwRuntimeError("index out of range"),undefined):q.$array[q.$offset+t]=W(p[t]));s++;}u=a.results;v=$makeSlice(CY,$parseInt(u.length));w=v;x=0;while(true){if(!(x<w.$length)){break;}y=x;((y<0||y>=v.$length)?($throwRuntimeError("index out of range"),undefined):v.$array[v.$offset+y]=W(u[y]));x++;}z=(($parseInt(u.length)<<16>>>16));if(!!(a.variadic)){z=(z|(32768))>>>0;}c.PtrBytes=4;X(c,new Z.ptr(new C.ptr(0,0,0,0,0,0,0,$throwNilPointerError,CX.nil,AC.nil,AE.nil),(($parseInt(p.length)<<16>>>16)),z,q,v));}else if(n===(20)){aa=a.methods;ab=$makeSlice(CZ,$parseInt(aa.length));ac=ab;ad=0;while(true){if(!(ad<ac.$length)){break;}ae=ad;af=aa[ae];ag=AI(af.pkg);ah=ag==="";ai=$clone(AB(AI(af.name),"",ah,false),AA);ai.SetPkgPath(ag);H.copy(((ae<0||ae>=ab.$length)?($throwRuntimeError("index out of range"),undefined):ab.$array[ab.$offset+ae]),new H.ptr(AD($clone(ai,AA)),AF(W(af.typ))));ad++;}X(c,new L.ptr(new C.ptr(0,0,0,0,0,0,0,$throwNilPointerError,CX.nil,AC.nil,AE.nil),$clone(AB(AI(a.pkg),"",false,false),AA),ab));}else if(n===(21)){c.PtrBytes=4;X(c,new M.ptr(new C.ptr(0,0,0,0,0,0,0,$throwNilPointerError,CX.nil,AC.nil,AE.nil),W(a.key),W(a.elem),BO.nil,$throwNilPointerError,0,0,0,0));}else if(n===(22)){c.PtrBytes=4;X(c,new O.ptr(new C.ptr(0,0,0,0,0,0,0,$throwNilPointerError,CX.nil,AC.nil,AE.nil),W(a.elem)));}else if(n===(23)){c.PtrBytes=4;X(c,new N.ptr(new C.ptr(0,0,0,0,0,0,0,$throwNilPointerError,CX.nil,AC.nil,AE.nil),W(a.elem)));}else if(n===(25)){aj=a.fields;ak=$makeSlice(DA,$parseInt(aj.length));al=ak;am=0;while(true){if(!(am<al.$length)){break;}an=am;ao=aj[an];P.copy(((an<0||an>=ak.$length)?($throwRuntimeError("index out of range"),undefined):ak.$array[ak.$offset+an]),new P.ptr($clone(AB(AI(ao.name),AI(ao.tag),!!(ao.exported),!!(ao.embedded)),AA),W(ao.typ),((an>>>0))));am++;}X(c,new Q.ptr(new C.ptr(0,0,0,0,0,0,0,$throwNilPointerError,CX.nil,AC.nil,AE.nil),$clone(AB(AI(a.pkgPath),"",false,false),AA),ak));}else if(n===(26)){c.PtrBytes=4;}return c;};$pkg.ReflectType=W;X=function DB(a,b){var a,b;a.kindType=b;b.Type=a;};$ptrType(Y).prototype.Methods=function DC(){var a;a=this;return a.Methods_;};$ptrType(Y).prototype.ExportedMethods=function DD(){var a;a=this;return $subslice(a.Methods_,0,a.Xcount,a.Xcount);};$ptrType(C).prototype.Uncommon=function DE(){var a,b;a=this;b=a.uncommonType;if(b===undefined){return CD.nil;}return((b));};$ptrType(C).prototype.JsType=function DF(){var a;a=this;return a.jsType;};$ptrType(C).prototype.PtrTo=function DG(){var a;a=this;return W($ptrType(a.JsType()));};$ptrType(C).prototype.JsPtrTo=function DH(){var a;a=this;return a.PtrTo().JsType();};$ptrType(C).prototype.String=function DI(){var a,b;a=this;b=$clone(a.NameOff(a.Str),AA).Name();if(!((((a.TFlag&2)>>>0)===0))){return $substring(b,1);}return b;};$ptrType(Z).prototype.InSlice=function DJ(){var a;a=this;return a.In_;};$ptrType(Z).prototype.OutSlice=function DK(){var a;a=this;return a.Out_;};$ptrType(AA).prototype.IsExported=function DL(){var a;a=this;return a.exported;};AA.prototype.IsExported=function(...$args){return this.$val.IsExported(...$args);};$ptrType(AA).prototype.HasTag=function DM(){var a;a=this;return a.tag.length>0;};AA.prototype.HasTag=function(...$args){return this.$val.HasTag(...$args);};$ptrType(AA).prototype.IsEmbedded=function DN(){var a;a=this;return a.embedded;};AA.prototype.IsEmbedded=function(...$args){return this.$val.IsEmbedded(...$args);};$ptrType(AA).prototype.IsBlank=function DO(){var a;a=this;return $clone(a,AA).Name()==="_";};AA.prototype.IsBlank=function(...$args){return this.$val.IsBlank(...$args);};$ptrType(AA).prototype.Name=function DP(){var a;a=this;return a.name;};AA.prototype.Name=function(...$args){return this.$val.Name(...$args);};$ptrType(AA).prototype.Tag=function DQ(){var a;a=this;return a.tag;};AA.prototype.Tag=function(...$args){return this.$val.Tag(...$args);};$ptrType(AA).prototype.PkgPath=function DR(){var a;a=this;return a.pkgPath;};AA.prototype.PkgPath=function(...$args){return this.$val.PkgPath(...$args);};$ptrType(AA).prototype.SetPkgPath=function DS(a){var a,b;b=this;b.pkgPath=a;};AB=function DT(a,b,c,d){var a,b,c,d;return new AA.ptr(a,b,c,d,"");};$pkg.NewName=AB;$ptrType(C).prototype.NameOff=function DU(a){var a,b;b=this;return(a===AC.nil&&$throwNilPointerError(),a);};AD=function DV(a){var a;return $pointerOfStructConversion(a,AC);};$pkg.ResolveReflectName=AD;$ptrType(C).prototype.TypeOff=function DW(a){var a,b;b=this;return $pointerOfStructConversion(a,BO);};AF=function DX(a){var a;return $pointerOfStructConversion(a,AE);};$pkg.ResolveReflectType=AF;$ptrType(C).prototype.TextOff=function DY(a){var a,b;b=this;return(a);};AI=function EA(a){var a,b;b=new EB.ptr("");b.str=a;return b.str;};$ptrType(C).prototype.IsWrapped=function EC(){var a;a=this;return!!(a.JsType().wrapped);};$ptrType(C).prototype.Comparable=function EG(){var a,b,c,d,e,f;a=this;b=a.Kind();if((b===(19))||(b===(23))||(b===(21))){return false;}else if(b===(17)){return a.Elem().Comparable();}else if(b===(25)){c=a.StructType();d=0;while(true){if(!(d<c.Fields.$length)){break;}f=$clone((e=c.Fields,((d<0||d>=e.$length)?($throwRuntimeError("index out of range"),undefined):e.$array[e.$offset+d])),P);if(!f.Typ.Comparable()){return false;}d=d+(1)>>0;}}return true;};BO.methods=[{prop:"Kind",name:"Kind",pkg:"",typ:$funcType([],[D],false)},{prop:"HasName",name:"HasName",pkg:"",typ:$funcType([],[$Bool],false)},{prop:"Pointers",name:"Pointers",pkg:"",typ:$funcType([],[$Bool],false)},{prop:"IfaceIndir",name:"IfaceIndir",pkg:"",typ:$funcType([],[$Bool],false)},{prop:"IsDirectIface",name:"IsDirectIface",pkg:"",typ:$funcType([],[$Bool],false)},{prop:"Len",name:"Len",pkg:"",typ:$funcType([],[$Int],false)},{prop:"Common",name:"Common",pkg:"",typ:$funcType([],[BO],false)},{prop:"ChanDir",name:"ChanDir",pkg:"",typ:$funcType([],[J],false)},{prop:"Elem",name:"Elem",pkg:"",typ:$funcType([],[BO],false)},{prop:"StructType",name:"StructType",pkg:"",typ:$funcType([],[BQ],false)},{prop:"MapType",name:"MapType",pkg:"",typ:$funcType([],[BS],false)},{prop:"ArrayType",name:"ArrayType",pkg:"",typ:$funcType([],[BU],false)},{prop:"FuncType",name:"FuncType",pkg:"",typ:$funcType([],[BW],false)},{prop:"InterfaceType",name:"InterfaceType",pkg:"",typ:$funcType([],[BY],false)},{prop:"Size",name:"Size",pkg:"",typ:$funcType([],[$Uintptr],false)},{prop:"Align",name:"Align",pkg:"",typ:$funcType([],[$Int],false)},{prop:"FieldAlign",name:"FieldAlign",pkg:"",typ:$funcType([],[$Int],false)},{prop:"ExportedMethods",name:"ExportedMethods",pkg:"",typ:$funcType([],[CE],false)},{prop:"NumMethod",name:"NumMethod",pkg:"",typ:$funcType([],[$Int],false)},{prop:"Key",name:"Key",pkg:"",typ:$funcType([],[BO],false)},{prop:"Uncommon",name:"Uncommon",pkg:"",typ:$funcType([],[CD],false)},{prop:"JsType",name:"JsType",pkg:"",typ:$funcType([],[FO],false)},{prop:"PtrTo",name:"PtrTo",pkg:"",typ:$funcType([],[BO],false)},{prop:"JsPtrTo",name:"JsPtrTo",pkg:"",typ:$funcType([],[FO],false)},{prop:"String",name:"String",pkg:"",typ:$funcType([],[$String],false)},{prop:"NameOff",name:"NameOff",pkg:"",typ:$funcType([AC],[AA],false)},{prop:"TypeOff",name:"TypeOff",pkg:"",typ:$funcType([AE],[BO],false)},{prop:"TextOff",name:"TextOff",pkg:"",typ:$funcType([AG],[$UnsafePointer],false)},{prop:"IsWrapped",name:"IsWrapped",pkg:"",typ:$funcType([],[$Bool],false)},{prop:"Comparable",name:"Comparable",pkg:"",typ:$funcType([],[$Bool],false)}];D.methods=[{prop:"String",name:"String",pkg:"",typ:$funcType([],[$String],false)}];BY.methods=[{prop:"NumMethod",name:"NumMethod",pkg:"",typ:$funcType([],[$Int],false)}];BS.methods=[{prop:"IndirectKey",name:"IndirectKey",pkg:"",typ:$funcType([],[$Bool],false)},{prop:"IndirectElem",name:"IndirectElem",pkg:"",typ:$funcType([],[$Bool],false)},{prop:"ReflexiveKey",name:"ReflexiveKey",pkg:"",typ:$funcType([],[$Bool],false)},{prop:"NeedKeyUpdate",name:"NeedKeyUpdate",pkg:"",typ:$funcType([],[$Bool],false)},{prop:"HashMightPanic",name:"HashMightPanic",pkg:"",typ:$funcType([],[$Bool],false)}];FR.methods=[{prop:"Embedded",name:"Embedded",pkg:"",typ:$funcType([],[$Bool],false)}];CD.methods=[{prop:"Methods",name:"Methods",pkg:"",typ:$funcType([],[CE],false)},{prop:"ExportedMethods",name:"ExportedMethods",pkg:"",typ:$funcType([],[CE],false)}];BW.methods=[{prop:"In",name:"In",pkg:"",typ:$funcType([$Int],[BO],false)},{prop:"NumIn",name:"NumIn",pkg:"",typ:$funcType([],[$Int],false)},{prop:"NumOut",name:"NumOut",pkg:"",typ:$funcType([],[$Int],false)},{prop:"Out",name:"Out",pkg:"",typ:$funcType([$Int],[BO],false)},{prop:"IsVariadic",name:"IsVariadic",pkg:"",typ:$funcType([],[$Bool],false)},{prop:"InSlice",name:"InSlice",pkg:"",typ:$funcType([],[CY],false)},{prop:"OutSlice",name:"OutSlice",pkg:"",typ:$funcType([],[CY],false)}];AA.methods=[{prop:"IsExported",name:"IsExported",pkg:"",typ:$funcType([],[$Bool],false)},{prop:"HasTag",name:"HasTag",pkg:"",typ:$funcType([],[$Bool],false)},{prop:"IsEmbedded",name:"IsEmbedded",pkg:"",typ:$funcType([],[$Bool],false)},{prop:"IsBlank",name:"IsBlank",pkg:"",typ:$funcType([],[$Bool],false)},{prop:"Name",name:"Name",pkg:"",typ:$funcType([],[$String],false)},{prop:"Tag",name:"Tag",pkg:"",typ:$funcType([],[$String],false)},{prop:"PkgPath",name:"PkgPath",pkg:"",typ:$funcType([],[$String],false)}];FS.methods=[{prop:"SetPkgPath",name:"SetPkgPath",pkg:"",typ:$funcType([$String],[],false)}];C.init("",[{prop:"Size_",name:"Size_",embedded:false,exported:true,typ:$Uintptr,tag:""},{prop:"PtrBytes",name:"PtrBytes",embedded:false,exported:true,typ:$Uintptr,tag:""},{prop:"Hash",name:"Hash",embedded:false,exported:true,typ:$Uint32,tag:""},{prop:"TFlag",name:"TFlag",embedded:false,exported:true,typ:E,tag:""},{prop:"Align_",name:"Align_",embedded:false,exported:true,typ:$Uint8,tag:""},{prop:"FieldAlign_",name:"FieldAlign_",embedded:false,exported:true,typ:$Uint8,tag:""},{prop:"Kind_",name:"Kind_",embedded:false,exported:true,typ:$Uint8,tag:""},{prop:"Equal",name:"Equal",embedded:false,exported:true,typ:FP,tag:""},{prop:"GCData",name:"GCData",embedded:false,exported:true,typ:CX,tag:""},{prop:"Str",name:"Str",embedded:false,exported:true,typ:AC,tag:""},{prop:"PtrToThis",name:"PtrToThis",embedded:false,exported:true,typ:AE,tag:""}]);G.init("",[{prop:"Name",name:"Name",embedded:false,exported:true,typ:AC,tag:""},{prop:"Mtyp",name:"Mtyp",embedded:false,exported:true,typ:AE,tag:""},{prop:"Ifn",name:"Ifn",embedded:false,exported:true,typ:AG,tag:""},{prop:"Tfn",name:"Tfn",embedded:false,exported:true,typ:AG,tag:""}]);H.init("",[{prop:"Name",name:"Name",embedded:false,exported:true,typ:AC,tag:""},{prop:"Typ",name:"Typ",embedded:false,exported:true,typ:AE,tag:""}]);I.init("",[{prop:"Type",name:"Type",embedded:true,exported:true,typ:C,tag:""},{prop:"Elem",name:"Elem",embedded:false,exported:true,typ:BO,tag:""},{prop:"Slice",name:"Slice",embedded:false,exported:true,typ:BO,tag:""},{prop:"Len",name:"Len",embedded:false,exported:true,typ:$Uintptr,tag:""}]);K.init("",[{prop:"Type",name:"Type",embedded:true,exported:true,typ:C,tag:""},{prop:"Elem",name:"Elem",embedded:false,exported:true,typ:BO,tag:""},{prop:"Dir",name:"Dir",embedded:false,exported:true,typ:J,tag:""}]);L.init("",[{prop:"Type",name:"Type",embedded:true,exported:true,typ:C,tag:""},{prop:"PkgPath",name:"PkgPath",embedded:false,exported:true,typ:AA,tag:""},{prop:"Methods",name:"Methods",embedded:false,exported:true,typ:CZ,tag:""}]);M.init("",[{prop:"Type",name:"Type",embedded:true,exported:true,typ:C,tag:""},{prop:"Key",name:"Key",embedded:false,exported:true,typ:BO,tag:""},{prop:"Elem",name:"Elem",embedded:false,exported:true,typ:BO,tag:""},{prop:"Bucket",name:"Bucket",embedded:false,exported:true,typ:BO,tag:""},{prop:"Hasher",name:"Hasher",embedded:false,exported:true,typ:FQ,tag:""},{prop:"KeySize",name:"KeySize",embedded:false,exported:true,typ:$Uint8,tag:""},{prop:"ValueSize",name:"ValueSize",embedded:false,exported:true,typ:$Uint8,tag:""},{prop:"BucketSize",name:"BucketSize",embedded:false,exported:true,typ:$Uint16,tag:""},{prop:"Flags",name:"Flags",embedded:false,exported:true,typ:$Uint32,tag:""}]);N.init("",[{prop:"Type",name:"Type",embedded:true,exported:true,typ:C,tag:""},{prop:"Elem",name:"Elem",embedded:false,exported:true,typ:BO,tag:""}]);O.init("",[{prop:"Type",name:"Type",embedded:true,exported:true,typ:C,tag:""},{prop:"Elem",name:"Elem",embedded:false,exported:true,typ:BO,tag:""}]);P.init("",[{prop:"Name",name:"Name",embedded:false,exported:true,typ:AA,tag:""},{prop:"Typ",name:"Typ",embedded:false,exported:true,typ:BO,tag:""},{prop:"Offset",name:"Offset",embedded:false,exported:true,typ:$Uintptr,tag:""}]);Q.init("",[{prop:"Type",name:"Type",embedded:true,exported:true,typ:C,tag:""},{prop:"PkgPath",name:"PkgPath",embedded:false,exported:true,typ:AA,tag:""},{prop:"Fields",name:"Fields",embedded:false,exported:true,typ:DA,tag:""}]);Y.init("",[{prop:"PkgPath",name:"PkgPath",embedded:false,exported:true,typ:AC,tag:""},{prop:"Mcount",name:"Mcount",embedded:false,exported:true,typ:$Uint16,tag:""},{prop:"Xcount",name:"Xcount",embedded:false,exported:true,typ:$Uint16,tag:""},{prop:"Methods_",name:"Methods_",embedded:false,exported:true,typ:CE,tag:""}]);Z.init("",[{prop:"Type",name:"Type",embedded:true,exported:true,typ:C,tag:""},{prop:"InCount",name:"InCount",embedded:false,exported:true,typ:$Uint16,tag:""},{prop:"OutCount",name:"OutCount",embedded:false,exported:true,typ:$Uint16,tag:""},{prop:"In_",name:"In_",embedded:false,exported:true,typ:CY,tag:""},{prop:"Out_",name:"Out_",embedded:false,exported:true,typ:CY,tag:""}]);AA.init("internal/abi",[{prop:"name",name:"name",embedded:false,exported:false,typ:$String,tag:""},{prop:"tag",name:"tag",embedded:false,exported:false,typ:$String,tag:""},{prop:"exported",name:"exported",embedded:false,exported:false,typ:$Bool,tag:""},{prop:"embedded",name:"embedded",embedded:false,exported:false,typ:$Bool,tag:""},{prop:"pkgPath",name:"pkgPath",embedded:false,exported:false,typ:$String,tag:""}]);AC.init(AA);AE.init(C);};$init=function(){$pkg.$init=function(){};var $f,$c=false,$s=0,$r;if(this!==undefined&&this.$blk!==undefined){$f=this;$c=true;$s=$f.$s;$r=$f.$r;}s:while(true){switch($s){case 0:$r=A.$init();$s=1;case 1:if($c){$c=false;$r=$r.$blk();}if($r&&$r.$blk!==undefined){break s;}$r=B.$init();$s=2;case 2:if($c){$c=false;$r=$r.$blk();}if($r&&$r.$blk!==undefined){break s;}F=new BD(["invalid","bool","int","int8","int16","int32","int64","uint","uint8","uint16","uint32","uint64","uintptr","float32","float64","complex64","complex128","array","chan","func","interface","map","ptr","slice","string","struct","unsafe.Pointer"]);$pkg.JsObjectPtr=W($jsObjectPtr);}return;}if($f===undefined){$f={$blk:$init};}$f.$s=$s;$f.$r=$r;return $f;};$pkg.$init=$init;return $pkg;})();
+$packages["internal/reflectlite"]=(function(){var $pkg={},$init,A,B,C,L,Q,BF,BM,BN,BP,BW,BY,CA,CC,CK,CO,DF,DG,DH,DI,V,W,Y,Z,AA,AB,AC,AF,AH,AJ;A=$packages["github.com/gopherjs/gopherjs/js"];B=$packages["internal/abi"];C=$packages["internal/goarch"];L=$newType(8,$kindInterface,"reflectlite.Type",true,"internal/reflectlite",true,null);Q=$newType(0,$kindStruct,"reflectlite.rtype",true,"internal/reflectlite",false,function(Type_){this.$val=this;if(arguments.length===0){this.Type=BF.nil;return;}this.Type=Type_;});$pkg.Type=L;$pkg.rtype=Q;$pkg.$finishSetup=function(){BF=$ptrType(B.Type);BM=$ptrType(B.UncommonType);BN=$sliceType(B.Method);BP=$ptrType(B.InterfaceType);BW=$ptrType(B.FuncType);BY=$ptrType(B.MapType);CA=$ptrType(B.ArrayType);CC=$ptrType(B.StructType);CK=$ptrType(B.Imethod);CO=$ptrType(B.StructField);DF=$ptrType($Uint8);DG=$sliceType(BF);DH=$sliceType(B.Imethod);DI=$sliceType(B.StructField);$ptrType(Q).prototype.uncommon=function BJ(){var a;a=this;return a.Type.Uncommon();};Q.prototype.uncommon=function(...$args){return this.$val.uncommon(...$args);};$ptrType(Q).prototype.common=function BK(){var a;a=this;return a.Type;};Q.prototype.common=function(...$args){return this.$val.common(...$args);};$ptrType(Q).prototype.exportedMethods=function BL(){var a,b;a=this;b=$clone(a,Q).uncommon();if(b===BM.nil){return BN.nil;}return b.ExportedMethods();};Q.prototype.exportedMethods=function(...$args){return this.$val.exportedMethods(...$args);};$ptrType(Q).prototype.NumMethod=function BO(){var a,b;a=this;b=a.Type.InterfaceType();if(!(b===BP.nil)){return b.NumMethod();}return $clone(a,Q).exportedMethods().$length;};Q.prototype.NumMethod=function(...$args){return this.$val.NumMethod(...$args);};$ptrType(Q).prototype.PkgPath=function BQ(){var a,b;a=this;if(((a.Type.TFlag&4)>>>0)===0){return"";}b=$clone(a,Q).uncommon();if(b===BM.nil){return"";}return $clone($clone(a,Q).nameOff(b.PkgPath),B.Name).Name();};Q.prototype.PkgPath=function(...$args){return this.$val.PkgPath(...$args);};$ptrType(Q).prototype.Name=function BR(){var a,b,c,d,e;a=this;if(!a.Type.HasName()){return"";}b=$clone(a,Q).String();c=b.length-1>>0;d=0;while(true){if(!(c>=0&&(!((b.charCodeAt(c)===46))||!((d===0))))){break;}e=b.charCodeAt(c);if(e===(93)){d=d+(1)>>0;}else if(e===(91)){d=d-(1)>>0;}c=c-(1)>>0;}return $substring(b,(c+1>>0));};Q.prototype.Name=function(...$args){return this.$val.Name(...$args);};V=function BS(a){var a;return new Q.ptr(a);};W=function BT(a){var a,b;b=a.Elem();if(!(b===BF.nil)){return b;}$panic(new $String("reflect: Elem of invalid type "+$clone(V(a),Q).String()));};$ptrType(Q).prototype.Elem=function BU(){var a;a=this;return AC(W($clone(a,Q).common()));};Q.prototype.Elem=function(...$args){return this.$val.Elem(...$args);};$ptrType(Q).prototype.In=function BV(a){var a,b,c,d;b=this;c=b.Type.FuncType();if(c===BW.nil){$panic(new $String("reflect: In of non-func type"));}return AC((d=c.InSlice(),((a<0||a>=d.$length)?($throwRuntimeError("index out of range"),undefined):d.$array[d.$offset+a])));};Q.prototype.In=function(...$args){return this.$val.In(...$args);};$ptrType(Q).prototype.Key=function BX(){var a,b;a=this;b=a.Type.MapType();if(b===BY.nil){$panic(new $String("reflect: Key of non-map type"));}return AC(b.Key);};Q.prototype.Key=function(...$args){return this.$val.Key(...$args);};$ptrType(Q).prototype.Len=function BZ(){var a,b;a=this;b=a.Type.ArrayType();if(b===CA.nil){$panic(new $String("reflect: Len of non-array type"));}return((b.Len>>0));};Q.prototype.Len=function(...$args){return this.$val.Len(...$args);};$ptrType(Q).prototype.NumField=function CB(){var a,b;a=this;b=a.Type.StructType();if(b===CC.nil){$panic(new $String("reflect: NumField of non-struct type"));}return b.Fields.$length;};Q.prototype.NumField=function(...$args){return this.$val.NumField(...$args);};$ptrType(Q).prototype.NumIn=function CD(){var a,b;a=this;b=a.Type.FuncType();if(b===BW.nil){$panic(new $String("reflect: NumIn of non-func type"));}return((b.InCount>>0));};Q.prototype.NumIn=function(...$args){return this.$val.NumIn(...$args);};$ptrType(Q).prototype.NumOut=function CE(){var a,b;a=this;b=a.Type.FuncType();if(b===BW.nil){$panic(new $String("reflect: NumOut of non-func type"));}return b.NumOut();};Q.prototype.NumOut=function(...$args){return this.$val.NumOut(...$args);};$ptrType(Q).prototype.Out=function CF(a){var a,b,c,d;b=this;c=b.Type.FuncType();if(c===BW.nil){$panic(new $String("reflect: Out of non-func type"));}return AC((d=c.OutSlice(),((a<0||a>=d.$length)?($throwRuntimeError("index out of range"),undefined):d.$array[d.$offset+a])));};Q.prototype.Out=function(...$args){return this.$val.Out(...$args);};$ptrType(Q).prototype.Implements=function CH(a){var{a,b,c,d,e,f,$s,$r,$c}=$restore(this,{a});$s=$s||0;s:while(true){switch($s){case 0:b=this;if($interfaceIsEqual(a,$ifaceNil)){$panic(new $String("reflect: nil type passed to Type.Implements"));}c=a.Kind();$s=3;case 3:if($c){$c=false;c=c.$blk();}if(c&&c.$blk!==undefined){break s;}if(!((c===20))){$s=1;continue;}$s=2;continue;case 1:$panic(new $String("reflect: non-interface type passed to Type.Implements"));case 2:d=a.common();$s=4;case 4:if($c){$c=false;d=d.$blk();}if(d&&d.$blk!==undefined){break s;}e=Y(d,$clone(b,Q).common());$s=5;case 5:if($c){$c=false;e=e.$blk();}if(e&&e.$blk!==undefined){break s;}f=e;$s=6;case 6:return f;}return;}var $f={$blk:CH,$c:true,$r,a,b,c,d,e,f,$s};return $f;};Q.prototype.Implements=function(...$args){return this.$val.Implements(...$args);};$ptrType(Q).prototype.AssignableTo=function CI(a){var{a,b,c,d,e,$s,$r,$c}=$restore(this,{a});$s=$s||0;s:while(true){switch($s){case 0:b=this;if($interfaceIsEqual(a,$ifaceNil)){$panic(new $String("reflect: nil type passed to Type.AssignableTo"));}c=a.common();$s=1;case 1:if($c){$c=false;c=c.$blk();}if(c&&c.$blk!==undefined){break s;}d=c;e=$clone(b,Q).common();$s=-1;return Z(d,e)||Y(d,e);}return;}var $f={$blk:CI,$c:true,$r,a,b,c,d,e,$s};return $f;};Q.prototype.AssignableTo=function(...$args){return this.$val.AssignableTo(...$args);};Y=function CJ(a,b){var a,aa,b,c,d,e,f,g,h,i,j,k,l,m,n,o,p,q,r,s,t,u,v,w,x,y,z;c=a.InterfaceType();if(c===BP.nil){return false;}if(c.Methods.$length===0){return true;}d=$clone(V(a),Q);e=$clone(V(b),Q);if(b.Kind()===20){f=(b.kindType);g=0;h=0;while(true){if(!(h<f.Methods.$length)){break;}j=(i=c.Methods,((g<0||g>=i.$length)?($throwRuntimeError("index out of range"),undefined):$indexPtr(i.$array,i.$offset+g,CK)));k=$clone($clone(d,Q).nameOff(j.Name),B.Name);m=(l=f.Methods,((h<0||h>=l.$length)?($throwRuntimeError("index out of range"),undefined):$indexPtr(l.$array,l.$offset+h,CK)));n=$clone($clone(e,Q).nameOff(m.Name),B.Name);if($clone(n,B.Name).Name()===$clone(k,B.Name).Name()&&$clone(e,Q).typeOff(m.Typ)===$clone(d,Q).typeOff(j.Typ)){if(!$clone(k,B.Name).IsExported()){o=AH($clone(k,B.Name));if(o===""){o=$clone(c.PkgPath,B.Name).Name();}p=AH($clone(n,B.Name));if(p===""){p=$clone(f.PkgPath,B.Name).Name();}if(!(o===p)){h=h+(1)>>0;continue;}}g=g+(1)>>0;if(g>=c.Methods.$length){return true;}}h=h+(1)>>0;}return false;}q=b.Uncommon();if(q===BM.nil){return false;}r=0;s=q.Methods();t=0;while(true){if(!(t<((q.Mcount>>0)))){break;}v=(u=c.Methods,((r<0||r>=u.$length)?($throwRuntimeError("index out of range"),undefined):$indexPtr(u.$array,u.$offset+r,CK)));w=$clone($clone(d,Q).nameOff(v.Name),B.Name);x=$clone(((t<0||t>=s.$length)?($throwRuntimeError("index out of range"),undefined):s.$array[s.$offset+t]),B.Method);y=$clone($clone(e,Q).nameOff(x.Name),B.Name);if($clone(y,B.Name).Name()===$clone(w,B.Name).Name()&&$clone(e,Q).typeOff(x.Mtyp)===$clone(d,Q).typeOff(v.Typ)){if(!$clone(w,B.Name).IsExported()){z=AH($clone(w,B.Name));if(z===""){z=$clone(c.PkgPath,B.Name).Name();}aa=AH($clone(y,B.Name));if(aa===""){aa=$clone($clone(e,Q).nameOff(q.PkgPath),B.Name).Name();}if(!(z===aa)){t=t+(1)>>0;continue;}}r=r+(1)>>0;if(r>=c.Methods.$length){return true;}}t=t+(1)>>0;}return false;};Z=function CL(a,b){var a,b;if(a===b){return true;}if(a.HasName()&&b.HasName()||!((a.Kind()===b.Kind()))){return false;}return AB(a,b,true);};AA=function CM(a,b,c){var a,b,c;if(c){return a===b;}if(!($clone(V(a),Q).Name()===$clone(V(b),Q).Name())||!((a.Kind()===b.Kind()))){return false;}return AB(a,b,false);};AB=function CN(a,b,c){var a,b,c,d,e,f,g,h,i,j,k,l,m,n,o,p,q,r,s,t;if(a===b){return true;}d=a.Kind();if(!((d===b.Kind()))){return false;}if(1<=d&&d<=16||(d===24)||(d===26)){return true;}e=d;if(e===(17)){return(a.Len()===b.Len())&&AA(a.Elem(),b.Elem(),c);}else if(e===(18)){if((b.ChanDir()===3)&&AA(a.Elem(),b.Elem(),c)){return true;}return(b.ChanDir()===a.ChanDir())&&AA(a.Elem(),b.Elem(),c);}else if(e===(19)){f=(a.kindType);g=(b.kindType);if(!((f.OutCount===g.OutCount))||!((f.InCount===g.InCount))){return false;}h=0;while(true){if(!(h<f.NumIn())){break;}if(!AA(f.In(h),g.In(h),c)){return false;}h=h+(1)>>0;}i=0;while(true){if(!(i<f.NumOut())){break;}if(!AA(f.Out(i),g.Out(i),c)){return false;}i=i+(1)>>0;}return true;}else if(e===(20)){j=(a.kindType);k=(b.kindType);if((j.Methods.$length===0)&&(k.Methods.$length===0)){return true;}return false;}else if(e===(21)){return AA(a.Key(),b.Key(),c)&&AA(a.Elem(),b.Elem(),c);}else if((e===(22))||(e===(23))){return AA(a.Elem(),b.Elem(),c);}else if(e===(25)){l=(a.kindType);m=(b.kindType);if(!((l.Fields.$length===m.Fields.$length))){return false;}if(!($clone(l.PkgPath,B.Name).Name()===$clone(m.PkgPath,B.Name).Name())){return false;}n=l.Fields;o=0;while(true){if(!(o<n.$length)){break;}p=o;r=(q=l.Fields,((p<0||p>=q.$length)?($throwRuntimeError("index out of range"),undefined):$indexPtr(q.$array,q.$offset+p,CO)));t=(s=m.Fields,((p<0||p>=s.$length)?($throwRuntimeError("index out of range"),undefined):$indexPtr(s.$array,s.$offset+p,CO)));if(!($clone(r.Name,B.Name).Name()===$clone(t.Name,B.Name).Name())){return false;}if(!AA(r.Typ,t.Typ,c)){return false;}if(c&&!($clone(r.Name,B.Name).Tag()===$clone(t.Name,B.Name).Tag())){return false;}if(!((r.Offset===t.Offset))){return false;}if(!(r.Embedded()===t.Embedded())){return false;}o++;}return true;}return false;};AC=function CP(a){var a,b;if(a===BF.nil){return $ifaceNil;}return(b=V(a),new b.constructor.elem(b));};$ptrType(Q).prototype.Comparable=function CX(){var a;a=this;return $clone(a,Q).common().Comparable();};Q.prototype.Comparable=function(...$args){return this.$val.Comparable(...$args);};$ptrType(Q).prototype.String=function CY(){var a;a=this;return $clone(a,Q).common().String();};Q.prototype.String=function(...$args){return this.$val.String(...$args);};AF=function DD(){var{a,b,c,d,e,f,$s,$r,$c}=$restore(this,{});$s=$s||0;s:while(true){switch($s){case 0:a=(function DE(a){var a;});$r=a((b=new Q.ptr(BF.nil),new b.constructor.elem(b)));$s=1;case 1:if($c){$c=false;$r=$r.$blk();}if($r&&$r.$blk!==undefined){break s;}$r=a((c=new B.UncommonType.ptr(B.NameOff.nil,0,0,BN.nil),new c.constructor.elem(c)));$s=2;case 2:if($c){$c=false;$r=$r.$blk();}if($r&&$r.$blk!==undefined){break s;}$r=a((d=new B.FuncType.ptr(new B.Type.ptr(0,0,0,0,0,0,0,$throwNilPointerError,DF.nil,B.NameOff.nil,B.TypeOff.nil),0,0,DG.nil,DG.nil),new d.constructor.elem(d)));$s=3;case 3:if($c){$c=false;$r=$r.$blk();}if($r&&$r.$blk!==undefined){break s;}$r=a((e=new B.InterfaceType.ptr(new B.Type.ptr(0,0,0,0,0,0,0,$throwNilPointerError,DF.nil,B.NameOff.nil,B.TypeOff.nil),new B.Name.ptr("","",false,false,""),DH.nil),new e.constructor.elem(e)));$s=4;case 4:if($c){$c=false;$r=$r.$blk();}if($r&&$r.$blk!==undefined){break s;}$r=a((f=new B.StructType.ptr(new B.Type.ptr(0,0,0,0,0,0,0,$throwNilPointerError,DF.nil,B.NameOff.nil,B.TypeOff.nil),new B.Name.ptr("","",false,false,""),DI.nil),new f.constructor.elem(f)));$s=5;case 5:if($c){$c=false;$r=$r.$blk();}if($r&&$r.$blk!==undefined){break s;}$s=-1;return;}return;}var $f={$blk:DD,$c:true,$r,a,b,c,d,e,f,$s};return $f;};AH=function DK(a){var a;return $clone(a,B.Name).PkgPath();};$ptrType(Q).prototype.nameOff=function DL(a){var a,b;b=this;return b.Type.NameOff(a);};Q.prototype.nameOff=function(...$args){return this.$val.nameOff(...$args);};$ptrType(Q).prototype.typeOff=function DM(a){var a,b;b=this;return b.Type.TypeOff(a);};Q.prototype.typeOff=function(...$args){return this.$val.typeOff(...$args);};AJ=function DO(a){var a,b;if($interfaceIsEqual(a,$ifaceNil)){return $ifaceNil;}return(b=V(B.ReflectType(a.constructor)),new b.constructor.elem(b));};$pkg.TypeOf=AJ;Q.methods=[{prop:"uncommon",name:"uncommon",pkg:"internal/reflectlite",typ:$funcType([],[BM],false)},{prop:"common",name:"common",pkg:"internal/reflectlite",typ:$funcType([],[BF],false)},{prop:"exportedMethods",name:"exportedMethods",pkg:"internal/reflectlite",typ:$funcType([],[BN],false)},{prop:"NumMethod",name:"NumMethod",pkg:"",typ:$funcType([],[$Int],false)},{prop:"PkgPath",name:"PkgPath",pkg:"",typ:$funcType([],[$String],false)},{prop:"Name",name:"Name",pkg:"",typ:$funcType([],[$String],false)},{prop:"Elem",name:"Elem",pkg:"",typ:$funcType([],[L],false)},{prop:"In",name:"In",pkg:"",typ:$funcType([$Int],[L],false)},{prop:"Key",name:"Key",pkg:"",typ:$funcType([],[L],false)},{prop:"Len",name:"Len",pkg:"",typ:$funcType([],[$Int],false)},{prop:"NumField",name:"NumField",pkg:"",typ:$funcType([],[$Int],false)},{prop:"NumIn",name:"NumIn",pkg:"",typ:$funcType([],[$Int],false)},{prop:"NumOut",name:"NumOut",pkg:"",typ:$funcType([],[$Int],false)},{prop:"Out",name:"Out",pkg:"",typ:$funcType([$Int],[L],false)},{prop:"Implements",name:"Implements",pkg:"",typ:$funcType([L],[$Bool],false)},{prop:"AssignableTo",name:"AssignableTo",pkg:"",typ:$funcType([L],[$Bool],false)},{prop:"Comparable",name:"Comparable",pkg:"",typ:$funcType([],[$Bool],false)},{prop:"String",name:"String",pkg:"",typ:$funcType([],[$String],false)},{prop:"nameOff",name:"nameOff",pkg:"internal/reflectlite",typ:$funcType([B.NameOff],[B.Name],false)},{prop:"typeOff",name:"typeOff",pkg:"internal/reflectlite",typ:$funcType([B.TypeOff],[BF],false)}];L.init([{prop:"AssignableTo",name:"AssignableTo",pkg:"",typ:$funcType([L],[$Bool],false)},{prop:"Comparable",name:"Comparable",pkg:"",typ:$funcType([],[$Bool],false)},{prop:"Elem",name:"Elem",pkg:"",typ:$funcType([],[L],false)},{prop:"Implements",name:"Implements",pkg:"",typ:$funcType([L],[$Bool],false)},{prop:"Kind",name:"Kind",pkg:"",typ:$funcType([],[B.Kind],false)},{prop:"Name",name:"Name",pkg:"",typ:$funcType([],[$String],false)},{prop:"PkgPath",name:"PkgPath",pkg:"",typ:$funcType([],[$String],false)},{prop:"Size",name:"Size",pkg:"",typ:$funcType([],[$Uintptr],false)},{prop:"String",name:"String",pkg:"",typ:$funcType([],[$String],false)},{prop:"common",name:"common",pkg:"internal/reflectlite",typ:$funcType([],[BF],false)},{prop:"uncommon",name:"uncommon",pkg:"internal/reflectlite",typ:$funcType([],[BM],false)}]);Q.init("",[{prop:"Type",name:"Type",embedded:true,exported:true,typ:BF,tag:""}]);};$init=function(){$pkg.$init=function(){};var $f,$c=false,$s=0,$r;if(this!==undefined&&this.$blk!==undefined){$f=this;$c=true;$s=$f.$s;$r=$f.$r;}s:while(true){switch($s){case 0:$r=A.$init();$s=1;case 1:if($c){$c=false;$r=$r.$blk();}if($r&&$r.$blk!==undefined){break s;}$r=B.$init();$s=2;case 2:if($c){$c=false;$r=$r.$blk();}if($r&&$r.$blk!==undefined){break s;}$r=C.$init();$s=3;case 3:if($c){$c=false;$r=$r.$blk();}if($r&&$r.$blk!==undefined){break s;}$r=AF();$s=4;case 4:if($c){$c=false;$r=$r.$blk();}if($r&&$r.$blk!==undefined){break s;}}return;}if($f===undefined){$f={$blk:$init};}$f.$s=$s;$f.$r=$r;return $f;};$pkg.$init=$init;return $pkg;})();
+$packages["errors"]=(function(){var $pkg={},$init,A,I,J,Z,E,a,H;A=$packages["internal/reflectlite"];I=$newType(0,$kindStruct,"errors.errorString",true,"errors",false,function(s_){this.$val=this;if(arguments.length===0){this.s="";return;}this.s=s_;});$pkg.errorString=I;$pkg.$finishSetup=function(){J=$ptrType($error);Z=$ptrType(I);H=function W(b){var b;return new I.ptr(b);};$pkg.New=H;$ptrType(I).prototype.Error=function X(){var b;b=this;return b.s;};Z.methods=[{prop:"Error",name:"Error",pkg:"",typ:$funcType([],[$String],false)}];I.init("errors",[{prop:"s",name:"s",embedded:false,exported:false,typ:$String,tag:""}]);};$init=function(){$pkg.$init=function(){};var $f,$c=false,$s=0,$r;if(this!==undefined&&this.$blk!==undefined){$f=this;$c=true;$s=$f.$s;$r=$f.$r;}s:while(true){switch($s){case 0:$r=A.$init();$s=1;case 1:if($c){$c=false;$r=$r.$blk();}if($r&&$r.$blk!==undefined){break s;}a=A.TypeOf((J.nil)).Elem();$s=2;case 2:if($c){$c=false;a=a.$blk();}if(a&&a.$blk!==undefined){break s;}E=a;$pkg.ErrUnsupported=H("unsupported operation");}return;}if($f===undefined){$f={$blk:$init};}$f.$s=$s;$f.$r=$r;return $f;};$pkg.$init=$init;return $pkg;})();
+$packages["honnef.co/go/js/util"]=(function(){var $pkg={},$init,A,G,R,S;A=$packages["github.com/gopherjs/gopherjs/js"];G=$newType(0,$kindStruct,"util.EventTarget",true,"honnef.co/go/js/util",true,function(Object_){this.$val=this;if(arguments.length===0){this.Object=null;return;}this.Object=Object_;});$pkg.EventTarget=G;$pkg.$finishSetup=function(){R=$ptrType(A.Object);S=$funcType([R],[],false);$ptrType(G).prototype.AddEventListener=function Q(a,b,c){var a,b,c,d;d=this;d.Object.addEventListener($externalize(a,$String),$externalize(c,S),$externalize(b,$Bool));};G.prototype.AddEventListener=function(...$args){return this.$val.AddEventListener(...$args);};$ptrType(G).prototype.RemoveEventListener=function T(a,b,c){var a,b,c,d;d=this;d.Object.removeEventListener($externalize(a,$String),$externalize(c,S),$externalize(b,$Bool));};G.prototype.RemoveEventListener=function(...$args){return this.$val.RemoveEventListener(...$args);};G.methods=[{prop:"AddEventListener",name:"AddEventListener",pkg:"",typ:$funcType([$String,$Bool,S],[],false)},{prop:"RemoveEventListener",name:"RemoveEventListener",pkg:"",typ:$funcType([$String,$Bool,S],[],false)}];G.init("",[{prop:"Object",name:"Object",embedded:true,exported:true,typ:R,tag:""}]);};$init=function(){$pkg.$init=function(){};var $f,$c=false,$s=0,$r;if(this!==undefined&&this.$blk!==undefined){$f=this;$c=true;$s=$f.$s;$r=$f.$r;}s:while(true){switch($s){case 0:$r=A.$init();$s=1;case 1:if($c){$c=false;$r=$r.$blk();}if($r&&$r.$blk!==undefined){break s;}}return;}if($f===undefined){$f={$blk:$init};}$f.$s=$s;$f.$r=$r;return $f;};$pkg.$init=$init;return $pkg;})();
+$packages["honnef.co/go/js/xhr"]=(function(){var $pkg={},$init,A,B,C,D,E,X,Y,Z,AA,F;A=$packages["errors"];B=$packages["github.com/gopherjs/gopherjs/js"];C=$packages["honnef.co/go/js/util"];D=$newType(0,$kindStruct,"xhr.Request",true,"honnef.co/go/js/xhr",true,function(Object_,EventTarget_,ReadyState_,Response_,ResponseText_,ResponseType_,ResponseXML_,Status_,StatusText_,Timeout_,WithCredentials_,ch_){this.$val=this;if(arguments.length===0){this.Object=null;this.EventTarget=new C.EventTarget.ptr(null);this.ReadyState=0;this.Response=null;this.ResponseText="";this.ResponseType="";this.ResponseXML=null;this.Status=0;this.StatusText="";this.Timeout=0;this.WithCredentials=false;this.ch=$chanNil;return;}this.Object=Object_;this.EventTarget=EventTarget_;this.ReadyState=ReadyState_;this.Response=Response_;this.ResponseText=ResponseText_;this.ResponseType=ResponseType_;this.ResponseXML=ResponseXML_;this.Status=Status_;this.StatusText=StatusText_;this.Timeout=Timeout_;this.WithCredentials=WithCredentials_;this.ch=ch_;});E=$newType(0,$kindStruct,"xhr.Upload",true,"honnef.co/go/js/xhr",true,function(Object_,EventTarget_){this.$val=this;if(arguments.length===0){this.Object=null;this.EventTarget=new C.EventTarget.ptr(null);return;}this.Object=Object_;this.EventTarget=EventTarget_;});$pkg.Request=D;$pkg.Upload=E;$pkg.$finishSetup=function(){X=$ptrType(E);Y=$ptrType(D);Z=$ptrType(B.Object);AA=$chanType($error,false,false);$ptrType(D).prototype.Upload=function H(){var a,b;a=this;b=a.Object.upload;return new E.ptr(b,$clone(new C.EventTarget.ptr(b),C.EventTarget));};F=function I(a,b){var a,b,c,d;c=new($global.XMLHttpRequest)();d=new D.ptr(c,$clone(new C.EventTarget.ptr(c),C.EventTarget),0,null,"","",null,0,"",0,false,$chanNil);d.Object.open($externalize(a,$String),$externalize(b,$String),$externalize(true,$Bool));return d;};$pkg.NewRequest=F;$ptrType(D).prototype.ResponseHeaders=function J(){var a;a=this;return $internalize(a.Object.getAllResponseHeaders(),$String);};$ptrType(D).prototype.ResponseHeader=function K(a){var a,b,c;b=this;c=b.Object.getResponseHeader($externalize(a,$String));if(c===null){return"";}return $internalize(c,$String);};$ptrType(D).prototype.Abort=function L(){var a,b;a=this;if(a.ch===$chanNil){return;}a.Object.abort();b=$select([[a.ch,$pkg.ErrAborted],[]]);if(b[0]===0){}else if(b[0]===1){}};$ptrType(D).prototype.OverrideMimeType=function M(a){var a,b;b=this;b.Object.overrideMimeType($externalize(a,$String));};$ptrType(D).prototype.Send=function N(a){var{a,b,c,d,$s,$r,$c}=$restore(this,{a});$s=$s||0;s:while(true){switch($s){case 0:b=[b];b[0]=this;if(!(b[0].ch===$chanNil)){$panic(new $String("must not use a Request for multiple requests"));}b[0].ch=new $Chan($error,1);$clone(b[0].EventTarget,C.EventTarget).AddEventListener("load",false,(function(b){return function O(c){var c;$go((function(b){return function P(){var{$s,$r,$c}=$restore(this,{});$s=$s||0;s:while(true){switch($s){case 0:$r=$send(b[0].ch,$ifaceNil);$s=1;case 1:if($c){$c=false;$r=$r.$blk();}if($r&&$r.$blk!==undefined){break s;}$s=-1;return;}return;}var $f={$blk:P,$c:true,$r,$s};return $f;};})(b),[]);};})(b));$clone(b[0].EventTarget,C.EventTarget).AddEventListener("error",false,(function(b){return function Q(c){var c;$go((function(b){return function R(){var{$s,$r,$c}=$restore(this,{});$s=$s||0;s:while(true){switch($s){case 0:$r=$send(b[0].ch,$pkg.ErrFailure);$s=1;case 1:if($c){$c=false;$r=$r.$blk();}if($r&&$r.$blk!==undefined){break s;}$s=-1;return;}return;}var $f={$blk:R,$c:true,$r,$s};return $f;};})(b),[]);};})(b));$clone(b[0].EventTarget,C.EventTarget).AddEventListener("timeout",false,(function(b){return function S(c){var c;$go((function(b){return function T(){var{$s,$r,$c}=$restore(this,{});$s=$s||0;s:while(true){switch($s){case 0:$r=$send(b[0].ch,$pkg.ErrTimeout);$s=1;case 1:if($c){$c=false;$r=$r.$blk();}if($r&&$r.$blk!==undefined){break s;}$s=-1;return;}return;}var $f={$blk:T,$c:true,$r,$s};return $f;};})(b),[]);};})(b));b[0].Object.send($externalize(a,$emptyInterface));c=$recv(b[0].ch);$s=1;case 1:if($c){$c=false;c=c.$blk();}if(c&&c.$blk!==undefined){break s;}d=c[0];$s=-1;return d;}return;}var $f={$blk:N,$c:true,$r,a,b,c,d,$s};return $f;};$ptrType(D).prototype.SetRequestHeader=function U(a,b){var a,b,c;c=this;c.Object.setRequestHeader($externalize(a,$String),$externalize(b,$String));};Y.methods=[{prop:"Upload",name:"Upload",pkg:"",typ:$funcType([],[X],false)},{prop:"ResponseHeaders",name:"ResponseHeaders",pkg:"",typ:$funcType([],[$String],false)},{prop:"ResponseHeader",name:"ResponseHeader",pkg:"",typ:$funcType([$String],[$String],false)},{prop:"Abort",name:"Abort",pkg:"",typ:$funcType([],[],false)},{prop:"OverrideMimeType",name:"OverrideMimeType",pkg:"",typ:$funcType([$String],[],false)},{prop:"Send",name:"Send",pkg:"",typ:$funcType([$emptyInterface],[$error],false)},{prop:"SetRequestHeader",name:"SetRequestHeader",pkg:"",typ:$funcType([$String,$String],[],false)}];D.init("honnef.co/go/js/xhr",[{prop:"Object",name:"Object",embedded:true,exported:true,typ:Z,tag:""},{prop:"EventTarget",name:"EventTarget",embedded:true,exported:true,typ:C.EventTarget,tag:""},{prop:"ReadyState",name:"ReadyState",embedded:false,exported:true,typ:$Int,tag:"js:\"readyState\""},{prop:"Response",name:"Response",embedded:false,exported:true,typ:Z,tag:"js:\"response\""},{prop:"ResponseText",name:"ResponseText",embedded:false,exported:true,typ:$String,tag:"js:\"responseText\""},{prop:"ResponseType",name:"ResponseType",embedded:false,exported:true,typ:$String,tag:"js:\"responseType\""},{prop:"ResponseXML",name:"ResponseXML",embedded:false,exported:true,typ:Z,tag:"js:\"responseXML\""},{prop:"Status",name:"Status",embedded:false,exported:true,typ:$Int,tag:"js:\"status\""},{prop:"StatusText",name:"StatusText",embedded:false,exported:true,typ:$String,tag:"js:\"statusText\""},{prop:"Timeout",name:"Timeout",embedded:false,exported:true,typ:$Int,tag:"js:\"timeout\""},{prop:"WithCredentials",name:"WithCredentials",embedded:false,exported:true,typ:$Bool,tag:"js:\"withCredentials\""},{prop:"ch",name:"ch",embedded:false,exported:false,typ:AA,tag:""}]);E.init("",[{prop:"Object",name:"Object",embedded:true,exported:true,typ:Z,tag:""},{prop:"EventTarget",name:"EventTarget",embedded:true,exported:true,typ:C.EventTarget,tag:""}]);};$init=function(){$pkg.$init=function(){};var $f,$c=false,$s=0,$r;if(this!==undefined&&this.$blk!==undefined){$f=this;$c=true;$s=$f.$s;$r=$f.$r;}s:while(true){switch($s){case 0:$r=A.$init();$s=1;case 1:if($c){$c=false;$r=$r.$blk();}if($r&&$r.$blk!==undefined){break s;}$r=B.$init();$s=2;case 2:if($c){$c=false;$r=$r.$blk();}if($r&&$r.$blk!==undefined){break s;}$r=C.$init();$s=3;case 3:if($c){$c=false;$r=$r.$blk();}if($r&&$r.$blk!==undefined){break s;}$pkg.ErrAborted=A.New("request aborted");$pkg.ErrTimeout=A.New("request timed out");$pkg.ErrFailure=A.New("send failed");}return;}if($f===undefined){$f={$blk:$init};}$f.$s=$s;$f.$r=$r;return $f;};$pkg.$init=$init;return $pkg;})();
+$packages["main"]=(function(){var $pkg={},$init,A,B,C,D,E,H,I,J,K,L,M,N,F;A=$packages["github.com/cnguy/gopherjs-frappe-charts"];B=$packages["github.com/gopherjs/gopherjs/js"];C=$packages["honnef.co/go/js/xhr"];D=$newType(0,$kindStruct,"main.tempResponse",true,"main",false,function(Object_,Hour_,Temp_){this.$val=this;if(arguments.length===0){this.Object=null;this.Hour=H.nil;this.Temp=I.nil;return;}this.Object=Object_;this.Hour=Hour_;this.Temp=Temp_;});E=$newType(0,$kindStruct,"main.annotationResponse",true,"main",false,function(Object_,Hour_,Text_){this.$val=this;if(arguments.length===0){this.Object=null;this.Hour="";this.Text="";return;}this.Object=Object_;this.Hour=Hour_;this.Text=Text_;});$pkg.tempResponse=D;$pkg.annotationResponse=E;$pkg.$finishSetup=function(){H=$sliceType($String);I=$sliceType($Float64);J=$ptrType(A.SpecificValue);K=$sliceType(J);L=$ptrType(A.Dataset);M=$sliceType(L);N=$ptrType(B.Object);F=function G(){var{a,b,c,d,e,f,g,h,i,j,k,l,m,n,o,p,q,r,s,t,$s,$r,$c}=$restore(this,{});$s=$s||0;s:while(true){switch($s){case 0:a=$internalize($global.jstz.determine().name(),$String);b=C.NewRequest("GET","/restricted/weather");b.Object.timeout=5000;b.SetRequestHeader("Content-Type","application/json");b.SetRequestHeader("TZ",a);b.Object.responseType=$externalize("json",$String);c=b.Send($ifaceNil);$s=1;case 1:if($c){$c=false;c=c.$blk();}if(c&&c.$blk!==undefined){break s;}d=c;if(!($interfaceIsEqual(d,$ifaceNil))){console.log(d);$s=-1;return;}e=new D.ptr(b.Object.response,H.nil,I.nil);f=C.NewRequest("GET","/restricted/indoortemp");f.Object.timeout=5000;f.SetRequestHeader("Content-Type","application/json");f.SetRequestHeader("TZ",a);f.Object.responseType=$externalize("json",$String);g=f.Send($ifaceNil);$s=2;case 2:if($c){$c=false;g=g.$blk();}if(g&&g.$blk!==undefined){break s;}h=g;if(!($interfaceIsEqual(h,$ifaceNil))){console.log(h);$s=-1;return;}i=new D.ptr(f.Object.response,H.nil,I.nil);j=C.NewRequest("GET","/restricted/annotations");j.Object.timeout=5000;j.SetRequestHeader("Content-Type","application/json");j.SetRequestHeader("TZ",a);j.Object.responseType=$externalize("json",$String);k=j.Send($ifaceNil);$s=3;case 3:if($c){$c=false;k=k.$blk();}if(k&&k.$blk!==undefined){break s;}l=k;if(!($interfaceIsEqual(l,$ifaceNil))){console.log(l);$s=-1;return;}m=$internalize(e.Object.hour,H);n=0;while(true){if(!(n<$parseInt(j.Object.response.length))){break;}o=new E.ptr(j.Object.response[n],"","");p=m;q=0;while(true){if(!(q<p.$length)){break;}r=q;if(((r<0||r>=m.$length)?($throwRuntimeError("index out of range"),undefined):m.$array[m.$offset+r])===$internalize(o.Object.hour,$String)){((r<0||r>=m.$length)?($throwRuntimeError("index out of range"),undefined):m.$array[m.$offset+r]=((r<0||r>=m.$length)?($throwRuntimeError("index out of range"),undefined):m.$array[m.$offset+r])+(" ("+$internalize(o.Object.text,$String)+")"));break;}q++;}n=n+(1)>>0;}s=A.NewChartData();s.Object.labels=$externalize(m,H);s.Object.specific_values=$externalize(new K([A.NewSpecificValue("","solid",0)]),K);s.Object.datasets=$externalize(new M([A.NewDataset("Outdoor Temperatur (Celsius)",$internalize(e.Object.temp,I)),A.NewDataset("Indoor Temperature (Celsius)",$internalize(i.Object.temp,I))]),M);t=A.NewLineChart("#chart",s);t.Object.region_fill=1;t.Render();$s=-1;return;}return;}var $f={$blk:G,$c:true,$r,a,b,c,d,e,f,g,h,i,j,k,l,m,n,o,p,q,r,s,t,$s};return $f;};D.init("",[{prop:"Object",name:"Object",embedded:true,exported:true,typ:N,tag:""},{prop:"Hour",name:"Hour",embedded:false,exported:true,typ:H,tag:"js:\"hour\""},{prop:"Temp",name:"Temp",embedded:false,exported:true,typ:I,tag:"js:\"temp\""}]);E.init("",[{prop:"Object",name:"Object",embedded:true,exported:true,typ:N,tag:""},{prop:"Hour",name:"Hour",embedded:false,exported:true,typ:$String,tag:"js:\"hour\""},{prop:"Text",name:"Text",embedded:false,exported:true,typ:$String,tag:"js:\"text\""}]);};$init=function(){$pkg.$init=function(){};var $f,$c=false,$s=0,$r;if(this!==undefined&&this.$blk!==undefined){$f=this;$c=true;$s=$f.$s;$r=$f.$r;}s:while(true){switch($s){case 0:$r=A.$init();$s=1;case 1:if($c){$c=false;$r=$r.$blk();}if($r&&$r.$blk!==undefined){break s;}$r=B.$init();$s=2;case 2:if($c){$c=false;$r=$r.$blk();}if($r&&$r.$blk!==undefined){break s;}$r=C.$init();$s=3;case 3:if($c){$c=false;$r=$r.$blk();}if($r&&$r.$blk!==undefined){break s;}if($pkg===$mainPkg){$s=4;continue;}$s=5;continue;case 4:$r=F();$s=6;case 6:if($c){$c=false;$r=$r.$blk();}if($r&&$r.$blk!==undefined){break s;}$mainFinished=true;case 5:}return;}if($f===undefined){$f={$blk:$init};}$f.$s=$s;$f.$r=$r;return $f;};$pkg.$init=$init;return $pkg;})();
+$callForAllPackages("$finishSetup");
 $synthesizeMethods();
+$callForAllPackages("$initLinknames");
 var $mainPkg = $packages["main"];
 $packages["runtime"].$init();
 $go($mainPkg.$init, []);
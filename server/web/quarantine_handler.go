@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/danielfireman/temp-to-go/server/tsmongo"
+	"github.com/labstack/echo"
+)
+
+const (
+	quarantinePath = "/restricted/quarantine"
+	// timestampParam is the form parameter with the timestamp (Unix seconds) of the reading to
+	// release or discard.
+	timestampParam = "timestamp"
+
+	defaultQuarantineHours = 7 * 24
+)
+
+type quarantineHandler struct {
+}
+
+// handle renders the readings quarantined within the last hours (a week, unless specified).
+func (h *quarantineHandler) handle(c echo.Context) error {
+	hours := defaultQuarantineHours
+	if v := c.QueryParam(hoursParam); v != "" {
+		var err error
+		if hours, err = strconv.Atoi(v); err != nil || hours <= 0 {
+			return c.NoContent(http.StatusBadRequest)
+		}
+	}
+	now := time.Now()
+	readings, err := tsmongo.Quarantined(c.Request().Context(), requestStore(c), now.Add(-time.Duration(hours)*time.Hour), now)
+	if err != nil {
+		c.Logger().Errorf("[/restricted/quarantine] %q\n", err)
+		return c.NoContent(storeErrorStatus(err))
+	}
+	return c.Render(http.StatusOK, "quarantine", struct {
+		Hours    int
+		Readings []tsmongo.QuarantinedReading
+	}{hours, readings})
+}
+
+// handleRelease stores the quarantined reading.
+func (h *quarantineHandler) handleRelease(c echo.Context) error {
+	return h.review(c, "/restricted/quarantine/release", tsmongo.Release)
+}
+
+// handleDiscard removes the quarantined reading.
+func (h *quarantineHandler) handleDiscard(c echo.Context) error {
+	return h.review(c, "/restricted/quarantine/discard", tsmongo.Discard)
+}
+
+func (h *quarantineHandler) review(c echo.Context, path string, f func(context.Context, tsmongo.Store, tsmongo.Series, time.Time) error) error {
+	sec, err := strconv.ParseInt(c.FormValue(timestampParam), 10, 64)
+	if err != nil || c.FormValue(fieldParam) == "" {
+		return c.NoContent(http.StatusBadRequest)
+	}
+	room := c.FormValue(roomParam)
+	if room == "" {
+		room = tsmongo.DefaultRoom
+	}
+	series := tsmongo.Series{Field: c.FormValue(fieldParam), Room: room}
+	switch err := f(c.Request().Context(), requestStore(c), series, time.Unix(sec, 0)); err {
+	case nil:
+		return c.Redirect(http.StatusFound, quarantinePath)
+	case tsmongo.ErrNotFound:
+		return c.NoContent(http.StatusNotFound)
+	default:
+		c.Logger().Errorf("[%s] %q\n", path, err)
+		return c.NoContent(storeErrorStatus(err))
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/danielfireman/temp-to-go/server/tsmongo"
+	"github.com/labstack/echo"
+)
+
+const metricParam = "metric"
+
+type sensorHandler struct {
+}
+
+func (h *sensorHandler) handleGet(c echo.Context) error {
+	sensorService := tsmongo.NewSensorService(requestStore(c), c.QueryParam(roomParam))
+	rs, err := sensorService.FetchContext(c.Request().Context(), c.QueryParam(metricParam), time.Now().Add(-25*time.Hour), time.Now(), tsmongo.Hourly)
+	switch err {
+	case nil:
+	case tsmongo.ErrInvalidMetric:
+		return c.NoContent(http.StatusBadRequest)
+	default:
+		c.Logger().Errorf("[/restricted/sensor] %q\n", err)
+		return c.NoContent(storeErrorStatus(err))
+	}
+	loc, err := time.LoadLocation(c.Request().Header.Get(timezoneHeader))
+	if err != nil {
+		c.Logger().Error(err)
+		loc = time.UTC
+	}
+	var resp sensorResponse
+	for _, r := range rs {
+		resp.Metric = r.Metric
+		t := r.Timestamp.In(loc)
+		resp.Hour = append(resp.Hour, t.Format("3pm"))
+		resp.Value = append(resp.Value, r.Value)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+type sensorResponse struct {
+	tsmongo.Metric
+	Hour  []string  `json:"hour,omitempty"`
+	Value []float64 `json:"value,omitempty"`
+}
@@ -10,6 +10,7 @@ import (
 
 const (
 	loggedInSessionField = "loggedin"
+	userSessionField     = "user"
 	sessionName          = "session"
 )
 
@@ -28,6 +29,7 @@ func (h *loginHandler) handle(c echo.Context) error {
 			HttpOnly: true,
 		}
 		sess.Values[loggedInSessionField] = true
+		sess.Values[userSessionField] = user
 		sess.Save(c.Request(), c.Response())
 		return c.Redirect(http.StatusFound, restrictedPath)
 	}
@@ -48,10 +50,21 @@ func (h *logoutHandler) handle(c echo.Context) error {
 		MaxAge: -1, // MaxAge<0 means delete cookie immediately.
 	}
 	delete(sess.Values, loggedInSessionField)
+	delete(sess.Values, userSessionField)
 	sess.Save(c.Request(), c.Response())
 	return c.Redirect(http.StatusFound, "/")
 }
 
+// sessionUser returns the user logged in, or an empty string if unknown.
+func sessionUser(c echo.Context) string {
+	sess, err := session.Get(sessionName, c)
+	if err != nil {
+		return ""
+	}
+	user, _ := sess.Values[userSessionField].(string)
+	return user
+}
+
 func loginCheckMiddleware(in echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		sess, err := session.Get(sessionName, c)
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/danielfireman/temp-to-go/server/tsmongo"
+	"github.com/labstack/echo"
+)
+
+const (
+	sleepPath = "/restricted/sleep"
+	// Query parameters of the sleep pages: the number of nights summarized and the comfort
+	// temperature threshold.
+	daysParam    = "days"
+	comfortParam = "comfort"
+	// qualityParam is the form parameter with the rating of new sleep sessions.
+	qualityParam = "quality"
+
+	defaultSleepDays = 7
+)
+
+type sleepHandler struct {
+	comfort float64 // Default comfort temperature threshold.
+}
+
+var errInvalidSleepQuery = fmt.Errorf("Invalid sleep query")
+
+// summaries summarizes the sleep sessions of the room within the last days.
+func (h *sleepHandler) summaries(c echo.Context) ([]tsmongo.SleepSummary, int, float64, error) {
+	days, comfort := defaultSleepDays, h.comfort
+	var err error
+	if v := c.QueryParam(daysParam); v != "" {
+		if days, err = strconv.Atoi(v); err != nil || days <= 0 {
+			return nil, 0, 0, errInvalidSleepQuery
+		}
+	}
+	if v := c.QueryParam(comfortParam); v != "" {
+		if comfort, err = strconv.ParseFloat(v, 64); err != nil {
+			return nil, 0, 0, errInvalidSleepQuery
+		}
+	}
+	now := time.Now()
+	ss := tsmongo.NewSleepService(requestStore(c), c.QueryParam(roomParam))
+	summaries, err := ss.SummariesContext(c.Request().Context(), now.AddDate(0, 0, -days), now, comfort)
+	return summaries, days, comfort, err
+}
+
+// handle renders the summaries of the sleep sessions, along with the form to log new ones.
+func (h *sleepHandler) handle(c echo.Context) error {
+	summaries, days, comfort, err := h.summaries(c)
+	switch err {
+	case nil:
+		qualities := make([]int, tsmongo.MaxSleepQuality)
+		for i := range qualities {
+			qualities[i] = i + 1
+		}
+		return c.Render(http.StatusOK, "sleep", struct {
+			Days         int
+			Comfort      float64
+			Summaries    []tsmongo.SleepSummary
+			Action       string
+			StartParam   string
+			FinishParam  string
+			QualityParam string
+			Qualities    []int
+		}{days, comfort, summaries, sleepPath, startParam, finishParam, qualityParam, qualities})
+	case errInvalidSleepQuery:
+		return c.NoContent(http.StatusBadRequest)
+	default:
+		c.Logger().Errorf("[/restricted/sleep] %q\n", err)
+		return c.NoContent(storeErrorStatus(err))
+	}
+}
+
+// handleSummaries returns the summaries of the sleep sessions.
+func (h *sleepHandler) handleSummaries(c echo.Context) error {
+	summaries, _, _, err := h.summaries(c)
+	switch err {
+	case nil:
+		return c.JSON(http.StatusOK, summaries)
+	case errInvalidSleepQuery:
+		return c.NoContent(http.StatusBadRequest)
+	default:
+		c.Logger().Errorf("[/restricted/sleep/summaries] %q\n", err)
+		return c.NoContent(storeErrorStatus(err))
+	}
+}
+
+// handlePost logs a sleep session.
+func (h *sleepHandler) handlePost(c echo.Context) error {
+	start, err := time.ParseInLocation(formTimeLayout, c.FormValue(startParam), time.Local)
+	if err != nil {
+		return c.NoContent(http.StatusBadRequest)
+	}
+	finish, err := time.ParseInLocation(formTimeLayout, c.FormValue(finishParam), time.Local)
+	if err != nil {
+		return c.NoContent(http.StatusBadRequest)
+	}
+	quality := 0
+	if v := c.FormValue(qualityParam); v != "" {
+		if quality, err = strconv.Atoi(v); err != nil {
+			return c.NoContent(http.StatusBadRequest)
+		}
+	}
+	s := tsmongo.SleepSession{Start: start, Finish: finish, Quality: quality}
+	switch err := tsmongo.NewSleepService(requestStore(c), c.FormValue(roomParam)).LogContext(c.Request().Context(), s); err {
+	case nil:
+		return c.Redirect(http.StatusFound, sleepPath)
+	case tsmongo.ErrInvalidSleepSession:
+		return c.NoContent(http.StatusBadRequest)
+	default:
+		c.Logger().Errorf("[/restricted/sleep] %q\n", err)
+		return c.NoContent(storeErrorStatus(err))
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/danielfireman/temp-to-go/server/tsmongo"
+	"github.com/labstack/echo"
+)
+
+const storeContextKey = "store"
+
+// storeMiddleware sets the store handlers use through requestStore. Requests get their own copy
+// of mongo sessions from the pool, which are released when the request is over; other stores
+// (e.g. a FileStore) are shared. Requests waiting for a copy longer than their deadline are
+// answered with 503.
+func storeMiddleware(store tsmongo.Store, pool *tsmongo.SessionPool) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if pool == nil {
+				c.Set(storeContextKey, store)
+				return next(c)
+			}
+			s, err := pool.Acquire(c.Request().Context())
+			if err != nil {
+				c.Logger().Errorf("[store] %q (%d sessions in use)\n", err, pool.InUse())
+				return c.NoContent(http.StatusServiceUnavailable)
+			}
+			defer pool.Release(s)
+			c.Set(storeContextKey, s)
+			return next(c)
+		}
+	}
+}
+
+// requestStore returns the store of the request, set by storeMiddleware.
+func requestStore(c echo.Context) tsmongo.Store {
+	return c.Get(storeContextKey).(tsmongo.Store)
+}
+
+// storeErrorStatus returns the status of responses to requests whose store operations failed:
+// 503 if the database could not be reached (so clients retry later), 500 otherwise.
+func storeErrorStatus(err error) int {
+	if tsmongo.IsUnreachable(err) {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
+
+type healthHandler struct {
+	session *tsmongo.Session
+}
+
+// handle pings the database, answering 503 if it can not be reached.
+func (h *healthHandler) handle(c echo.Context) error {
+	if h.session == nil { // Embedded stores are always reachable.
+		return c.JSON(http.StatusOK, healthResponse{"ok"})
+	}
+	if err := h.session.PingContext(c.Request().Context()); err != nil {
+		c.Logger().Errorf("[/health] %q\n", err)
+		return c.JSON(http.StatusServiceUnavailable, healthResponse{"unreachable"})
+	}
+	return c.JSON(http.StatusOK, healthResponse{"ok"})
+}
+
+type healthResponse struct {
+	Database string `json:"database"`
+}
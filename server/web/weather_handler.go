@@ -8,17 +8,16 @@ import (
 	"github.com/labstack/echo"
 )
 
+const timezoneHeader = "TZ"
+
 type weatherHandler struct {
-	weatherService *tsmongo.WeatherService
 }
 
-const timezoneHeader = "TZ"
-
 func (h *weatherHandler) handle(c echo.Context) error {
-	var err error
-	ws, err := h.weatherService.Fetch(time.Now().Add(-25*time.Hour), time.Now())
+	ws, err := tsmongo.NewWeatherService(requestStore(c)).FetchContext(c.Request().Context(), time.Now().Add(-25*time.Hour), time.Now())
 	if err != nil {
-		return c.NoContent(http.StatusInternalServerError)
+		c.Logger().Errorf("[/restricted/weather] %q\n", err)
+		return c.NoContent(storeErrorStatus(err))
 	}
 	loc, err := time.LoadLocation(c.Request().Header.Get(timezoneHeader))
 	if err != nil {
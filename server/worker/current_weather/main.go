@@ -1,13 +1,17 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
+	"time"
 
 	"github.com/danielfireman/temp-to-go/server/tsmongo"
 	"github.com/danielfireman/temp-to-go/server/weather"
 )
 
+const dbTimeout = time.Minute
+
 func main() {
 	owmKey := os.Getenv("OWM_API_KEY")
 	if owmKey == "" {
@@ -19,19 +23,22 @@ func main() {
 	if mgoURI == "" {
 		log.Fatalf("Invalid MONGODB_URI: %s", mgoURI)
 	}
-	session, err := tsmongo.Dial(mgoURI)
+	session, err := tsmongo.Open(mgoURI)
 	if err != nil {
-		log.Fatalf("Error connecting to status DB: %s", mgoURI)
+		log.Fatalf("Error connecting to status DB: %q", err)
 	}
 	defer session.Close()
 	weatherService := tsmongo.NewWeatherService(session)
 	log.Println("Connected to StatusDB.")
 
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
 	ws, err := weatherClient.Current()
 	if err != nil {
 		log.Fatalf("Error retrieving current weather: %q", err)
 	}
-	if err := weatherService.Update(ws); err != nil {
+	if err := weatherService.UpdateContext(ctx, ws); err != nil {
 		log.Fatalf("Error updating status with current weather: %q", err)
 	}
 	log.Printf("Succefully updated status with current weather: %+v\n", ws)
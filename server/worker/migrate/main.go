@@ -0,0 +1,47 @@
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/danielfireman/temp-to-go/server/tsmongo"
+	"github.com/globalsign/mgo"
+)
+
+// Applies pending schema migrations to the timeseries mongo database and creates its indexes.
+func main() {
+	mgoURI := os.Getenv("MONGODB_URI")
+	if mgoURI == "" {
+		log.Fatalf("Invalid MONGODB_URI: %s", mgoURI)
+	}
+	// Not using tsmongo.Dial, which applies the migrations without reporting them.
+	info, err := mgo.ParseURL(mgoURI)
+	if err != nil {
+		log.Fatalf("Invalid MONGODB_URI: %q", err)
+	}
+	s, err := mgo.DialWithInfo(info)
+	if err != nil {
+		log.Fatalf("Error connecting to status DB: %q", err)
+	}
+	defer s.Close()
+	session := tsmongo.NewSession(s, info.Database)
+	defer session.Close()
+	log.Println("Connected to StatusDB.")
+
+	from, err := session.SchemaVersion()
+	if err != nil {
+		log.Fatalf("Error fetching schema version: %q", err)
+	}
+	results, err := session.Migrate()
+	for _, r := range results {
+		log.Printf("Applied migration %d (%s): %d documents changed.\n", r.Version, r.Description, r.Changed)
+	}
+	if err != nil {
+		log.Fatalf("Error migrating: %q", err)
+	}
+	to, err := session.SchemaVersion()
+	if err != nil {
+		log.Fatalf("Error fetching schema version: %q", err)
+	}
+	log.Printf("Schema version: %d -> %d. Indexes are up to date.\n", from, to)
+}
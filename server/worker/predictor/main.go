@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 	"time"
@@ -9,39 +10,67 @@ import (
 	"github.com/sajari/regression"
 )
 
+const dbTimeout = 5 * time.Minute
+
 func main() {
 	mgoURI := os.Getenv("MONGODB_URI")
 	if mgoURI == "" {
 		log.Fatalf("Invalid MONGODB_URI: %s", mgoURI)
 	}
-	session, err := tsmongo.Dial(mgoURI)
+	session, err := tsmongo.Open(mgoURI)
 	if err != nil {
-		log.Fatalf("Error connecting to status DB: %s", mgoURI)
+		log.Fatalf("Error connecting to status DB: %q", err)
 	}
 	log.Println("Connected to timeseries mongo.")
 	defer session.Close()
 
-	bedroomService := tsmongo.NewBedroomService(session)
+	bedroomService := tsmongo.NewBedroomService(session, tsmongo.DefaultRoom)
 	weatherService := tsmongo.NewWeatherService(session)
-	fanService := tsmongo.NewFanService(session)
+	fanService := tsmongo.NewFanService(session, tsmongo.DefaultRoom)
 	forecastService := tsmongo.NewForecastService(session)
 	predictionService := tsmongo.NewPredictionService(session)
 
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
 	// Get the last week worth of data.
 	st := time.Now().Add(-7 * 24 * time.Hour)
 	et := time.Now()
 
 	r := newRegression()
-	r.Train(getTrainSet(st, et, weatherService, fanService, bedroomService)...)
+	trainSet := getTrainSet(ctx, session, st, et, weatherService, fanService, bedroomService)
+	r.Train(trainSet...)
 
 	// Run model.
-	r.Run()
+	if err := r.Run(); err != nil {
+		log.Fatalf("Error training the model: %q", err)
+	}
 
-	// Predict the next 24 hours and updates the database.
-	predictions := predict(r, et, forecastService)
-	if err := predictionService.Update(predictions...); err != nil {
+	// Predict the next 24 hours and updates the database, along with the run metadata.
+	predictions := predict(ctx, r, et, forecastService)
+	run, err := predictionService.UpdateRunContext(ctx, newRun(r, st, et, len(trainSet)), predictions...)
+	if err != nil {
 		log.Fatalf("Error updating predictions:%q", err)
 	}
+	log.Printf("Stored %d predictions of run %d (R2:%f).\n", len(predictions), run.Version, run.R2)
+}
+
+// coeffNames names the model coefficients: the offset and then the variables, in order.
+var coeffNames = []string{"offset", "weather_temp", "fan_status"}
+
+func newRun(r regression.Regression, st, et time.Time, points int) tsmongo.PredictionRun {
+	coeffs := make(map[string]float64)
+	for i, n := range coeffNames {
+		coeffs[n] = r.Coeff(i)
+	}
+	return tsmongo.PredictionRun{
+		GeneratedAt:    time.Now(),
+		TrainStart:     st,
+		TrainFinish:    et,
+		TrainingPoints: points,
+		Coefficients:   coeffs,
+		R2:             r.R2,
+	}
 }
 
 func newRegression() regression.Regression {
@@ -64,14 +93,15 @@ func predOrDie(r regression.Regression, w, f float64) float64 {
 	return p
 }
 
-func predict(r regression.Regression, et time.Time, forecastService *tsmongo.ForecastService) []tsmongo.Prediction {
-	forecast, err := forecastService.Fetch(et, et.Add(24*time.Hour))
+func predict(ctx context.Context, r regression.Regression, et time.Time, forecastService *tsmongo.ForecastService) []tsmongo.Prediction {
+	forecast, err := forecastService.FetchContext(ctx, et, et.Add(24*time.Hour))
 	if err != nil {
 		log.Fatal(err)
 	}
 	var predictions []tsmongo.Prediction
 	for _, f := range forecast {
 		predictions = append(predictions, tsmongo.Prediction{
+			Timestamp:   f.Timestamp,
 			TempFanOff:  predOrDie(r, f.Temp, 0),
 			TempFanLow:  predOrDie(r, f.Temp, 1),
 			TempFanHigh: predOrDie(r, f.Temp, 2),
@@ -80,68 +110,41 @@ func predict(r regression.Regression, et time.Time, forecastService *tsmongo.For
 	return predictions
 }
 
-func getTrainSet(st, et time.Time, weatherService *tsmongo.WeatherService, fanService *tsmongo.FanService, bedroomService *tsmongo.BedroomService) regression.DataPoints {
-	bs, err := bedroomService.FetchState(st, et)
-	if err != nil {
-		log.Fatalf("Error fetching past bedroom temperature: %q", err)
-	}
-	if len(bs) == 0 {
-		log.Fatalf("Can not predict without any bedroom temperature.")
-	}
-	ws, err := weatherService.Fetch(st, et)
-	if err != nil {
-		log.Fatalf("Error fetching past weather: %q", err)
-	}
-	fs, err := fanService.FetchState(st, et)
+// Missing weather observations (e.g. the current weather worker missed a run) are interpolated,
+// as long as the gap is not too long.
+const maxWeatherGap = 3 * time.Hour
+
+func getTrainSet(ctx context.Context, store tsmongo.Store, st, et time.Time, weatherService *tsmongo.WeatherService, fanService *tsmongo.FanService, bedroomService *tsmongo.BedroomService) regression.DataPoints {
+	rows, err := tsmongo.Join(ctx, store, tsmongo.JoinQuery{
+		Start:  st,
+		Finish: et,
+		Step:   time.Hour,
+		Columns: []tsmongo.Column{
+			bedroomService.Column(tsmongo.FillNone, 0),
+			weatherService.Column(tsmongo.FillLinear, maxWeatherGap),
+			// The fan status holds until it is changed.
+			fanService.Column(tsmongo.FillPrevious, 0),
+		},
+	})
 	if err != nil {
-		log.Fatalf("Error fetching fan state: %q", err)
+		log.Fatalf("Error fetching train set: %q", err)
 	}
-	wsMap := make(map[time.Time]float64)
-	for _, s := range ws {
-		wsMap[s.Timestamp] = s.Temp
-	}
-	fsMap := fillFanState(fs, st, et)
 	var trainSet regression.DataPoints
-	for _, b := range bs {
-		// Only consider valid for the train set there is a full tuple.
-		t := b.Timestamp
-		f, fok := fsMap[t]
-		w, wok := wsMap[t]
-		if fok && wok {
-			trainSet = append(trainSet, regression.DataPoint(b.Temperature, predInputs(w, f)))
+	count := 0
+	for _, r := range rows {
+		bedroom, weather, fan := r.Cells[0], r.Cells[1], r.Cells[2]
+		if !bedroom.Valid {
+			continue
 		}
-	}
-	return trainSet
-}
-
-func fillFanState(fs []tsmongo.FanState, st, et time.Time) map[time.Time]float64 {
-	m := make(map[time.Time]float64)
-	// First populate the map with what we have.
-	for _, s := range fs {
-		m[s.Timestamp] = fanStatusToFloat(s.Status)
-	}
-	// Fill the map, always using the last known status.
-	lastStatus := float64(0)
-	if len(fs) > 0 {
-		lastStatus = fanStatusToFloat(fs[0].Status)
-	}
-	for currTime := st.In(time.UTC); currTime.Before(et); currTime = currTime.Add(1 * time.Hour) {
-		if val, ok := m[currTime]; ok {
-			lastStatus = val
-		} else {
-			m[currTime] = lastStatus
+		count++
+		// Only consider valid for the train set there is a full tuple. The fan is considered off
+		// before its first known status.
+		if weather.Valid {
+			trainSet = append(trainSet, regression.DataPoint(bedroom.Value, predInputs(weather.Value, fan.Value)))
 		}
 	}
-	return m
-}
-
-func fanStatusToFloat(s tsmongo.FanStatus) float64 {
-	switch s {
-	case tsmongo.FanLowSpeed:
-		return 1
-	case tsmongo.FanHighSpeed:
-		return 2
-	default:
-		return 0
+	if count == 0 {
+		log.Fatalf("Can not predict without any bedroom temperature.")
 	}
+	return trainSet
 }
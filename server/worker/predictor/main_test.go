@@ -1,38 +1,21 @@
 package main
 
 import (
-	"io/ioutil"
-	"os"
+	"context"
 	"testing"
 	"time"
 
 	"github.com/danielfireman/temp-to-go/server/tsmongo"
 	"github.com/danielfireman/temp-to-go/server/weather"
-	"github.com/globalsign/mgo/dbtest"
 )
 
-var mongoDB dbtest.DBServer
-
 func TestPredictor(t *testing.T) {
-	tempDir, _ := ioutil.TempDir("", "predictor_testing")
-	defer func() { os.RemoveAll(tempDir) }()
-
-	mongoDB.SetPath(tempDir)
-	defer mongoDB.Stop()
-	defer mongoDB.Wipe()
-
-	s := mongoDB.Session()
-	defer s.Close()
-	dbNames, _ := s.DatabaseNames()
-	dbName := dbNames[0]
-
-	session := tsmongo.NewSession(s, dbName)
-	defer session.Close()
+	session := tsmongo.NewMemStore()
 
 	startTime := time.Now()
 	endTime := startTime.Add(12 * time.Hour)
 
-	bs := tsmongo.NewBedroomService(session)
+	bs := tsmongo.NewBedroomService(session, tsmongo.DefaultRoom)
 	bs.UpdateTemperature(startTime.Add(1*time.Hour), 27)
 	bs.UpdateTemperature(startTime.Add(2*time.Hour), 28)
 	bs.UpdateTemperature(startTime.Add(3*time.Hour), 25) // User switches the fan on (high).
@@ -46,7 +29,7 @@ func TestPredictor(t *testing.T) {
 	bs.UpdateTemperature(startTime.Add(11*time.Hour), 26)
 	bs.UpdateTemperature(startTime.Add(12*time.Hour), 27) // User switches the fan off.
 
-	fs := tsmongo.NewFanService(session)
+	fs := tsmongo.NewFanService(session, tsmongo.DefaultRoom)
 	fs.UpdateStatus(startTime.Add(3*time.Hour), tsmongo.FanHighSpeed)
 	fs.UpdateStatus(startTime.Add(5*time.Hour), tsmongo.FanOff)
 	fs.UpdateStatus(startTime.Add(9*time.Hour), tsmongo.FanLowSpeed)
@@ -79,9 +62,9 @@ func TestPredictor(t *testing.T) {
 	fcs := tsmongo.NewForecastService(session)
 	fcs.Update(forecasts...)
 	r := newRegression()
-	r.Train(getTrainSet(startTime, endTime, ws, fs, bs)...)
+	r.Train(getTrainSet(context.Background(), session, startTime, endTime, ws, fs, bs)...)
 	r.Run()
-	predictions := predict(r, endTime, fcs)
+	predictions := predict(context.Background(), r, endTime, fcs)
 	if len(predictions) != len(forecasts) {
 		t.Errorf("len(predictions) want:%d got:%d", len(forecasts), len(predictions))
 	}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/danielfireman/temp-to-go/server/tsmongo"
+)
+
+const dbTimeout = 30 * time.Minute
+
+// Retention policies are configured per series through RETENTION_<SERIES> environment
+// variables, for instance:
+//
+//	RETENTION_BEDROOM="raw=30d,rollup=730d"  # Keep raw data 30 days, then daily rollups for 2 years.
+//	RETENTION_PRED="raw=7d"                  # Delete predictions after a week.
+//
+// RETENTION_TZ sets the timezone rollup windows are aligned to (defaults to UTC). Series
+// without a policy are kept forever.
+func main() {
+	mgoURI := os.Getenv("MONGODB_URI")
+	if mgoURI == "" {
+		log.Fatalf("Invalid MONGODB_URI: %s", mgoURI)
+	}
+	loc, err := time.LoadLocation(os.Getenv("RETENTION_TZ"))
+	if err != nil {
+		log.Fatalf("Invalid RETENTION_TZ: %q", err)
+	}
+	policies := make(map[string]tsmongo.RetentionPolicy)
+	for _, field := range tsmongo.Fields {
+		env := "RETENTION_" + strings.ToUpper(field)
+		v := os.Getenv(env)
+		if v == "" {
+			continue
+		}
+		p, err := tsmongo.ParseRetentionPolicy(v)
+		if err != nil {
+			log.Fatalf("Invalid %s: %q", env, err)
+		}
+		p.Location = loc
+		policies[field] = p
+	}
+
+	session, err := tsmongo.Open(mgoURI)
+	if err != nil {
+		log.Fatalf("Error connecting to status DB: %q", err)
+	}
+	defer session.Close()
+	log.Println("Connected to StatusDB.")
+
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	now := time.Now()
+	for _, field := range tsmongo.Fields {
+		p, ok := policies[field]
+		if !ok {
+			log.Printf("%s: no retention policy, keeping all data.\n", field)
+			continue
+		}
+		// The policy applies to the series of every room.
+		series, err := session.ListSeriesContext(ctx, field)
+		if err != nil {
+			log.Fatalf("Error listing %s series: %q", field, err)
+		}
+		for _, s := range series {
+			report, err := tsmongo.ApplyRetention(ctx, session, s, p, now)
+			if err != nil {
+				log.Fatalf("Error applying retention policy to %s: %q", s, err)
+			}
+			log.Println(report)
+		}
+	}
+}
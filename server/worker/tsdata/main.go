@@ -0,0 +1,390 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/danielfireman/temp-to-go/server/tsmongo"
+)
+
+const dbTimeout = 2 * time.Hour
+
+const usage = `Exports and imports timeseries data, for backups or moving data between databases,
+summarizes the stored series and edits their records.
+
+Usage:
+	tsdata export [-format jsonl|csv] [-start TIME] [-finish TIME] [-o FILE] [SERIES...]
+	tsdata import [-format jsonl|csv] [-dry-run] [-batch N] [FILE]
+	tsdata stats [-start TIME] [-finish TIME] [-gaps N] [SERIES...]
+	tsdata delete -start TIME -finish TIME [-actor NAME] -reason TEXT SERIES
+	tsdata correct -at TIME -value JSON [-actor NAME] -reason TEXT SERIES
+	tsdata audit [-start TIME] [-finish TIME] SERIES
+
+SERIES selects a field (e.g. "bedroom", all rooms) or the series of a room (e.g.
+"bedroom@kitchen"), optionally followed by its own time range (e.g.
+"fan@default=2018-07-01..2018-08-01"). All series are exported (or summarized) if none is
+selected. Stats count the records, hours with records and missing hours of each series, and
+list its largest gaps.
+Delete removes the records of a single series (e.g. "bedroom@kitchen", or "weather" if the
+field is stored for a single room) within the range (inclusive), and correct changes the value of the record
+taken at a moment (e.g. "-value 24.5"). The original values are kept in the audit trail of
+the series, which is printed by audit.
+TIME is either a date (2006-01-02) or a RFC3339 timestamp. Export writes to the standard
+output and import reads from the standard input, unless a file is specified.
+
+The database is specified by the MONGODB_URI environment variable, which may also be a
+file:// URI (see tsmongo.Open).
+`
+
+func main() {
+	log.SetFlags(0)
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	mgoURI := os.Getenv("MONGODB_URI")
+	if mgoURI == "" {
+		log.Fatalf("Invalid MONGODB_URI: %s", mgoURI)
+	}
+	switch os.Args[1] {
+	case "export":
+		export(mgoURI, os.Args[2:])
+	case "import":
+		importData(mgoURI, os.Args[2:])
+	case "stats":
+		stats(mgoURI, os.Args[2:])
+	case "delete", "correct", "audit":
+		edit(mgoURI, os.Args[1], os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
+
+func export(mgoURI string, args []string) {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	format := flags.String("format", "jsonl", "Output format: jsonl or csv.")
+	start := flags.String("start", "", "Start of the exported range (defaults to the beginning).")
+	finish := flags.String("finish", "", "End of the exported range (defaults to now).")
+	out := flags.String("o", "", "Output file (defaults to the standard output).")
+	flags.Parse(args)
+
+	f, err := tsmongo.ParseFormat(*format)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defaultRange, err := parseRange(*start, *finish)
+	if err != nil {
+		log.Fatalf("Invalid range: %q", err)
+	}
+	w := io.Writer(os.Stdout)
+	if *out != "" {
+		file, err := os.Create(*out)
+		if err != nil {
+			log.Fatalf("Error creating output file: %q", err)
+		}
+		defer file.Close()
+		w = file
+	}
+
+	store, err := tsmongo.Open(mgoURI)
+	if err != nil {
+		log.Fatalf("Error connecting to status DB: %q", err)
+	}
+	defer store.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	ranges, err := selectRanges(ctx, store, flags.Args(), defaultRange)
+	if err != nil {
+		log.Fatal(err)
+	}
+	n, err := tsmongo.Export(ctx, store, w, f, ranges...)
+	if err != nil {
+		log.Fatalf("Error exporting: %q", err)
+	}
+	log.Printf("Exported %d records of %d series.\n", n, len(ranges))
+}
+
+func importData(mgoURI string, args []string) {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	format := flags.String("format", "jsonl", "Input format: jsonl or csv.")
+	dryRun := flags.Bool("dry-run", false, "Validate the records without storing them.")
+	batch := flags.Int("batch", 1000, "Maximum number of records upserted at once.")
+	flags.Parse(args)
+
+	f, err := tsmongo.ParseFormat(*format)
+	if err != nil {
+		log.Fatal(err)
+	}
+	r := io.Reader(os.Stdin)
+	if flags.NArg() > 0 {
+		file, err := os.Open(flags.Arg(0))
+		if err != nil {
+			log.Fatalf("Error opening input file: %q", err)
+		}
+		defer file.Close()
+		r = file
+	}
+
+	store, err := tsmongo.Open(mgoURI)
+	if err != nil {
+		log.Fatalf("Error connecting to status DB: %q", err)
+	}
+	defer store.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	var lastProgress time.Time
+	report, err := tsmongo.Import(ctx, store, r, f, tsmongo.ImportOptions{
+		DryRun:    *dryRun,
+		BatchSize: *batch,
+		Progress: func(r tsmongo.ImportReport) {
+			if time.Since(lastProgress) >= time.Second {
+				log.Printf("Imported %s...\n", r)
+				lastProgress = time.Now()
+			}
+		},
+	})
+	if err != nil {
+		log.Fatalf("Error importing after %s: %q", report, err)
+	}
+	if *dryRun {
+		log.Printf("Dry run: validated %s, nothing was stored.\n", report)
+		return
+	}
+	log.Printf("Imported %s.\n", report)
+}
+
+func stats(mgoURI string, args []string) {
+	flags := flag.NewFlagSet("stats", flag.ExitOnError)
+	start := flags.String("start", "", "Start of the summarized range (defaults to the first record).")
+	finish := flags.String("finish", "", "End of the summarized range (defaults to now).")
+	gaps := flags.Int("gaps", 3, "Number of largest gaps listed per series.")
+	flags.Parse(args)
+
+	defaultRange, err := parseRange(*start, *finish)
+	if err != nil {
+		log.Fatalf("Invalid range: %q", err)
+	}
+
+	store, err := tsmongo.Open(mgoURI)
+	if err != nil {
+		log.Fatalf("Error connecting to status DB: %q", err)
+	}
+	defer store.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	ranges, err := selectRanges(ctx, store, flags.Args(), defaultRange)
+	if err != nil {
+		log.Fatal(err)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "SERIES\tRECORDS\tHOURS\tFIRST\tLAST\tAGE\tMISSING\tSIZE\tLARGEST GAPS")
+	for _, r := range ranges {
+		st, err := tsmongo.Stats(ctx, store, r.Series, tsmongo.StatsQuery{Start: r.Start, Finish: r.Finish, MaxGaps: *gaps})
+		if err != nil {
+			log.Fatalf("Error summarizing %s: %q", r.Series, err)
+		}
+		var gs []string
+		for _, g := range st.Gaps {
+			gs = append(gs, fmt.Sprintf("%s (%dh)", g.Start.Format("2006-01-02 15h"), g.Hours()))
+		}
+		first, last, age := "-", "-", "-"
+		if st.Count > 0 {
+			first, last = st.First.Format(time.RFC3339), st.Last.Format(time.RFC3339)
+			age = st.LastAge.String()
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%s\t%s\t%dh\t%dKiB\t%s\n", r.Series, st.Count, st.Hours, first, last, age, st.MissingHours, (st.Size+1023)/1024, strings.Join(gs, ", "))
+	}
+	w.Flush()
+}
+
+func edit(mgoURI string, cmd string, args []string) {
+	flags := flag.NewFlagSet(cmd, flag.ExitOnError)
+	start := flags.String("start", "", "Start of the range.")
+	finish := flags.String("finish", "", "End of the range (defaults to now).")
+	at := flags.String("at", "", "Timestamp of the corrected record.")
+	value := flags.String("value", "", "Corrected value, as JSON.")
+	actor := flags.String("actor", os.Getenv("USER"), "Who is editing the records.")
+	reason := flags.String("reason", "", "Why the records are edited.")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		log.Fatalf("%s acts on a single series.", cmd)
+	}
+	r, err := parseRange(*start, *finish)
+	if err != nil {
+		log.Fatalf("Invalid range: %q", err)
+	}
+	e := tsmongo.Edit{Actor: *actor, Reason: *reason}
+	if cmd != "audit" && e.Reason == "" {
+		log.Fatalf("%s needs a reason.", cmd)
+	}
+
+	store, err := tsmongo.Open(mgoURI)
+	if err != nil {
+		log.Fatalf("Error connecting to status DB: %q", err)
+	}
+	defer store.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+	series, err := resolveSeries(ctx, store, flags.Arg(0))
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	switch cmd {
+	case "delete":
+		if *start == "" {
+			log.Fatal("delete needs the start of the range.")
+		}
+		n, err := tsmongo.DeleteRange(ctx, store, series, r.start, r.finish, e)
+		if err != nil {
+			log.Fatalf("Error deleting records of %s: %q", series, err)
+		}
+		log.Printf("Deleted %d records of %s.\n", n, series)
+	case "correct":
+		t, err := parseTime(*at)
+		if err != nil {
+			log.Fatalf("Invalid timestamp: %q", err)
+		}
+		var v interface{}
+		if err := json.Unmarshal([]byte(*value), &v); err != nil {
+			log.Fatalf("Invalid value: %q", err)
+		}
+		switch err := tsmongo.Correct(ctx, store, series, t, v, e); err {
+		case nil:
+			log.Printf("Corrected the record of %s at %s.\n", series, t.Format(time.RFC3339))
+		case tsmongo.ErrNotFound:
+			log.Fatalf("No record of %s at %s.", series, t.Format(time.RFC3339))
+		default:
+			log.Fatalf("Error correcting the record of %s: %q", series, err)
+		}
+	case "audit":
+		trail, err := tsmongo.AuditTrail(ctx, store, series, r.start, r.finish)
+		if err != nil {
+			log.Fatalf("Error reading the audit trail of %s: %q", series, err)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "EDITED\tOP\tRECORD\tORIGINAL\tVALUE\tACTOR\tREASON")
+		for _, a := range trail {
+			v := "-"
+			if a.Value != nil {
+				v = fmt.Sprint(a.Value)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%v\t%s\t%s\t%s\n", a.Edited.Format(time.RFC3339), a.Op, a.Timestamp.Format(time.RFC3339), a.Original, v, a.Actor, a.Reason)
+		}
+		w.Flush()
+	}
+}
+
+// resolveSeries resolves a series selector (e.g. "bedroom@kitchen"). Without a room, the room
+// the field is stored for (along with its audit trail) is used, which must be a single one
+// (e.g. the default room for bedroom, none for weather).
+func resolveSeries(ctx context.Context, store tsmongo.Store, sel string) (tsmongo.Series, error) {
+	if i := strings.Index(sel, "@"); i >= 0 {
+		return tsmongo.Series{Field: sel[:i], Room: sel[i+1:]}, nil
+	}
+	all, err := store.ListSeriesContext(ctx, "")
+	if err != nil {
+		return tsmongo.Series{}, fmt.Errorf("Error listing series: %q", err)
+	}
+	audit := tsmongo.AuditSeries(tsmongo.Series{Field: sel}).Field
+	var rooms []string
+	seen := make(map[string]bool)
+	for _, s := range all {
+		if (s.Field == sel || s.Field == audit) && !seen[s.Room] {
+			seen[s.Room] = true
+			rooms = append(rooms, s.Room)
+		}
+	}
+	switch {
+	case len(rooms) == 0:
+		return tsmongo.Series{}, fmt.Errorf("No series matching %q.", sel)
+	case len(rooms) > 1:
+		return tsmongo.Series{}, fmt.Errorf("Series %q is stored for several rooms (%s), select one of them (e.g. %q).", sel, strings.Join(rooms, ", "), sel+"@"+rooms[0])
+	}
+	return tsmongo.Series{Field: sel, Room: rooms[0]}, nil
+}
+
+type timeRange struct {
+	start, finish time.Time
+}
+
+func parseRange(start, finish string) (timeRange, error) {
+	r := timeRange{finish: time.Now()}
+	var err error
+	if start != "" {
+		if r.start, err = parseTime(start); err != nil {
+			return r, err
+		}
+	}
+	if finish != "" {
+		if r.finish, err = parseTime(finish); err != nil {
+			return r, err
+		}
+	}
+	return r, nil
+}
+
+func parseTime(s string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+// selectRanges lists the stored series matching the selectors, along with their time ranges.
+func selectRanges(ctx context.Context, store tsmongo.Store, selectors []string, defaultRange timeRange) ([]tsmongo.ExportRange, error) {
+	all, err := store.ListSeriesContext(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("Error listing series: %q", err)
+	}
+	if len(selectors) == 0 {
+		selectors = []string{""}
+	}
+	var ret []tsmongo.ExportRange
+	for _, sel := range selectors {
+		r := defaultRange
+		if i := strings.Index(sel, "="); i >= 0 {
+			bounds := strings.SplitN(sel[i+1:], "..", 2)
+			if len(bounds) != 2 {
+				return nil, fmt.Errorf("Invalid series range: %q", sel)
+			}
+			if r, err = parseRange(bounds[0], bounds[1]); err != nil {
+				return nil, fmt.Errorf("Invalid series range %q: %q", sel, err)
+			}
+			sel = sel[:i]
+		}
+		field, room := sel, ""
+		byRoom := false
+		if i := strings.Index(sel, "@"); i >= 0 {
+			field, room, byRoom = sel[:i], sel[i+1:], true
+		}
+		found := false
+		for _, s := range all {
+			if (field == "" || s.Field == field) && (!byRoom || s.Room == room) {
+				ret = append(ret, tsmongo.ExportRange{Series: s, Start: r.start, Finish: r.finish})
+				found = true
+			}
+		}
+		if !found {
+			log.Printf("No series matching %q.\n", sel)
+		}
+	}
+	return ret, nil
+}
@@ -1,13 +1,17 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
+	"time"
 
 	"github.com/danielfireman/temp-to-go/server/tsmongo"
 	"github.com/danielfireman/temp-to-go/server/weather"
 )
 
+const dbTimeout = time.Minute
+
 func main() {
 	owmKey := os.Getenv("OWM_API_KEY")
 	if owmKey == "" {
@@ -19,20 +23,24 @@ func main() {
 	if mgoURI == "" {
 		log.Fatalf("Invalid MONGODB_URI: %s", mgoURI)
 	}
-	session, err := tsmongo.Dial(mgoURI)
+	session, err := tsmongo.Open(mgoURI)
 	if err != nil {
-		log.Fatalf("Error connecting to status DB: %s", mgoURI)
+		log.Fatalf("Error connecting to status DB: %q", err)
 	}
 	defer session.Close()
 	forecastService := tsmongo.NewForecastService(session)
 	log.Println("Connected to StatusDB.")
 
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	issued := time.Now()
 	ws, err := weatherClient.Forecast()
 	if err != nil {
 		log.Fatalf("Error retrieving weather forecast weather: %q", err)
 	}
-	if err := forecastService.Update(ws...); err != nil {
+	if err := forecastService.UpdateIssuedContext(ctx, issued, ws...); err != nil {
 		log.Fatalf("Error updating status with weather forecast: %q", err)
 	}
-	log.Printf("Succefully updated status with weather forecast: %+v\n", ws)
+	log.Printf("Succefully updated status with weather forecast issued at %s: %+v\n", issued, ws)
 }